@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"io/fs"
 	"log/slog"
+	"net"
 	"net/http"
 	_ "net/http/pprof"
 	"os"
@@ -65,9 +66,28 @@ var (
 			" Please see project README for more information and the full list of tools.",
 	).Default("all").Strings()
 
+	flagMcpDisableTools = kingpin.Flag(
+		"mcp.disable-tools",
+		"List of mcp tools to disable. Disabled tools are removed from the set of tools selected by --mcp.tools"+
+			" and --prometheus.backend, so disabling a tool always wins over enabling it, even for core tools.",
+	).Strings()
+
+	flagMcpOutputFormat = kingpin.Flag(
+		"mcp.output-format",
+		"Output format for tool responses ["+strings.Join(mcp.OutputFormats, ", ")+"]."+
+			" TOON (Token-Oriented Object Notation) may reduce token usage, YAML is easier for humans to read.",
+	).Default(mcp.OutputFormatJSON).Enum(mcp.OutputFormats...)
+
 	flagMcpToonOutputEnabled = kingpin.Flag(
 		"mcp.enable-toon-output",
-		"Enable Token-Oriented Object Notation (TOON) output for tools instead of JSON",
+		"Deprecated: use --mcp.output-format=toon. Enable Token-Oriented Object Notation (TOON) output for tools instead of JSON."+
+			" If set, overrides --mcp.output-format.",
+	).Default("false").Bool()
+
+	flagMcpStripHelpText = kingpin.Flag(
+		"mcp.strip-help-text",
+		"Omit metric help text from metadata tool responses to reduce token usage."+
+			" LLMs can override this on a per-tool-call basis via tool request arguments on supported tools.",
 	).Default("false").Bool()
 
 	flagMcpClientLogging = kingpin.Flag(
@@ -77,12 +97,49 @@ var (
 			" and to the MCP client, allowing LLMs to observe server activity.",
 	).Default("false").Bool()
 
+	flagMcpInstructionsFile = kingpin.Flag(
+		"mcp.instructions-file",
+		"Path to a file with operator-authored guidance for LLMs, e.g. which namespaces to query or which"+
+			" query patterns to prefer. It is read once at startup and exposed as the `prometheus://instructions`"+
+			" resource. If unset, a built-in default is used.",
+	).String()
+
+	flagMcpServerInstructions = kingpin.Flag(
+		"mcp.server-instructions",
+		"Operator rules appended to the server instructions sent to MCP clients during initialization, e.g."+
+			" \"never call delete_series\". Either inline text or `@` followed by the path of a file to read at startup."+
+			" The built-in instructions describing output truncation and admin tool gating are always included."+
+			" Unlike the `prometheus://instructions` resource from --mcp.instructions-file, which clients read on demand,"+
+			" these rules reach every client and take precedence over the resource.",
+	).String()
+
 	// TODO (@tjhop): change this to an enum?
 	flagMcpTransport = kingpin.Flag(
 		"mcp.transport",
-		"The type of transport to use for the MCP server [`stdio`, `http`].",
+		"The type of transport to use for the MCP server [`stdio`, `http`, `sse`, `unix`]."+
+			" The `sse` transport is deprecated by the MCP spec and only intended for older clients that don't support streamable HTTP.",
 	).Default("stdio").String()
 
+	flagMcpAuthToken = kingpin.Flag(
+		"mcp.auth-token",
+		"Bearer token MCP clients must send in the `Authorization` header to use the `/mcp` endpoint."+
+			" Requests without a matching token are rejected with 401 Unauthorized."+
+			" The header is not forwarded to Prometheus. Only applies to the `http` transport."+
+			" Mutually exclusive with --mcp.auth-token-file.",
+	).String()
+
+	flagMcpAuthTokenFile = kingpin.Flag(
+		"mcp.auth-token-file",
+		"Path to a file containing the bearer token for --mcp.auth-token. It is read once at startup.",
+	).String()
+
+	flagMcpUnixSocket = kingpin.Flag(
+		"mcp.unix-socket",
+		"Path of the Unix domain socket to listen on when --mcp.transport=unix."+
+			" MCP clients connect to the `/mcp` endpoint on the socket using the streamable HTTP transport."+
+			" The socket file is removed on shutdown.",
+	).String()
+
 	flagPrometheusBackend = kingpin.Flag(
 		"prometheus.backend",
 		"Customize the toolset for a specific Prometheus API compatible backend."+
@@ -91,31 +148,156 @@ var (
 
 	flagPrometheusURL = kingpin.Flag(
 		"prometheus.url",
-		"URL of the Prometheus instance to connect to",
-	).Default("http://127.0.0.1:9090").String()
+		"URL of the Prometheus instance to connect to. May be repeated as `name=url` to configure"+
+			" additional named backends that query tools can select with their `target` argument."+
+			" The unnamed URL, or else the first named one, is the default backend.",
+	).Default("http://127.0.0.1:9090").Strings()
+
+	flagPrometheusConfigPath = kingpin.Flag(
+		"prometheus.config-path",
+		"Path to the Prometheus configuration file on disk, for local deployments."+
+			" Required by the `config_pending_changes` and `config_diff` tools to compare the on-disk config against the loaded config.",
+	).String()
+
+	flagPrometheusRemoteReadURL = kingpin.Flag(
+		"prometheus.remote-read-url",
+		"URL of a remote read endpoint, e.g. http://127.0.0.1:9090/api/v1/read, used by the `remote_read` tool."+
+			" Requests use the --http.config client settings.",
+	).String()
 
 	flagPrometheusTimeout = kingpin.Flag(
 		"prometheus.timeout",
 		"Timeout for API calls to the Prometheus backend",
 	).Default("1m").Duration()
 
+	flagPrometheusMaxTimeout = kingpin.Flag(
+		"prometheus.max-timeout",
+		"Maximum timeout that tools with a `timeout` argument, such as `query` and `range_query`, may request for a"+
+			" single call. Larger values are capped. It is never lower than --prometheus.timeout.",
+	).Default("5m").Duration()
+
+	flagPrometheusMaxConcurrentRequests = kingpin.Flag(
+		"prometheus.max-concurrent-requests",
+		"Maximum number of concurrent requests to the Prometheus backend, shared by all MCP sessions."+
+			" Requests over the limit wait for a free slot until --prometheus.timeout expires."+
+			" To disable the limit, set to 0.",
+	).Default("0").Int()
+
+	flagPrometheusLookbackDelta = kingpin.Flag(
+		"prometheus.lookback-delta",
+		"How far back from the end time tools look when no start time is given, e.g. the default range of `range_query`."+
+			" Raise it for environments with long scrape intervals. A negative duration like -15m means the same as 15m.",
+	).Default("5m").Duration()
+
+	flagPrometheusRetries = kingpin.Flag(
+		"prometheus.retries",
+		"Number of times to retry an API call to the Prometheus backend after a transient error,"+
+			" i.e. a 5xx response or a connection error. Requests that change state, such as"+
+			" `reload`, are never retried. Retries stop once --prometheus.timeout would be exceeded."+
+			" To disable retries, set to 0.",
+	).Default("2").Int()
+
+	flagPrometheusRetryBackoff = kingpin.Flag(
+		"prometheus.retry-backoff",
+		"Base delay between retries of API calls to the Prometheus backend. It grows linearly with each attempt.",
+	).Default("500ms").Duration()
+
+	flagPrometheusUserAgent = kingpin.Flag(
+		"prometheus.user-agent",
+		"User-Agent header sent on requests to the Prometheus backend and Alertmanager."+
+			" The name of the tool making a request is appended as an `mcp-tool/<name>` product token,"+
+			" so access logs show which tool generated the traffic."+
+			" Defaults to `prometheus-mcp/<version>` with a link to the project.",
+	).String()
+
 	flagPrometheusTruncationLimit = kingpin.Flag(
 		"prometheus.truncation-limit",
-		"If enabled, this controls the maximum query response size in number of lines/entries provided to the LLM from the API response."+
+		"If enabled, this controls the maximum query response size in number of lines/entries (or bytes or estimated tokens, see --prometheus.truncation-mode) provided to the LLM from the API response."+
 			" LLMs can override truncation limits if needed on a per-tool-call basis via tool request arguments on supported tools."+
 			" To disable truncation limits, set to 0.",
 	).Default("0").Int()
 
+	flagPrometheusTruncationMode = kingpin.Flag(
+		"prometheus.truncation-mode",
+		"Unit used by truncation limits on query results ["+strings.Join(mcp.TruncationModes, ", ")+"]."+
+			" In bytes mode, results are cut on a UTF-8 character boundary."+
+			" In tokens mode, tokens are estimated as 4 characters each and results are cut at the last complete line that fits."+
+			" Tools returning lists of entries always truncate by entries.",
+	).Default(mcp.TruncationModeLines).Enum(mcp.TruncationModes...)
+
+	flagPrometheusTimezone = kingpin.Flag(
+		"prometheus.timezone",
+		"IANA time zone name, such as `Europe/Berlin` or `UTC`. If set, timestamps in the text output of the"+
+			" `query` and `range_query` tools are followed by a human-readable time in this zone."+
+			" This only affects how results are displayed, not the queries sent to Prometheus.",
+	).String()
+
+	flagPrometheusAllowEmptyMatchers = kingpin.Flag(
+		"prometheus.allow-empty-matchers",
+		"Allow the `series` tool to be called without matchers, enumerating all series in the time range."+
+			" Only useful for backends that support unmatched series enumeration. This is expensive for the backend,"+
+			" so results are always truncated and include a warning about the cost.",
+	).Default("false").Bool()
+
+	flagMimirTenant = kingpin.Flag(
+		"mimir.tenant",
+		"Tenant ID sent in the `X-Scope-OrgID` header on every request to the backend when"+
+			" --prometheus.backend=mimir. MCP clients using the HTTP transport can override it per"+
+			" request by sending their own `X-Scope-OrgID` header.",
+	).String()
+
+	flagMimirTenantsPath = kingpin.Flag(
+		"mimir.tenants-path",
+		"Endpoint listing the tenants of the cluster for the `list_tenants` tool when --prometheus.backend=mimir."+
+			" An absolute path is resolved against the root of the --prometheus.url, a full URL is used as is, e.g."+
+			" the `/admin/api/v3/tenants` endpoint of the Grafana Enterprise Metrics admin API.",
+	).Default(mcp.DefaultMimirTenantsPath).String()
+
+	flagPrometheusForwardHeaders = kingpin.Flag(
+		"prometheus.forward-headers",
+		"Name of a header to copy from the MCP client's HTTP request to requests to the backend, e.g."+
+			" `X-Scope-OrgID` for multi-tenant proxies. May be repeated. Only allow-listed headers are forwarded,"+
+			" all others are dropped. The `Authorization` header is always forwarded and can't be listed."+
+			" Only applies to the HTTP based transports.",
+	).Strings()
+
+	flagAlertmanagerURL = kingpin.Flag(
+		"alertmanager.url",
+		"URL of the Alertmanager used by the `list_silences`, `alertmanager_alerts`, `alertmanager_status`, and `create_silence` tools."+
+			" Requests use the --http.config client settings, Authorization headers forwarded by MCP clients are not sent to Alertmanager.",
+	).String()
+
 	flagHTTPConfig = kingpin.Flag(
 		"http.config",
 		"Path to config file to set Prometheus HTTP client options",
 	).String()
 
+	flagPrometheusTLSSkipVerify = kingpin.Flag(
+		"prometheus.tls-skip-verify",
+		"Disable TLS certificate verification for requests to Prometheus and the other backends, e.g. for"+
+			" self-signed certificates. Insecure, only use it for testing. Can't be combined with --http.config,"+
+			" set `insecure_skip_verify` in its `tls_config` instead.",
+	).Default("false").Bool()
+
+	flagHTTPMaxResponseBytes = kingpin.Flag(
+		"http.max-response-bytes",
+		"Maximum size in bytes of response bodies read from raw HTTP endpoints, such as the management API,"+
+			" Alertmanager, and Thanos `/api/v1/stores`. Larger responses fail with an error instead of being"+
+			" read into memory. To disable the limit, set to 0.",
+	).Default("0").Int64()
+
 	flagWebTelemetryPath = kingpin.Flag(
 		"web.telemetry-path",
 		"Path under which to expose metrics.",
 	).Default("/metrics").String()
 
+	flagWebCORSOrigins = kingpin.Flag(
+		"web.cors-origins",
+		"Comma-separated list of origins allowed to make cross-origin requests to the `/mcp` endpoint,"+
+			" such as `https://example.com`, or `*` to allow any origin. Required for browser-based MCP clients."+
+			" Only applies to the `http` transport. CORS is disabled when empty.",
+	).Default("").String()
+
 	flagWebMaxRequests = kingpin.Flag(
 		"web.max-requests",
 		"Maximum number of parallel scrape requests. Use 0 to disable.",
@@ -130,6 +312,26 @@ var (
 			" Docs: https://prometheus.io/docs/prometheus/latest/querying/api/#tsdb-admin-apis",
 	).Default("false").Bool()
 
+	flagRequireConfirmation = kingpin.Flag(
+		"dangerous.require-confirmation",
+		"Require the destructive `delete_series` and `clean_tombstones` tools to be called with a `confirm` argument"+
+			" matching a confirmation token. Calls without it fail with an error describing how to confirm."+
+			" If --dangerous.confirmation-token is unset, a random token is generated at startup and revealed in that error,"+
+			" forcing the LLM to confirm deliberately in a second call.",
+	).Default("false").Bool()
+
+	flagConfirmationToken = kingpin.Flag(
+		"dangerous.confirmation-token",
+		"Confirmation token for --dangerous.require-confirmation. Unlike a generated token, it is never revealed to the LLM,"+
+			" so the user has to provide it to confirm destructive operations. Prefer setting it through the environment variable.",
+	).String()
+
+	flagEnableSilenceTools = kingpin.Flag(
+		"dangerous.enable-alertmanager-silences",
+		"Enable and allow using the `create_silence` tool, which creates silences in the Alertmanager configured with --alertmanager.url."+
+			" This is dangerous, as silences suppress notifications for the alerts they match.",
+	).Default("false").Bool()
+
 	flagMcpKeepaliveInterval = kingpin.Flag(
 		"mcp.keepalive-interval",
 		"Interval for sending keepalive pings to connected MCP sessions."+
@@ -137,9 +339,15 @@ var (
 			" Most useful for HTTP transports to prevent idle connections from dropping.",
 	).Default("30s").Duration()
 
+	flagMcpProgressInterval = kingpin.Flag(
+		"mcp.progress-interval",
+		"Interval for sending progress notifications while a range query is running, to clients that request"+
+			" progress updates, so long queries don't appear hung. 0 disables progress notifications.",
+	).Default("5s").Duration()
+
 	flagMcpSessionTimeout = kingpin.Flag(
 		"mcp.session-timeout",
-		"Idle session timeout for HTTP transport MCP sessions.",
+		"Idle session timeout for HTTP and unix transport MCP sessions.",
 	).Default("10m").Duration()
 
 	flagDocsAutoUpdate = kingpin.Flag(
@@ -148,11 +356,57 @@ var (
 			" Checks every "+mcp.DocsUpdateInterval.String()+".",
 	).Default("false").Bool()
 
+	flagDocsIndexTimeout = kingpin.Flag(
+		"docs.index-timeout",
+		"Maximum time allowed to build the documentation search index. If exceeded, docs search is disabled while docs listing and reading remain available. 0 disables the timeout.",
+	).Default("1m").Duration()
+
+	flagDocsDir = kingpin.Flag(
+		"docs.dir",
+		"Directory to serve the Prometheus documentation from instead of the embedded copy, such as a network mount of the docs/ directory of the prometheus/docs repository.",
+	).String()
+
+	flagDocsReadRetries = kingpin.Flag(
+		"docs.read-retries",
+		"Number of times to retry reading a documentation file after a transient error, for docs served from --docs.dir. Embedded docs are never retried.",
+	).Default("2").Int()
+
+	flagCacheTTL = kingpin.Flag(
+		"cache.ttl",
+		"How long to cache responses of the `label_names`, `label_values`, `metric_metadata`, and `metrics_with_help` tools in memory."+
+			" Repeated calls with the same arguments within the TTL are served without querying Prometheus."+
+			" Query tools are never cached. 0 disables caching.",
+	).Default("0s").Duration()
+
 	flagLogToFile = kingpin.Flag(
 		"log.file",
 		"The name of the file to log to (file rotation policies should be configured with external tools like logrotate)",
 	).String()
 
+	flagLogToolCalls = kingpin.Flag(
+		"log.tool-calls",
+		"Log every tool call at info level with its arguments, duration, and outcome, to audit what clients did."+
+			" Values of arguments whose names look sensitive (e.g. containing `token` or `password`) are redacted.",
+	).Default("false").Bool()
+
+	flagAuditFile = kingpin.Flag(
+		"audit.file",
+		"File to append a JSON line to for every call of a tool that changes state (`reload`, `quit`, `delete_series`,"+
+			" `clean_tombstones`, `snapshot`, and `create_silence`), independent of the application logs."+
+			" Each line includes the SHA-256 hash of the previous line, so edits to earlier lines can be detected.",
+	).String()
+
+	flagRateLimitRPS = kingpin.Flag(
+		"ratelimit.rps",
+		"Maximum rate of tool calls per second, shared by all clients of the server."+
+			" Calls over the limit fail with a tool error telling the client when to retry. 0 disables rate limiting.",
+	).Default("0").Float64()
+
+	flagRateLimitBurst = kingpin.Flag(
+		"ratelimit.burst",
+		"Maximum number of tool calls allowed in a burst above --ratelimit.rps. 0 defaults to --ratelimit.rps rounded up.",
+	).Default("0").Int()
+
 	toolkitFlags = kingpinflag.AddFlags(kingpin.CommandLine, fmt.Sprintf(":%d", defaultPort))
 )
 
@@ -190,8 +444,45 @@ func main() {
 	slog.SetDefault(logger)
 	logger.Info("Starting "+programName, "version", promversion.Version, "build_date", promversion.BuildDate, "commit", promversion.Revision, "docs_commit", docsCommit, "go_version", runtime.Version())
 
+	if *flagMcpTransport == "unix" && *flagMcpUnixSocket == "" {
+		logger.Error("The unix transport requires a socket path, set with --mcp.unix-socket")
+		os.Exit(1)
+	}
+
+	prometheusURL, prometheusTargets, err := mcp.ParsePrometheusURLs(*flagPrometheusURL)
+	if err != nil {
+		logger.Error("Failed to parse Prometheus URLs", "err", err)
+		os.Exit(1)
+	}
+
+	corsOrigins, err := mcp.ParseCORSOrigins(*flagWebCORSOrigins)
+	if err != nil {
+		logger.Error("Failed to parse CORS origins", "err", err)
+		os.Exit(1)
+	}
+	if len(corsOrigins) > 0 && *flagMcpTransport != "http" {
+		logger.Warn("CORS origins are only used by the http transport, ignoring --web.cors-origins", "transport", *flagMcpTransport)
+	}
+
+	authToken, err := mcp.ResolveAuthToken(*flagMcpAuthToken, *flagMcpAuthTokenFile)
+	if err != nil {
+		logger.Error("Failed to load MCP auth token", "err", err)
+		os.Exit(1)
+	}
+	if authToken != "" && *flagMcpTransport != "http" {
+		logger.Warn("The MCP auth token is only used by the http transport, ignoring it", "transport", *flagMcpTransport)
+	}
+
+	if *flagPrometheusTLSSkipVerify {
+		if *flagHTTPConfig != "" {
+			logger.Error("--prometheus.tls-skip-verify can't be combined with --http.config, set `insecure_skip_verify` in the `tls_config` of the HTTP config file instead")
+			os.Exit(1)
+		}
+		logger.Warn("TLS certificate verification is disabled by --prometheus.tls-skip-verify, connections to Prometheus are vulnerable to man-in-the-middle attacks. Do not use this in production.")
+	}
+
 	// Optionally load HTTP config file to configure HTTP client for Prometheus API.
-	rt, err := getRoundTripperFromConfig(*flagHTTPConfig)
+	rt, err := getRoundTripperFromConfig(*flagHTTPConfig, *flagPrometheusTLSSkipVerify)
 	if err != nil {
 		logger.Error("Failed to load HTTP config file, using default HTTP round tripper", "err", err)
 	}
@@ -199,27 +490,76 @@ func main() {
 	ctx, rootCtxCancel := context.WithCancel(context.Background())
 	defer rootCtxCancel()
 
-	// Setup static file server for embedded prometheus docs.
-	docs, err := fs.Sub(assetsDocs, "external/docs/docs")
-	if err != nil {
-		logger.Error("Failed to create sub FS for embedded docs", "err", err)
+	// Setup static file server for embedded prometheus docs, unless they are
+	// served from an external directory.
+	if *flagDocsDir != "" {
+		if info, err := os.Stat(*flagDocsDir); err != nil || !info.IsDir() {
+			logger.Error("Docs directory is not a readable directory", "docs_dir", *flagDocsDir, "err", err)
+			os.Exit(1)
+		}
+		docsFs = os.DirFS(*flagDocsDir)
 	} else {
-		docsFs = docs
+		docs, err := fs.Sub(assetsDocs, "external/docs/docs")
+		if err != nil {
+			logger.Error("Failed to create sub FS for embedded docs", "err", err)
+		} else {
+			docsFs = docs
+		}
+	}
+
+	outputFormat := *flagMcpOutputFormat
+	if *flagMcpToonOutputEnabled {
+		logger.Warn("The --mcp.enable-toon-output flag is deprecated, use --mcp.output-format=toon instead")
+		outputFormat = mcp.OutputFormatTOON
 	}
 
 	mcpServer, mcpContainer, err := mcp.NewServer(ctx, mcp.ServerConfig{
-		Logger:                logger,
-		PrometheusURL:         *flagPrometheusURL,
-		PrometheusBackend:     *flagPrometheusBackend,
-		PrometheusTimeout:     *flagPrometheusTimeout,
-		TruncationLimit:       *flagPrometheusTruncationLimit,
-		RoundTripper:          rt,
-		TSDBAdminToolsEnabled: *flagEnableTsdbAdminTools,
-		EnabledTools:          *flagMcpTools,
-		DocsFS:                docsFs,
-		ToonOutputEnabled:     *flagMcpToonOutputEnabled,
-		ClientLoggingEnabled:  *flagMcpClientLogging,
-		KeepAlive:             *flagMcpKeepaliveInterval,
+		Logger:                  logger,
+		PrometheusURL:           prometheusURL,
+		PrometheusTargets:       prometheusTargets,
+		PrometheusBackend:       *flagPrometheusBackend,
+		PrometheusTimeout:       *flagPrometheusTimeout,
+		PrometheusMaxTimeout:    *flagPrometheusMaxTimeout,
+		PrometheusLookbackDelta: *flagPrometheusLookbackDelta,
+		PrometheusRetries:       *flagPrometheusRetries,
+		PrometheusRetryBackoff:  *flagPrometheusRetryBackoff,
+		PrometheusConfigPath:    *flagPrometheusConfigPath,
+		TruncationLimit:         *flagPrometheusTruncationLimit,
+		TruncationMode:          *flagPrometheusTruncationMode,
+		DisplayTimezone:         *flagPrometheusTimezone,
+		RoundTripper:            rt,
+		UserAgent:               *flagPrometheusUserAgent,
+		HTTPMaxResponseBytes:    *flagHTTPMaxResponseBytes,
+		TSDBAdminToolsEnabled:   *flagEnableTsdbAdminTools,
+		RequireConfirmation:     *flagRequireConfirmation,
+		ConfirmationToken:       *flagConfirmationToken,
+		AllowEmptyMatchers:      *flagPrometheusAllowEmptyMatchers,
+		AlertmanagerURL:         *flagAlertmanagerURL,
+		RemoteReadURL:           *flagPrometheusRemoteReadURL,
+		SilenceToolsEnabled:     *flagEnableSilenceTools,
+		EnabledTools:            *flagMcpTools,
+		DisabledTools:           *flagMcpDisableTools,
+		DocsFS:                  docsFs,
+		DocsIndexTimeout:        *flagDocsIndexTimeout,
+		DocsReadRetries:         *flagDocsReadRetries,
+		DocsFSEmbedded:          *flagDocsDir == "",
+		OutputFormat:            outputFormat,
+		StripHelpText:           *flagMcpStripHelpText,
+		ClientLoggingEnabled:    *flagMcpClientLogging,
+		LogToolCalls:            *flagLogToolCalls,
+		AuditFile:               *flagAuditFile,
+		RateLimitRPS:            *flagRateLimitRPS,
+		RateLimitBurst:          *flagRateLimitBurst,
+		MaxConcurrentRequests:   *flagPrometheusMaxConcurrentRequests,
+		KeepAlive:               *flagMcpKeepaliveInterval,
+		ProgressInterval:        *flagMcpProgressInterval,
+		Transport:               *flagMcpTransport,
+		InstructionsFile:        *flagMcpInstructionsFile,
+		ServerInstructions:      *flagMcpServerInstructions,
+		CacheTTL:                *flagCacheTTL,
+		MimirTenant:             *flagMimirTenant,
+		MimirTenantsPath:        *flagMimirTenantsPath,
+		ForwardHeaders:          *flagPrometheusForwardHeaders,
 	})
 	if err != nil {
 		logger.Error("Failed to create MCP server", "err", err)
@@ -297,8 +637,23 @@ func main() {
 					logger.Debug("starting MCP server", "transport", "http")
 
 					httpMcpHandler := mcp.NewStreamableHTTPHandler(mcpServer, logger, *flagMcpSessionTimeout)
-					http.Handle("/mcp", httpMcpHandler)
+					http.Handle("/mcp", mcp.NewCORSHandler(mcp.NewBearerAuthHandler(httpMcpHandler, authToken), corsOrigins))
+					<-cancel
+
+				case "sse":
+					logger.Debug("starting MCP server", "transport", "sse")
+
+					http.Handle("/sse", mcp.NewSSEHandler(ctx, mcpServer))
 					<-cancel
+
+				case "unix":
+					logger.Debug("starting MCP server", "transport", "unix", "socket", *flagMcpUnixSocket)
+
+					httpMcpHandler := mcp.NewStreamableHTTPHandler(mcpServer, logger, *flagMcpSessionTimeout)
+					if err := serveUnixSocket(logger, *flagMcpUnixSocket, httpMcpHandler, cancel); err != nil {
+						return fmt.Errorf("MCP server failed: %w", err)
+					}
+
 				default:
 					return fmt.Errorf("unsupported transport type: %s", *flagMcpTransport)
 				}
@@ -388,13 +743,21 @@ func initHTTPServer(logger *slog.Logger) *http.Server {
 		},
 	}
 
-	if *flagMcpTransport == "http" {
+	switch *flagMcpTransport {
+	case "http":
 		landingPageLinks = append(landingPageLinks,
 			web.LandingLinks{
 				Address: "/mcp",
 				Text:    "Prometheus MCP Server",
 			},
 		)
+	case "sse":
+		landingPageLinks = append(landingPageLinks,
+			web.LandingLinks{
+				Address: "/sse",
+				Text:    "Prometheus MCP Server (SSE)",
+			},
+		)
 	}
 
 	if docsFs != nil {
@@ -425,9 +788,91 @@ func initHTTPServer(logger *slog.Logger) *http.Server {
 	return server
 }
 
-func getRoundTripperFromConfig(httpConfig string) (http.RoundTripper, error) {
+// serveUnixSocket serves the MCP handler under `/mcp` on a Unix domain socket
+// at path until cancel is closed, then shuts down and removes the socket file.
+func serveUnixSocket(logger *slog.Logger, path string, mcpHandler http.Handler, cancel <-chan struct{}) error {
+	if err := removeStaleUnixSocket(path); err != nil {
+		return err
+	}
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return fmt.Errorf("failed to listen on unix socket %s: %w", path, err)
+	}
+	defer func() {
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			logger.Error("failed to remove unix socket", "socket", path, "err", err)
+		}
+	}()
+
+	mux := http.NewServeMux()
+	mux.Handle("/mcp", mcpHandler)
+	// Same timeouts as the TCP web server, see initHTTPServer.
+	server := &http.Server{
+		Handler:      mux,
+		ReadTimeout:  30 * time.Second,
+		WriteTimeout: 0,
+		IdleTimeout:  30 * time.Second,
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.Serve(listener)
+	}()
+
+	select {
+	case err := <-serveErr:
+		return fmt.Errorf("unix socket server failed: %w", err)
+	case <-cancel:
+	}
+
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer shutdownCancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		logger.Error("failed to shut down unix socket server gracefully", "err", err)
+	}
+	return nil
+}
+
+// removeStaleUnixSocket removes a socket file left behind at path by a
+// previous run that didn't shut down cleanly. Anything other than a socket is
+// left alone, so a mistyped path can't delete a regular file.
+func removeStaleUnixSocket(path string) error {
+	info, err := os.Lstat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to stat unix socket %s: %w", path, err)
+	}
+	if info.Mode().Type() != fs.ModeSocket {
+		return fmt.Errorf("refusing to listen on %s: file exists and is not a unix socket", path)
+	}
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf("failed to remove stale unix socket %s: %w", path, err)
+	}
+	return nil
+}
+
+// getRoundTripperFromConfig returns the round tripper for requests to
+// Prometheus. It is configured by the HTTP config file if one is given, or
+// skips TLS certificate verification if tlsSkipVerify is set.
+func getRoundTripperFromConfig(httpConfig string, tlsSkipVerify bool) (http.RoundTripper, error) {
+	if httpConfig != "" && tlsSkipVerify {
+		return nil, errors.New("TLS certificate verification can only be disabled without an HTTP configuration file")
+	}
+
 	httpClient := http.DefaultClient
-	if httpConfig != "" {
+	switch {
+	case tlsSkipVerify:
+		var err error
+		httpClient, err = config_util.NewClientFromConfig(config_util.HTTPClientConfig{
+			TLSConfig: config_util.TLSConfig{InsecureSkipVerify: true},
+		}, programName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create HTTP client without TLS verification: %w", err)
+		}
+	case httpConfig != "":
 		httpCfg, _, err := config_util.LoadHTTPConfigFile(httpConfig)
 		if err != nil {
 			return nil, fmt.Errorf("failed to load HTTP configuration file %s: %w", httpConfig, err)
@@ -0,0 +1,129 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestGetRoundTripperFromConfigOAuth2 verifies that an OAuth2 client
+// credentials config in the HTTP config file yields a round tripper that
+// fetches tokens from the token URL and refreshes them once they expire.
+func TestGetRoundTripperFromConfigOAuth2(t *testing.T) {
+	t.Parallel()
+
+	var tokensIssued atomic.Int32
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		require.Equal(t, "client_credentials", r.PostForm.Get("grant_type"))
+		require.Equal(t, "read", r.PostForm.Get("scope"))
+
+		clientID, clientSecret, ok := r.BasicAuth()
+		require.True(t, ok)
+		require.Equal(t, "mcp-client", clientID)
+		require.Equal(t, "mcp-secret", clientSecret)
+
+		n := tokensIssued.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		// Tokens expire immediately, so every request needs a new one.
+		fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"Bearer","expires_in":1}`, n)
+	}))
+	t.Cleanup(tokenServer.Close)
+
+	var authorizations []string
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorizations = append(authorizations, r.Header.Get("Authorization"))
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(backend.Close)
+
+	configPath := filepath.Join(t.TempDir(), "http-config.yml")
+	config := fmt.Sprintf(`oauth2:
+  client_id: mcp-client
+  client_secret: mcp-secret
+  token_url: %s
+  scopes:
+    - read
+`, tokenServer.URL)
+	require.NoError(t, os.WriteFile(configPath, []byte(config), 0o600))
+
+	rt, err := getRoundTripperFromConfig(configPath, false)
+	require.NoError(t, err)
+
+	client := &http.Client{Transport: rt}
+	for range 2 {
+		resp, err := client.Get(backend.URL)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+	}
+
+	// The oauth2 package considers tokens expiring within 10s as expired, so
+	// the second request refreshes the token.
+	require.Equal(t, []string{"Bearer token-1", "Bearer token-2"}, authorizations)
+	require.Equal(t, int32(2), tokensIssued.Load())
+}
+
+func TestGetRoundTripperFromConfigInvalid(t *testing.T) {
+	t.Parallel()
+
+	configPath := filepath.Join(t.TempDir(), "http-config.yml")
+	// OAuth2 can't be combined with other authorization methods.
+	config := `oauth2:
+  client_id: mcp-client
+  client_secret: mcp-secret
+  token_url: http://127.0.0.1/token
+bearer_token: token
+`
+	require.NoError(t, os.WriteFile(configPath, []byte(config), 0o600))
+
+	_, err := getRoundTripperFromConfig(configPath, false)
+	require.Error(t, err)
+}
+
+func TestGetRoundTripperFromConfigTLSSkipVerify(t *testing.T) {
+	t.Parallel()
+
+	backend := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(backend.Close)
+
+	// The test server's certificate is self-signed, so requests fail unless
+	// verification is disabled.
+	rt, err := getRoundTripperFromConfig("", false)
+	require.NoError(t, err)
+	_, err = (&http.Client{Transport: rt}).Get(backend.URL)
+	require.ErrorContains(t, err, "certificate")
+
+	rt, err = getRoundTripperFromConfig("", true)
+	require.NoError(t, err)
+	resp, err := (&http.Client{Transport: rt}).Get(backend.URL)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	configPath := filepath.Join(t.TempDir(), "http-config.yml")
+	require.NoError(t, os.WriteFile(configPath, []byte("follow_redirects: true\n"), 0o600))
+	_, err = getRoundTripperFromConfig(configPath, true)
+	require.Error(t, err)
+}
@@ -16,6 +16,7 @@ package metrics
 import (
 	"runtime"
 	"sync"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/collectors"
@@ -29,6 +30,24 @@ const (
 var (
 	once     sync.Once
 	Registry *prometheus.Registry
+
+	apiCallSuccess = &lastSuccessCollector{
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(MetricNamespace, "", "seconds_since_last_successful_api_call"),
+			"Seconds since the last successful API call to a backend, per backend URL.",
+			[]string{"backend"},
+			nil,
+		),
+		lastSuccess: make(map[string]time.Time),
+	}
+
+	apiCallRetries = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: prometheus.BuildFQName(MetricNamespace, "api", "call_retries_total"),
+			Help: "Total number of retries of Prometheus API calls after transient errors, per endpoint.",
+		},
+		[]string{"target_path"},
+	)
 )
 
 func init() {
@@ -56,6 +75,44 @@ func init() {
 			collectors.NewGoCollector(),
 			// register build info metric
 			metricBuildInfo,
+			apiCallSuccess,
+			apiCallRetries,
 		)
 	})
 }
+
+// RecordSuccessfulAPICall records that an API call to the given backend
+// succeeded just now.
+func RecordSuccessfulAPICall(backend string) {
+	apiCallSuccess.mu.Lock()
+	defer apiCallSuccess.mu.Unlock()
+	apiCallSuccess.lastSuccess[backend] = time.Now()
+}
+
+// RecordAPICallRetry records that an API call to the given endpoint path is
+// being retried after a transient error.
+func RecordAPICallRetry(path string) {
+	apiCallRetries.WithLabelValues(path).Inc()
+}
+
+// lastSuccessCollector exposes the time since the last successful API call
+// per backend. The value is computed at scrape time, so it keeps growing
+// while a backend is unreachable.
+type lastSuccessCollector struct {
+	desc *prometheus.Desc
+
+	mu          sync.Mutex
+	lastSuccess map[string]time.Time
+}
+
+func (c *lastSuccessCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *lastSuccessCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for backend, last := range c.lastSuccess {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, time.Since(last).Seconds(), backend)
+	}
+}
@@ -0,0 +1,373 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	errAlertmanagerURLNotConfigured   = errors.New("an Alertmanager URL must be configured with the `--alertmanager.url` flag")
+	errAlertmanagerSilencesNotEnabled = errors.New("creating silences must be enabled with the `--dangerous.enable-alertmanager-silences` flag")
+)
+
+// defaultAlertmanagerSilenceCreatedBy is the author of silences created
+// without an explicit created_by.
+const defaultAlertmanagerSilenceCreatedBy = "prometheus-mcp"
+
+// alertmanagerMatcher is a matcher in the format used by the Alertmanager v2
+// API.
+type alertmanagerMatcher struct {
+	Name    string `json:"name"`
+	Value   string `json:"value"`
+	IsRegex bool   `json:"isRegex"`
+	IsEqual bool   `json:"isEqual"`
+}
+
+// alertmanagerSilence is the body of a request to create a silence with the
+// Alertmanager v2 API.
+type alertmanagerSilence struct {
+	Matchers  []alertmanagerMatcher `json:"matchers"`
+	StartsAt  time.Time             `json:"startsAt"`
+	EndsAt    time.Time             `json:"endsAt"`
+	CreatedBy string                `json:"createdBy"`
+	Comment   string                `json:"comment"`
+}
+
+// ListSilencesHandler handles the list silences tool.
+func (s *ServerContainer) ListSilencesHandler(ctx context.Context, req *mcp.CallToolRequest, input AlertmanagerFilterInput) (*mcp.CallToolResult, any, error) {
+	query, err := alertmanagerFilterQuery(input.Filter)
+	if err != nil {
+		return newToolErrorResult(err.Error()), nil, nil
+	}
+
+	result, err := s.alertmanagerAPICall(ctx, http.MethodGet, "/api/v2/silences", query, nil)
+	if err != nil {
+		return newToolErrorResult("failed listing silences from Alertmanager: " + err.Error()), nil, nil
+	}
+	return newToolTextResult(result), nil, nil
+}
+
+// AlertmanagerAlertsHandler handles the Alertmanager alerts tool.
+func (s *ServerContainer) AlertmanagerAlertsHandler(ctx context.Context, req *mcp.CallToolRequest, input AlertmanagerFilterInput) (*mcp.CallToolResult, any, error) {
+	query, err := alertmanagerFilterQuery(input.Filter)
+	if err != nil {
+		return newToolErrorResult(err.Error()), nil, nil
+	}
+
+	result, err := s.alertmanagerAPICall(ctx, http.MethodGet, "/api/v2/alerts/groups", query, nil)
+	if err != nil {
+		return newToolErrorResult("failed listing alert groups from Alertmanager: " + err.Error()), nil, nil
+	}
+	return newToolTextResult(result), nil, nil
+}
+
+// CreateSilenceHandler handles the create silence tool.
+func (s *ServerContainer) CreateSilenceHandler(ctx context.Context, req *mcp.CallToolRequest, input CreateSilenceInput) (*mcp.CallToolResult, any, error) {
+	if !s.silenceToolsEnabled {
+		return newToolErrorResult("failed creating silence: " + errAlertmanagerSilencesNotEnabled.Error()), nil, nil
+	}
+
+	if len(input.Matchers) == 0 {
+		return newToolErrorResult("at least one matchers parameter is required"), nil, nil
+	}
+	if strings.TrimSpace(input.Comment) == "" {
+		return newToolErrorResult("comment parameter is required"), nil, nil
+	}
+
+	duration, err := model.ParseDuration(input.Duration)
+	if err != nil {
+		return newToolErrorResult(fmt.Sprintf("failed to parse duration: %v", err)), nil, nil
+	}
+	if duration <= 0 {
+		return newToolErrorResult("duration must be positive"), nil, nil
+	}
+
+	matchers, err := parseAlertmanagerMatchers(input.Matchers)
+	if err != nil {
+		return newToolErrorResult(err.Error()), nil, nil
+	}
+
+	createdBy := input.CreatedBy
+	if createdBy == "" {
+		createdBy = defaultAlertmanagerSilenceCreatedBy
+	}
+
+	now := time.Now().UTC()
+	silence := alertmanagerSilence{
+		StartsAt:  now,
+		EndsAt:    now.Add(time.Duration(duration)),
+		CreatedBy: createdBy,
+		Comment:   input.Comment,
+	}
+	for _, m := range matchers {
+		silence.Matchers = append(silence.Matchers, alertmanagerMatcher{
+			Name:    m.Name,
+			Value:   m.Value,
+			IsRegex: m.Type == labels.MatchRegexp || m.Type == labels.MatchNotRegexp,
+			IsEqual: m.Type == labels.MatchEqual || m.Type == labels.MatchRegexp,
+		})
+	}
+
+	body, err := json.Marshal(silence)
+	if err != nil {
+		return newToolErrorResult("failed to encode silence: " + err.Error()), nil, nil
+	}
+
+	logger := s.GetToolLogger(req, input)
+	logger.Warn("creating Alertmanager silence")
+
+	result, err := s.alertmanagerAPICall(ctx, http.MethodPost, "/api/v2/silences", nil, bytes.NewReader(body))
+	if err != nil {
+		return newToolErrorResult("failed creating silence in Alertmanager: " + err.Error()), nil, nil
+	}
+
+	logger.Warn("created Alertmanager silence successfully")
+	return newToolTextResult(result), nil, nil
+}
+
+// alertmanagerStatus is the subset of the Alertmanager v2 API status response
+// used by the Alertmanager status tool.
+type alertmanagerStatus struct {
+	Cluster struct {
+		Name   string `json:"name"`
+		Status string `json:"status"`
+		Peers  []struct {
+			Name    string `json:"name"`
+			Address string `json:"address"`
+		} `json:"peers"`
+	} `json:"cluster"`
+	Config struct {
+		Original string `json:"original"`
+	} `json:"config"`
+	Uptime      time.Time `json:"uptime"`
+	VersionInfo struct {
+		Version string `json:"version"`
+	} `json:"versionInfo"`
+}
+
+// alertmanagerConfig is the subset of an Alertmanager configuration file
+// needed to summarize its receivers and routing tree.
+type alertmanagerConfig struct {
+	Route     *alertmanagerConfigRoute `yaml:"route"`
+	Receivers []map[string]yaml.Node   `yaml:"receivers"`
+}
+
+type alertmanagerConfigRoute struct {
+	Receiver string                     `yaml:"receiver"`
+	GroupBy  []string                   `yaml:"group_by"`
+	Match    map[string]string          `yaml:"match"`
+	MatchRE  map[string]string          `yaml:"match_re"`
+	Matchers []string                   `yaml:"matchers"`
+	Continue bool                       `yaml:"continue"`
+	Routes   []*alertmanagerConfigRoute `yaml:"routes"`
+}
+
+type alertmanagerPeer struct {
+	Name    string `json:"name"`
+	Address string `json:"address"`
+}
+
+type alertmanagerReceiver struct {
+	Name         string   `json:"name"`
+	Integrations []string `json:"integrations,omitempty"`
+}
+
+type alertmanagerRoute struct {
+	Receiver string               `json:"receiver,omitempty"`
+	GroupBy  []string             `json:"group_by,omitempty"`
+	Matchers []string             `json:"matchers,omitempty"`
+	Continue bool                 `json:"continue,omitempty"`
+	Routes   []*alertmanagerRoute `json:"routes,omitempty"`
+}
+
+type alertmanagerStatusResponse struct {
+	Version       string                 `json:"version,omitempty"`
+	StartedAt     time.Time              `json:"started_at"`
+	Uptime        string                 `json:"uptime"`
+	ClusterName   string                 `json:"cluster_name,omitempty"`
+	ClusterStatus string                 `json:"cluster_status"`
+	Peers         []alertmanagerPeer     `json:"peers"`
+	Receivers     []alertmanagerReceiver `json:"receivers"`
+	Route         *alertmanagerRoute     `json:"route,omitempty"`
+	Config        string                 `json:"config"`
+	Notes         []string               `json:"notes,omitempty"`
+}
+
+// AlertmanagerStatusHandler handles the Alertmanager status tool.
+func (s *ServerContainer) AlertmanagerStatusHandler(ctx context.Context, req *mcp.CallToolRequest, input EmptyInput) (*mcp.CallToolResult, any, error) {
+	result, err := s.alertmanagerAPICall(ctx, http.MethodGet, "/api/v2/status", nil, nil)
+	if err != nil {
+		return newToolErrorResult("failed getting status from Alertmanager: " + err.Error()), nil, nil
+	}
+
+	var status alertmanagerStatus
+	if err := json.Unmarshal([]byte(result), &status); err != nil {
+		return newToolErrorResult("failed to decode Alertmanager status: " + err.Error()), nil, nil
+	}
+
+	resp := alertmanagerStatusResponse{
+		Version:       status.VersionInfo.Version,
+		StartedAt:     status.Uptime,
+		Uptime:        model.Duration(time.Since(status.Uptime).Truncate(time.Second)).String(),
+		ClusterName:   status.Cluster.Name,
+		ClusterStatus: status.Cluster.Status,
+		Peers:         make([]alertmanagerPeer, 0, len(status.Cluster.Peers)),
+		Receivers:     []alertmanagerReceiver{},
+		Config:        status.Config.Original,
+	}
+	for _, p := range status.Cluster.Peers {
+		resp.Peers = append(resp.Peers, alertmanagerPeer{Name: p.Name, Address: p.Address})
+	}
+
+	var cfg alertmanagerConfig
+	if err := yaml.Unmarshal([]byte(status.Config.Original), &cfg); err != nil {
+		resp.Notes = append(resp.Notes, "failed to parse Alertmanager config, receivers and routes are unavailable: "+err.Error())
+	} else {
+		resp.Receivers = summarizeAlertmanagerReceivers(cfg.Receivers)
+		resp.Route = summarizeAlertmanagerRoute(cfg.Route)
+	}
+
+	output, err := s.FormatOutput(resp)
+	if err != nil {
+		return newToolErrorResult("failed to format Alertmanager status: " + err.Error()), nil, nil
+	}
+	return newToolTextResult(output), nil, nil
+}
+
+// summarizeAlertmanagerReceivers returns the name of each receiver along with
+// the kinds of integrations it notifies, e.g. `slack` for `slack_configs`.
+func summarizeAlertmanagerReceivers(receivers []map[string]yaml.Node) []alertmanagerReceiver {
+	summaries := make([]alertmanagerReceiver, 0, len(receivers))
+	for _, r := range receivers {
+		var summary alertmanagerReceiver
+		if name, ok := r["name"]; ok {
+			summary.Name = name.Value
+		}
+		for key, node := range r {
+			integration, ok := strings.CutSuffix(key, "_configs")
+			if ok && node.Kind == yaml.SequenceNode && len(node.Content) > 0 {
+				summary.Integrations = append(summary.Integrations, integration)
+			}
+		}
+		slices.Sort(summary.Integrations)
+		summaries = append(summaries, summary)
+	}
+	return summaries
+}
+
+// summarizeAlertmanagerRoute converts a routing tree from the Alertmanager
+// config, normalizing the deprecated match and match_re fields to matchers.
+func summarizeAlertmanagerRoute(route *alertmanagerConfigRoute) *alertmanagerRoute {
+	if route == nil {
+		return nil
+	}
+
+	summary := &alertmanagerRoute{
+		Receiver: route.Receiver,
+		GroupBy:  route.GroupBy,
+		Continue: route.Continue,
+	}
+	for _, name := range slices.Sorted(maps.Keys(route.Match)) {
+		summary.Matchers = append(summary.Matchers, fmt.Sprintf("%s=%q", name, route.Match[name]))
+	}
+	for _, name := range slices.Sorted(maps.Keys(route.MatchRE)) {
+		summary.Matchers = append(summary.Matchers, fmt.Sprintf("%s=~%q", name, route.MatchRE[name]))
+	}
+	summary.Matchers = append(summary.Matchers, route.Matchers...)
+	for _, child := range route.Routes {
+		summary.Routes = append(summary.Routes, summarizeAlertmanagerRoute(child))
+	}
+	return summary
+}
+
+// alertmanagerAPICall makes a request to the configured Alertmanager's API.
+// Requests use the server's HTTP client settings, but never forward a
+// client's Authorization header, which is meant for Prometheus.
+func (s *ServerContainer) alertmanagerAPICall(ctx context.Context, method, path string, query url.Values, body io.Reader) (string, error) {
+	if s.alertmanagerURL == "" {
+		return "", errAlertmanagerURLNotConfigured
+	}
+
+	fullPath, err := url.JoinPath(s.alertmanagerURL, path)
+	if err != nil {
+		return "", fmt.Errorf("failed to construct URL for request: %w", err)
+	}
+	if len(query) > 0 {
+		fullPath += "?" + query.Encode()
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.getAPITimeout(ctx))
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, method, fullPath, body)
+	if err != nil {
+		return "", fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	rt := s.alertmanagerRT
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	return s.sendHTTPRequest(req, rt, s.alertmanagerURL, path, true)
+}
+
+// parseAlertmanagerMatchers parses matchers in the PromQL label matcher
+// syntax, e.g. `alertname="HighLatency"` or `job=~"api.*"`.
+func parseAlertmanagerMatchers(matchers []string) ([]*labels.Matcher, error) {
+	parsed, err := promqlParser.ParseMetricSelector("{" + strings.Join(matchers, ",") + "}")
+	if err != nil {
+		return nil, fmt.Errorf("invalid matchers, expected label matchers such as alertname=\"HighLatency\": %w", err)
+	}
+	return parsed, nil
+}
+
+// alertmanagerFilterQuery validates filter matchers and converts them to the
+// query parameters of the Alertmanager v2 API.
+func alertmanagerFilterQuery(filter []string) (url.Values, error) {
+	if len(filter) == 0 {
+		return nil, nil
+	}
+
+	matchers, err := parseAlertmanagerMatchers(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{}
+	for _, m := range matchers {
+		query.Add("filter", m.String())
+	}
+	return query, nil
+}
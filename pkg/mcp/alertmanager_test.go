@@ -0,0 +1,408 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/prometheus/prometheus-mcp/pkg/mcp/mcptest"
+)
+
+// fakeAlertmanager records the requests it receives and responds to them
+// with a fixed JSON body.
+type fakeAlertmanager struct {
+	mu       sync.Mutex
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func newFakeAlertmanager(t *testing.T, response string) (*fakeAlertmanager, *httptest.Server) {
+	t.Helper()
+
+	fake := &fakeAlertmanager{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		fake.mu.Lock()
+		fake.requests = append(fake.requests, r)
+		fake.bodies = append(fake.bodies, body)
+		fake.mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(response))
+	}))
+	t.Cleanup(srv.Close)
+
+	return fake, srv
+}
+
+func TestListSilencesHandler(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name            string
+		args            map[string]any
+		noURL           bool
+		expectedFilters []string
+		expectedError   string
+	}{
+		{
+			name: "no filter",
+			args: map[string]any{},
+		},
+		{
+			name:            "with filter",
+			args:            map[string]any{"filter": []string{`alertname="HighLatency"`, `job=~"api.*"`}},
+			expectedFilters: []string{`alertname="HighLatency"`, `job=~"api.*"`},
+		},
+		{
+			name:          "invalid filter",
+			args:          map[string]any{"filter": []string{"not a matcher"}},
+			expectedError: "invalid matchers",
+		},
+		{
+			name:          "alertmanager url not configured",
+			args:          map[string]any{},
+			noURL:         true,
+			expectedError: "--alertmanager.url",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			fake, srv := newFakeAlertmanager(t, `[{"id":"abc","status":{"state":"active"}}]`)
+			container := newTestContainer(nil)
+			if !tc.noURL {
+				container.alertmanagerURL = srv.URL
+			}
+
+			ts := mcptest.NewTestServer(t)
+			mcptest.AddTool(ts, listSilencesToolDef, container.ListSilencesHandler)
+
+			result, err := ts.CallTool(ts.Context(), "list_silences", tc.args)
+			require.NoError(t, err)
+			text := mcptest.GetResultText(result)
+
+			if tc.expectedError != "" {
+				require.True(t, result.IsError)
+				require.Contains(t, text, tc.expectedError)
+				require.Empty(t, fake.requests)
+				return
+			}
+
+			require.False(t, result.IsError, text)
+			require.Contains(t, text, `"id":"abc"`)
+			require.Len(t, fake.requests, 1)
+			require.Equal(t, http.MethodGet, fake.requests[0].Method)
+			require.Equal(t, "/api/v2/silences", fake.requests[0].URL.Path)
+			require.Equal(t, tc.expectedFilters, fake.requests[0].URL.Query()["filter"])
+		})
+	}
+}
+
+func TestAlertmanagerAlertsHandler(t *testing.T) {
+	t.Parallel()
+
+	fake, srv := newFakeAlertmanager(t, `[{"labels":{"alertname":"HighLatency"},"alerts":[]}]`)
+	container := newTestContainer(nil)
+	container.alertmanagerURL = srv.URL
+
+	ts := mcptest.NewTestServer(t)
+	mcptest.AddTool(ts, alertmanagerAlertsToolDef, container.AlertmanagerAlertsHandler)
+
+	result, err := ts.CallTool(ts.Context(), "alertmanager_alerts", map[string]any{"filter": []string{`severity="page"`}})
+	require.NoError(t, err)
+	text := mcptest.GetResultText(result)
+
+	require.False(t, result.IsError, text)
+	require.Contains(t, text, "HighLatency")
+	require.Len(t, fake.requests, 1)
+	require.Equal(t, "/api/v2/alerts/groups", fake.requests[0].URL.Path)
+	require.Equal(t, []string{`severity="page"`}, fake.requests[0].URL.Query()["filter"])
+}
+
+func TestCreateSilenceHandler(t *testing.T) {
+	t.Parallel()
+
+	validArgs := map[string]any{
+		"matchers": []string{`alertname="HighLatency"`, `job!~"batch.*"`},
+		"duration": "2h",
+		"comment":  "investigating latency",
+	}
+
+	testCases := []struct {
+		name          string
+		args          map[string]any
+		disabled      bool
+		noURL         bool
+		expectedError string
+		validateBody  func(t *testing.T, silence alertmanagerSilence)
+	}{
+		{
+			name: "creates silence",
+			args: validArgs,
+			validateBody: func(t *testing.T, silence alertmanagerSilence) {
+				require.Equal(t, []alertmanagerMatcher{
+					{Name: "alertname", Value: "HighLatency", IsRegex: false, IsEqual: true},
+					{Name: "job", Value: "batch.*", IsRegex: true, IsEqual: false},
+				}, silence.Matchers)
+				require.Equal(t, 2*time.Hour, silence.EndsAt.Sub(silence.StartsAt))
+				require.Equal(t, defaultAlertmanagerSilenceCreatedBy, silence.CreatedBy)
+				require.Equal(t, "investigating latency", silence.Comment)
+			},
+		},
+		{
+			name: "custom created_by",
+			args: map[string]any{
+				"matchers":   []string{`instance="db-1:9100"`},
+				"duration":   "30m",
+				"comment":    "maintenance",
+				"created_by": "oncall",
+			},
+			validateBody: func(t *testing.T, silence alertmanagerSilence) {
+				require.Equal(t, "oncall", silence.CreatedBy)
+				require.Equal(t, 30*time.Minute, silence.EndsAt.Sub(silence.StartsAt))
+			},
+		},
+		{
+			name:          "silence tools disabled",
+			args:          validArgs,
+			disabled:      true,
+			expectedError: "--dangerous.enable-alertmanager-silences",
+		},
+		{
+			name:          "alertmanager url not configured",
+			args:          validArgs,
+			noURL:         true,
+			expectedError: "--alertmanager.url",
+		},
+		{
+			name:          "invalid matchers",
+			args:          map[string]any{"matchers": []string{"{{"}, "duration": "1h", "comment": "x"},
+			expectedError: "invalid matchers",
+		},
+		{
+			name:          "invalid duration",
+			args:          map[string]any{"matchers": []string{`job="api"`}, "duration": "forever", "comment": "x"},
+			expectedError: "failed to parse duration",
+		},
+		{
+			name:          "zero duration",
+			args:          map[string]any{"matchers": []string{`job="api"`}, "duration": "0s", "comment": "x"},
+			expectedError: "duration must be positive",
+		},
+		{
+			name:          "empty comment",
+			args:          map[string]any{"matchers": []string{`job="api"`}, "duration": "1h", "comment": "  "},
+			expectedError: "comment parameter is required",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			fake, srv := newFakeAlertmanager(t, `{"silenceID":"new-silence"}`)
+			container := newTestContainer(nil)
+			container.silenceToolsEnabled = !tc.disabled
+			if !tc.noURL {
+				container.alertmanagerURL = srv.URL
+			}
+
+			ts := mcptest.NewTestServer(t)
+			mcptest.AddTool(ts, createSilenceToolDef, container.CreateSilenceHandler)
+
+			result, err := ts.CallTool(ts.Context(), "create_silence", tc.args)
+			require.NoError(t, err)
+			text := mcptest.GetResultText(result)
+
+			if tc.expectedError != "" {
+				require.True(t, result.IsError)
+				require.Contains(t, text, tc.expectedError)
+				require.Empty(t, fake.requests)
+				return
+			}
+
+			require.False(t, result.IsError, text)
+			require.Contains(t, text, "new-silence")
+			require.Len(t, fake.requests, 1)
+			require.Equal(t, http.MethodPost, fake.requests[0].Method)
+			require.Equal(t, "/api/v2/silences", fake.requests[0].URL.Path)
+			require.Equal(t, "application/json", fake.requests[0].Header.Get("Content-Type"))
+
+			var silence alertmanagerSilence
+			require.NoError(t, json.Unmarshal(fake.bodies[0], &silence))
+			tc.validateBody(t, silence)
+		})
+	}
+}
+
+func TestAlertmanagerRequestsDoNotForwardAuth(t *testing.T) {
+	t.Parallel()
+
+	fake, srv := newFakeAlertmanager(t, `[]`)
+	container := newTestContainer(nil)
+	container.alertmanagerURL = srv.URL
+
+	ctx := addAuthToContext(t.Context(), "Bearer secret")
+	_, err := container.alertmanagerAPICall(ctx, http.MethodGet, "/api/v2/silences", nil, nil)
+	require.NoError(t, err)
+	require.Len(t, fake.requests, 1)
+	require.Empty(t, fake.requests[0].Header.Get("Authorization"))
+}
+
+func TestAlertmanagerRequestsBypassConcurrencyLimit(t *testing.T) {
+	t.Parallel()
+
+	fake, srv := newFakeAlertmanager(t, `[]`)
+	container, err := newServerContainer(t.Context(), ServerConfig{
+		Logger:                slog.Default(),
+		PrometheusURL:         "http://localhost:9090",
+		PrometheusTimeout:     time.Minute,
+		RoundTripper:          http.DefaultTransport,
+		AlertmanagerURL:       srv.URL,
+		MaxConcurrentRequests: 1,
+	})
+	require.NoError(t, err)
+
+	// With the only slot for Prometheus requests taken, Alertmanager
+	// requests must not wait for it.
+	require.NoError(t, container.apiCallLimiter.sem.Acquire(t.Context(), 1))
+	defer container.apiCallLimiter.sem.Release(1)
+
+	ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+	defer cancel()
+	_, err = container.alertmanagerAPICall(ctx, http.MethodGet, "/api/v2/silences", nil, nil)
+	require.NoError(t, err)
+	require.Len(t, fake.requests, 1)
+	require.Equal(t, container.userAgent, fake.requests[0].Header.Get("User-Agent"))
+}
+
+func TestAlertmanagerStatusHandler(t *testing.T) {
+	t.Parallel()
+
+	config := `route:
+  receiver: default
+  group_by: [alertname]
+  routes:
+    - receiver: pager
+      match:
+        severity: page
+      continue: true
+    - receiver: team-db
+      matchers:
+        - team="db"
+receivers:
+  - name: default
+    email_configs:
+      - to: ops@example.com
+  - name: pager
+    pagerduty_configs:
+      - routing_key: <secret>
+    slack_configs:
+      - channel: '#alerts'
+  - name: team-db
+`
+	startedAt := time.Now().Add(-2 * time.Hour).UTC()
+
+	testCases := []struct {
+		name             string
+		config           string
+		validateResponse func(t *testing.T, resp alertmanagerStatusResponse)
+	}{
+		{
+			name:   "summarizes receivers and routes",
+			config: config,
+			validateResponse: func(t *testing.T, resp alertmanagerStatusResponse) {
+				require.Equal(t, "0.28.0", resp.Version)
+				require.Equal(t, "ready", resp.ClusterStatus)
+				require.Equal(t, []alertmanagerPeer{{Name: "01ABC", Address: "10.0.0.1:9094"}}, resp.Peers)
+				require.Contains(t, resp.Uptime, "2h")
+				require.Equal(t, config, resp.Config)
+				require.Equal(t, []alertmanagerReceiver{
+					{Name: "default", Integrations: []string{"email"}},
+					{Name: "pager", Integrations: []string{"pagerduty", "slack"}},
+					{Name: "team-db"},
+				}, resp.Receivers)
+				require.Equal(t, &alertmanagerRoute{
+					Receiver: "default",
+					GroupBy:  []string{"alertname"},
+					Routes: []*alertmanagerRoute{
+						{Receiver: "pager", Matchers: []string{`severity="page"`}, Continue: true},
+						{Receiver: "team-db", Matchers: []string{`team="db"`}},
+					},
+				}, resp.Route)
+				require.Empty(t, resp.Notes)
+			},
+		},
+		{
+			name:   "unparseable config",
+			config: "route: [",
+			validateResponse: func(t *testing.T, resp alertmanagerStatusResponse) {
+				require.Equal(t, "route: [", resp.Config)
+				require.Empty(t, resp.Receivers)
+				require.Nil(t, resp.Route)
+				require.Len(t, resp.Notes, 1)
+				require.Contains(t, resp.Notes[0], "failed to parse Alertmanager config")
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			body, err := json.Marshal(map[string]any{
+				"cluster": map[string]any{
+					"name":   "01ABC",
+					"status": "ready",
+					"peers":  []map[string]string{{"name": "01ABC", "address": "10.0.0.1:9094"}},
+				},
+				"config":      map[string]string{"original": tc.config},
+				"uptime":      startedAt.Format(time.RFC3339),
+				"versionInfo": map[string]string{"version": "0.28.0"},
+			})
+			require.NoError(t, err)
+
+			fake, srv := newFakeAlertmanager(t, string(body))
+			container := newTestContainer(nil)
+			container.alertmanagerURL = srv.URL
+
+			ts := mcptest.NewTestServer(t)
+			mcptest.AddTool(ts, alertmanagerStatusToolDef, container.AlertmanagerStatusHandler)
+
+			result, err := ts.CallTool(ts.Context(), "alertmanager_status", map[string]any{})
+			require.NoError(t, err)
+			text := mcptest.GetResultText(result)
+			require.False(t, result.IsError, text)
+			require.Len(t, fake.requests, 1)
+			require.Equal(t, "/api/v2/status", fake.requests[0].URL.Path)
+
+			var resp alertmanagerStatusResponse
+			require.NoError(t, json.Unmarshal([]byte(text), &resp))
+			tc.validateResponse(t, resp)
+		})
+	}
+}
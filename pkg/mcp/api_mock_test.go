@@ -62,6 +62,11 @@ type SeriesCall struct {
 	EndTime   time.Time
 }
 
+// RulesCall records the parameters of a Rules call.
+type RulesCall struct {
+	Matches []string
+}
+
 // MetadataCall records the parameters of a Metadata call.
 type MetadataCall struct {
 	Metric string
@@ -96,13 +101,15 @@ type MockPrometheusAPI struct {
 	QueryFunc           func(ctx context.Context, query string, ts time.Time, opts ...promv1.Option) (model.Value, promv1.Warnings, error)
 	QueryExemplarsFunc  func(ctx context.Context, query string, startTime time.Time, endTime time.Time) ([]promv1.ExemplarQueryResult, error)
 	QueryRangeFunc      func(ctx context.Context, query string, r promv1.Range, opts ...promv1.Option) (model.Value, promv1.Warnings, error)
-	RulesFunc           func(ctx context.Context) (promv1.RulesResult, error)
+	RulesFunc           func(ctx context.Context, matches []string) (promv1.RulesResult, error)
 	RuntimeinfoFunc     func(ctx context.Context) (promv1.RuntimeinfoResult, error)
 	SeriesFunc          func(ctx context.Context, matches []string, startTime time.Time, endTime time.Time, opts ...promv1.Option) ([]model.LabelSet, promv1.Warnings, error)
 	SnapshotFunc        func(ctx context.Context, skipHead bool) (promv1.SnapshotResult, error)
 	TargetsFunc         func(ctx context.Context) (promv1.TargetsResult, error)
 	TargetsMetadataFunc func(ctx context.Context, matchTarget string, metric string, limit string) ([]promv1.MetricMetadata, error)
 	TSDBFunc            func(ctx context.Context, opts ...promv1.Option) (promv1.TSDBResult, error)
+	TSDBBlocksFunc      func(ctx context.Context) (promv1.TSDBBlocksResult, error)
+	FormatQueryFunc     func(ctx context.Context, query string) (string, error)
 	WALReplayFunc       func(ctx context.Context) (promv1.WalReplayStatus, error)
 
 	// Call tracking fields for verifying handler behavior.
@@ -113,6 +120,7 @@ type MockPrometheusAPI struct {
 	LabelValuesCalls []LabelValuesCall
 	SeriesCalls      []SeriesCall
 	MetadataCalls    []MetadataCall
+	RulesCalls       []RulesCall
 }
 
 // ResetCalls clears all recorded call tracking data.
@@ -126,6 +134,7 @@ func (m *MockPrometheusAPI) ResetCalls() {
 	m.LabelValuesCalls = nil
 	m.SeriesCalls = nil
 	m.MetadataCalls = nil
+	m.RulesCalls = nil
 }
 
 // GetQueryCalls returns a copy of the recorded Query calls.
@@ -188,6 +197,16 @@ func (m *MockPrometheusAPI) GetMetadataCalls() []MetadataCall {
 	return result
 }
 
+// GetRulesCalls returns a copy of the recorded Rules calls.
+// Thread-safe for concurrent test execution.
+func (m *MockPrometheusAPI) GetRulesCalls() []RulesCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	result := make([]RulesCall, len(m.RulesCalls))
+	copy(result, m.RulesCalls)
+	return result
+}
+
 // Implement all methods of promv1.API to delegate to the function fields.
 func (m *MockPrometheusAPI) AlertManagers(ctx context.Context) (promv1.AlertManagersResult, error) {
 	if m.AlertManagersFunc != nil {
@@ -231,7 +250,7 @@ func (m *MockPrometheusAPI) Flags(ctx context.Context) (promv1.FlagsResult, erro
 	}
 	return promv1.FlagsResult{}, nil
 }
-func (m *MockPrometheusAPI) LabelNames(ctx context.Context, matches []string, startTime time.Time, endTime time.Time, opts ...promv1.Option) ([]string, promv1.Warnings, error) {
+func (m *MockPrometheusAPI) LabelNames(ctx context.Context, matches []string, startTime time.Time, endTime time.Time, opts ...promv1.Option) (model.LabelNames, promv1.Warnings, error) {
 	// Record the call for verification.
 	m.mu.Lock()
 	m.LabelNamesCalls = append(m.LabelNamesCalls, LabelNamesCall{
@@ -242,7 +261,15 @@ func (m *MockPrometheusAPI) LabelNames(ctx context.Context, matches []string, st
 	m.mu.Unlock()
 
 	if m.LabelNamesFunc != nil {
-		return m.LabelNamesFunc(ctx, matches, startTime, endTime, opts...)
+		names, warnings, err := m.LabelNamesFunc(ctx, matches, startTime, endTime, opts...)
+		if names == nil {
+			return nil, warnings, err
+		}
+		lnames := make(model.LabelNames, len(names))
+		for i, name := range names {
+			lnames[i] = model.LabelName(name)
+		}
+		return lnames, warnings, err
 	}
 	return nil, nil, nil
 }
@@ -310,9 +337,14 @@ func (m *MockPrometheusAPI) QueryRange(ctx context.Context, query string, r prom
 	}
 	return nil, nil, nil
 }
-func (m *MockPrometheusAPI) Rules(ctx context.Context) (promv1.RulesResult, error) {
+func (m *MockPrometheusAPI) Rules(ctx context.Context, matches []string) (promv1.RulesResult, error) {
+	// Record the call for verification.
+	m.mu.Lock()
+	m.RulesCalls = append(m.RulesCalls, RulesCall{Matches: matches})
+	m.mu.Unlock()
+
 	if m.RulesFunc != nil {
-		return m.RulesFunc(ctx)
+		return m.RulesFunc(ctx, matches)
 	}
 	return promv1.RulesResult{}, nil
 }
@@ -361,6 +393,18 @@ func (m *MockPrometheusAPI) TSDB(ctx context.Context, opts ...promv1.Option) (pr
 	}
 	return promv1.TSDBResult{}, nil
 }
+func (m *MockPrometheusAPI) TSDBBlocks(ctx context.Context) (promv1.TSDBBlocksResult, error) {
+	if m.TSDBBlocksFunc != nil {
+		return m.TSDBBlocksFunc(ctx)
+	}
+	return promv1.TSDBBlocksResult{}, nil
+}
+func (m *MockPrometheusAPI) FormatQuery(ctx context.Context, query string) (string, error) {
+	if m.FormatQueryFunc != nil {
+		return m.FormatQueryFunc(ctx, query)
+	}
+	return query, nil
+}
 func (m *MockPrometheusAPI) WalReplay(ctx context.Context) (promv1.WalReplayStatus, error) {
 	if m.WALReplayFunc != nil {
 		return m.WALReplayFunc(ctx)
@@ -0,0 +1,175 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// auditedTools are the tools that change the state of Prometheus or
+// Alertmanager. Every call to them is recorded in the audit log.
+var auditedTools = []string{
+	"clean_tombstones",
+	"create_silence",
+	"delete_series",
+	"quit",
+	"reload",
+	"snapshot",
+}
+
+// auditRecord is a line of the audit log.
+type auditRecord struct {
+	Timestamp time.Time       `json:"timestamp"`
+	Tool      string          `json:"tool"`
+	Arguments json.RawMessage `json:"arguments,omitempty"`
+	Principal string          `json:"principal,omitempty"`
+	Status    string          `json:"status"`
+	Error     string          `json:"error,omitempty"`
+	// PrevHash is the hex encoded SHA-256 hash of the previous line of the
+	// audit log, or empty for the first line. Chaining the records makes
+	// edits and removals of earlier lines detectable.
+	PrevHash string `json:"prev_hash"`
+}
+
+// auditLog appends audit records as JSON lines to a file, independent of the
+// application logs.
+type auditLog struct {
+	mu       sync.Mutex
+	file     *os.File
+	prevHash string
+}
+
+// openAuditLog opens the audit log file at path for appending, creating it if
+// needed. The hash chain continues from the last line of an existing file.
+func openAuditLog(path string) (*auditLog, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_RDWR, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log file: %w", err)
+	}
+
+	var last []byte
+	r := bufio.NewReader(f)
+	for {
+		line, err := r.ReadBytes('\n')
+		if line = bytes.TrimSuffix(line, []byte("\n")); len(line) > 0 {
+			last = line
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			f.Close()
+			return nil, fmt.Errorf("failed to read audit log file: %w", err)
+		}
+	}
+
+	a := &auditLog{file: f}
+	if last != nil {
+		a.prevHash = auditLineHash(last)
+	}
+	return a, nil
+}
+
+// write appends a record to the audit log, chained to the previous record.
+func (a *auditLog) write(rec auditRecord) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	rec.PrevHash = a.prevHash
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit record: %w", err)
+	}
+	if _, err := a.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit record: %w", err)
+	}
+	a.prevHash = auditLineHash(line)
+	return nil
+}
+
+func auditLineHash(line []byte) string {
+	sum := sha256.Sum256(line)
+	return hex.EncodeToString(sum[:])
+}
+
+// auditMiddleware creates an MCP middleware that records every call to the
+// audited tools in the audit log, whether it succeeded or not. Failing to
+// write the audit log is logged, but doesn't fail the tool call since the
+// operation already happened.
+func auditMiddleware(a *auditLog, logger *slog.Logger) mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			if method != methodToolsCall {
+				return next(ctx, method, req)
+			}
+			params, ok := req.GetParams().(*mcp.CallToolParamsRaw)
+			if !ok || !slices.Contains(auditedTools, params.Name) {
+				return next(ctx, method, req)
+			}
+
+			result, err := next(ctx, method, req)
+
+			rec := auditRecord{
+				Timestamp: time.Now().UTC(),
+				Tool:      params.Name,
+				Arguments: redactToolArguments(params.Arguments),
+				Principal: auditPrincipal(ctx),
+				Status:    "success",
+			}
+			toolResult, _ := result.(*mcp.CallToolResult)
+			switch {
+			case err != nil:
+				rec.Status, rec.Error = "error", err.Error()
+			case toolResult == nil:
+				rec.Status = "error"
+			case toolResult.IsError:
+				rec.Status, rec.Error = "error", toolResultErrorText(toolResult)
+			}
+			if werr := a.write(rec); werr != nil {
+				logger.Error("Failed to write audit log", "tool_name", params.Name, "err", werr)
+			}
+
+			return result, err
+		}
+	}
+}
+
+// auditPrincipal returns who made a tool call, if known: the username of
+// basic auth credentials forwarded by the client. Bearer tokens and passwords
+// are never recorded.
+func auditPrincipal(ctx context.Context) string {
+	if auth := getAuthFromContext(ctx); auth != "" {
+		r := http.Request{Header: http.Header{"Authorization": []string{auth}}}
+		if user, _, ok := r.BasicAuth(); ok {
+			return user
+		}
+	}
+	return ""
+}
@@ -0,0 +1,161 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/require"
+)
+
+// readAuditRecords reads the audit log at path, checking that the hash chain
+// is intact.
+func readAuditRecords(t *testing.T, path string) []auditRecord {
+	t.Helper()
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	var (
+		records  []auditRecord
+		prevHash string
+	)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var rec auditRecord
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &rec))
+		require.Equal(t, prevHash, rec.PrevHash, "hash chain broken at line %d", len(records)+1)
+		prevHash = auditLineHash(scanner.Bytes())
+		records = append(records, rec)
+	}
+	require.NoError(t, scanner.Err())
+	return records
+}
+
+func TestAuditMiddleware(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "audit.log")
+	a, err := openAuditLog(path)
+	require.NoError(t, err)
+	logger, _ := newTestLogger()
+	middleware := auditMiddleware(a, logger)
+
+	basicAuth := "Basic " + base64.StdEncoding.EncodeToString([]byte("alice:hunter2"))
+	calls := []struct {
+		ctx        context.Context
+		method     string
+		req        mcp.Request
+		nextResult mcp.Result
+		nextErr    error
+	}{
+		{
+			ctx:        addAuthToContext(context.Background(), basicAuth),
+			method:     methodToolsCall,
+			req:        mockRequest(&mcp.CallToolParamsRaw{Name: "reload", Arguments: json.RawMessage(`{}`)}),
+			nextResult: newToolTextResult("ok"),
+		},
+		{
+			ctx:    context.Background(),
+			method: methodToolsCall,
+			req: mockRequest(&mcp.CallToolParamsRaw{
+				Name:      "delete_series",
+				Arguments: json.RawMessage(`{"matches":["up"],"confirm":"s3cret"}`),
+			}),
+			nextResult: newToolErrorResult("invalid confirmation token for delete_series"),
+		},
+		{
+			ctx:     addAuthToContext(context.Background(), "Basic "+base64.StdEncoding.EncodeToString([]byte("bob:hunter2"))),
+			method:  methodToolsCall,
+			req:     mockRequest(&mcp.CallToolParamsRaw{Name: "snapshot"}),
+			nextErr: errors.New("connection reset"),
+		},
+		{
+			// Read-only tools are not audited.
+			ctx:        addAuthToContext(context.Background(), basicAuth),
+			method:     methodToolsCall,
+			req:        mockRequest(&mcp.CallToolParamsRaw{Name: "query", Arguments: json.RawMessage(`{"query":"up"}`)}),
+			nextResult: newToolTextResult("ok"),
+		},
+		{
+			ctx:        context.Background(),
+			method:     methodResourcesRead,
+			req:        mockRequest(&mcp.ReadResourceParams{URI: "prometheus://alerts"}),
+			nextResult: &mcp.ReadResourceResult{},
+		},
+	}
+	for _, c := range calls {
+		next := func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			return c.nextResult, c.nextErr
+		}
+		result, err := middleware(next)(c.ctx, c.method, c.req)
+		require.Equal(t, c.nextResult, result)
+		require.Equal(t, c.nextErr, err)
+	}
+
+	records := readAuditRecords(t, path)
+	require.Len(t, records, 3)
+
+	require.Equal(t, "reload", records[0].Tool)
+	require.Equal(t, "alice", records[0].Principal)
+	require.Equal(t, "success", records[0].Status)
+	require.Empty(t, records[0].Error)
+	require.False(t, records[0].Timestamp.IsZero())
+
+	require.Equal(t, "delete_series", records[1].Tool)
+	require.Empty(t, records[1].Principal)
+	require.Equal(t, "error", records[1].Status)
+	require.Equal(t, "invalid confirmation token for delete_series", records[1].Error)
+	require.JSONEq(t, `{"matches":["up"],"confirm":"<redacted>"}`, string(records[1].Arguments))
+
+	require.Equal(t, "snapshot", records[2].Tool)
+	require.Equal(t, "bob", records[2].Principal)
+	require.Equal(t, "error", records[2].Status)
+	require.Equal(t, "connection reset", records[2].Error)
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.NotContains(t, string(content), "hunter2")
+	require.NotContains(t, string(content), "s3cret")
+}
+
+func TestOpenAuditLogContinuesHashChain(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "audit.log")
+	for _, tool := range []string{"reload", "quit"} {
+		a, err := openAuditLog(path)
+		require.NoError(t, err)
+		require.NoError(t, a.write(auditRecord{Tool: tool, Status: "success"}))
+		require.NoError(t, a.file.Close())
+	}
+
+	records := readAuditRecords(t, path)
+	require.Len(t, records, 2)
+	require.Empty(t, records[0].PrevHash)
+	require.NotEmpty(t, records[1].PrevHash)
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+}
@@ -0,0 +1,180 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcp
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/prometheus/prometheus-mcp/internal/metrics"
+)
+
+var (
+	metricCacheHits = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: prometheus.BuildFQName(metrics.MetricNamespace, "cache", "hits_total"),
+			Help: "Total number of tool responses served from the response cache, per tool.",
+		},
+		[]string{"tool_name"},
+	)
+
+	metricCacheMisses = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: prometheus.BuildFQName(metrics.MetricNamespace, "cache", "misses_total"),
+			Help: "Total number of tool responses not found in the response cache, per tool.",
+		},
+		[]string{"tool_name"},
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		metricCacheHits,
+		metricCacheMisses,
+	)
+}
+
+type responseCacheEntry struct {
+	value   string
+	expires time.Time
+}
+
+// responseCache is an in-memory TTL cache for the responses of idempotent
+// read tools. A nil *responseCache is valid and caches nothing.
+type responseCache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu        sync.Mutex
+	entries   map[string]responseCacheEntry
+	lastSweep time.Time
+}
+
+// newResponseCache returns a response cache with the given TTL, or nil if
+// the TTL is not positive, which disables caching.
+func newResponseCache(ttl time.Duration) *responseCache {
+	if ttl <= 0 {
+		return nil
+	}
+	return &responseCache{
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[string]responseCacheEntry),
+	}
+}
+
+// ttlOrZero returns how long responses are cached, or 0 if caching is disabled.
+func (c *responseCache) ttlOrZero() time.Duration {
+	if c == nil {
+		return 0
+	}
+	return c.ttl
+}
+
+func (c *responseCache) get(key string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return "", false
+	}
+	if !c.now().Before(entry.expires) {
+		delete(c.entries, key)
+		return "", false
+	}
+	return entry.value, true
+}
+
+func (c *responseCache) set(key, value string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	c.entries[key] = responseCacheEntry{value: value, expires: now.Add(c.ttl)}
+
+	// Expired entries are otherwise only dropped when looked up again, so
+	// sweep them at most once per TTL to keep memory bounded.
+	if now.Sub(c.lastSweep) < c.ttl {
+		return
+	}
+	for k, entry := range c.entries {
+		if !now.Before(entry.expires) {
+			delete(c.entries, k)
+		}
+	}
+	c.lastSweep = now
+}
+
+// cachedAPICall returns the cached response for the tool and arguments if
+// there is one, and otherwise calls fn and caches its response on success.
+// The key also covers the backend and credentials of the request, so
+// responses are never shared between backends or clients with different
+// access. Callers are expected to pass normalized arguments (e.g. sorted
+// matchers) so that equivalent calls share an entry.
+func (s *ServerContainer) cachedAPICall(ctx context.Context, tool string, args []any, fn func() (string, error)) (string, error) {
+	if s.responseCache == nil {
+		return fn()
+	}
+
+	key, err := s.responseCacheKey(ctx, tool, args)
+	if err != nil {
+		s.logger.Debug("Failed to build response cache key, bypassing cache", "tool_name", tool, "err", err)
+		return fn()
+	}
+
+	if value, ok := s.responseCache.get(key); ok {
+		metricCacheHits.With(prometheus.Labels{"tool_name": tool}).Inc()
+		return value, nil
+	}
+	metricCacheMisses.With(prometheus.Labels{"tool_name": tool}).Inc()
+
+	value, err := fn()
+	if err != nil {
+		return "", err
+	}
+	s.responseCache.set(key, value)
+	return value, nil
+}
+
+// responseCacheKey hashes the tool name, arguments, and request scope into a
+// cache key, so credentials are not kept in memory in the clear.
+func (s *ServerContainer) responseCacheKey(ctx context.Context, tool string, args []any) (string, error) {
+	encodedArgs, err := json.Marshal(args)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode arguments: %w", err)
+	}
+
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00%s\x00%s\x00", tool, s.getPrometheusURL(ctx), getAuthFromContext(ctx), s.tenant(ctx))
+	if cfg := getHTTPConfigFromContext(ctx); cfg != nil {
+		fmt.Fprint(h, hashHTTPClientConfig(cfg))
+	}
+	h.Write([]byte{0})
+	if headers := s.forwardedHeaders(ctx); len(headers) > 0 {
+		for _, name := range s.forwardHeaders {
+			fmt.Fprintf(h, "%s\x00%q\x00", name, headers.Values(name))
+		}
+	}
+	h.Write([]byte{0})
+	h.Write(encodedArgs)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
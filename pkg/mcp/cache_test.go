@@ -0,0 +1,178 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcp
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	promv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prometheus/prometheus-mcp/pkg/mcp/mcptest"
+)
+
+func TestResponseCache(t *testing.T) {
+	t.Parallel()
+
+	require.Nil(t, newResponseCache(0))
+	require.Nil(t, newResponseCache(-time.Second))
+
+	now := time.Unix(1700000000, 0)
+	c := newResponseCache(time.Minute)
+	c.now = func() time.Time { return now }
+
+	_, ok := c.get("a")
+	require.False(t, ok)
+
+	c.set("a", "value")
+	v, ok := c.get("a")
+	require.True(t, ok)
+	require.Equal(t, "value", v)
+
+	now = now.Add(30 * time.Second)
+	c.set("b", "other")
+
+	now = now.Add(30 * time.Second)
+	_, ok = c.get("a")
+	require.False(t, ok, "entry should expire after the TTL")
+	_, ok = c.get("b")
+	require.True(t, ok)
+
+	// Setting an entry after a full TTL sweeps the expired ones.
+	now = now.Add(time.Minute)
+	c.set("c", "new")
+	require.Len(t, c.entries, 1)
+}
+
+func TestCachedAPICalls(t *testing.T) {
+	t.Parallel()
+
+	var labelNamesCalls, labelValuesCalls, metadataCalls, queryCalls atomic.Int32
+	mockAPI := &MockPrometheusAPI{
+		LabelNamesFunc: func(ctx context.Context, matches []string, startTime time.Time, endTime time.Time, opts ...promv1.Option) ([]string, promv1.Warnings, error) {
+			labelNamesCalls.Add(1)
+			return []string{"__name__", "job"}, nil, nil
+		},
+		LabelValuesFunc: func(ctx context.Context, label string, matches []string, startTime time.Time, endTime time.Time, opts ...promv1.Option) (model.LabelValues, promv1.Warnings, error) {
+			labelValuesCalls.Add(1)
+			return model.LabelValues{"node", "prometheus"}, nil, nil
+		},
+		MetadataFunc: func(ctx context.Context, metric string, limit string) (map[string][]promv1.Metadata, error) {
+			metadataCalls.Add(1)
+			return map[string][]promv1.Metadata{
+				"up": {{Type: "gauge", Help: "target health"}},
+			}, nil
+		},
+		QueryFunc: func(ctx context.Context, query string, ts time.Time, opts ...promv1.Option) (model.Value, promv1.Warnings, error) {
+			queryCalls.Add(1)
+			return model.Vector{}, nil, nil
+		},
+	}
+	container := newTestContainer(mockAPI)
+	container.responseCache = newResponseCache(time.Minute)
+
+	ts := mcptest.NewTestServer(t)
+	mcptest.AddTool(ts, labelNamesToolDef, container.LabelNamesHandler)
+	mcptest.AddTool(ts, labelValuesToolDef, container.LabelValuesHandler)
+	mcptest.AddTool(ts, metricMetadataToolDef, container.MetricMetadataHandler)
+	mcptest.AddTool(ts, queryToolDef, container.QueryHandler)
+
+	call := func(ctx context.Context, tool string, args map[string]any) string {
+		t.Helper()
+		result, err := ts.CallTool(ctx, tool, args)
+		require.NoError(t, err)
+		require.False(t, result.IsError, mcptest.GetResultText(result))
+		return mcptest.GetResultText(result)
+	}
+
+	hitsBefore := testutil.ToFloat64(metricCacheHits.WithLabelValues("label_names"))
+	missesBefore := testutil.ToFloat64(metricCacheMisses.WithLabelValues("label_names"))
+
+	first := call(ts.Context(), "label_names", map[string]any{"matches": []string{`up`, `{job="node"}`}})
+	second := call(ts.Context(), "label_names", map[string]any{"matches": []string{`{job="node"}`, `up`}})
+	require.Equal(t, first, second)
+	require.Equal(t, int32(1), labelNamesCalls.Load(), "matchers in a different order should hit the cache")
+	require.InDelta(t, 1, testutil.ToFloat64(metricCacheHits.WithLabelValues("label_names"))-hitsBefore, 0)
+	require.InDelta(t, 1, testutil.ToFloat64(metricCacheMisses.WithLabelValues("label_names"))-missesBefore, 0)
+
+	call(ts.Context(), "label_names", map[string]any{"matches": []string{`up`}})
+	require.Equal(t, int32(2), labelNamesCalls.Load(), "different arguments should miss the cache")
+
+	// Relative times resolve to a new time on every call, but still hit the
+	// cache.
+	call(ts.Context(), "label_names", map[string]any{"start_time": "now-1h"})
+	call(ts.Context(), "label_names", map[string]any{"start_time": "now-1h"})
+	require.Equal(t, int32(3), labelNamesCalls.Load(), "relative times should hit the cache")
+
+	call(ts.Context(), "label_values", map[string]any{"label": "job"})
+	call(ts.Context(), "label_values", map[string]any{"label": "job"})
+	require.Equal(t, int32(1), labelValuesCalls.Load(), "calls without a time range should hit the cache")
+	call(ts.Context(), "label_values", map[string]any{"label": "job", "end_time": "now-5m"})
+	call(ts.Context(), "label_values", map[string]any{"label": "job", "end_time": "now-5m"})
+	require.Equal(t, int32(2), labelValuesCalls.Load(), "relative times should hit the cache")
+
+	call(ts.Context(), "metric_metadata", map[string]any{"metric": "up"})
+	withHelp := call(ts.Context(), "metric_metadata", map[string]any{"metric": "up"})
+	withoutHelp := call(ts.Context(), "metric_metadata", map[string]any{"metric": "up", "strip_help": true})
+	require.Equal(t, int32(2), metadataCalls.Load())
+	require.Contains(t, withHelp, "target health")
+	require.NotContains(t, withoutHelp, "target health")
+
+	call(ts.Context(), "query", map[string]any{"query": "up"})
+	call(ts.Context(), "query", map[string]any{"query": "up"})
+	require.Equal(t, int32(2), queryCalls.Load(), "queries should never be cached")
+}
+
+func TestResponseCacheKeyScope(t *testing.T) {
+	t.Parallel()
+
+	container := newTestContainer(nil)
+	container.prometheusTargets = map[string]prometheusTarget{
+		"other": {url: "http://other:9090"},
+	}
+	args := []any{"up"}
+
+	key := func(ctx context.Context) string {
+		t.Helper()
+		k, err := container.responseCacheKey(ctx, "label_names", args)
+		require.NoError(t, err)
+		return k
+	}
+
+	ctx := context.Background()
+	base := key(ctx)
+	require.Equal(t, base, key(ctx))
+	require.NotEqual(t, base, key(addAuthToContext(ctx, "Bearer token")), "credentials should be part of the key")
+	require.NotEqual(t, base, key(addTargetToContext(ctx, "other")), "the backend should be part of the key")
+
+	container.prometheusBackend = "mimir"
+	require.NotEqual(t, key(addTenantToContext(ctx, "team-a")), key(addTenantToContext(ctx, "team-b")), "the Mimir tenant should be part of the key")
+
+	container.forwardHeaders = []string{"X-Team"}
+	headersCtx := func(name, value string) context.Context {
+		return addRequestHeadersToContext(ctx, http.Header{name: []string{value}})
+	}
+	require.NotEqual(t, key(headersCtx("X-Team", "a")), key(headersCtx("X-Team", "b")), "forwarded headers should be part of the key")
+	require.Equal(t, key(headersCtx("X-Other", "a")), key(headersCtx("X-Other", "b")), "other headers should not be part of the key")
+
+	other, err := container.responseCacheKey(ctx, "label_values", args)
+	require.NoError(t, err)
+	require.NotEqual(t, base, other, "the tool name should be part of the key")
+}
@@ -0,0 +1,109 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcp
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/sync/semaphore"
+
+	"github.com/prometheus/prometheus-mcp/internal/metrics"
+)
+
+var metricAPIRequestsInFlight = prometheus.NewGauge(
+	prometheus.GaugeOpts{
+		Name: prometheus.BuildFQName(metrics.MetricNamespace, "api", "requests_in_flight"),
+		Help: "Number of requests to the Prometheus backend currently in flight.",
+	},
+)
+
+func init() {
+	metrics.Registry.MustRegister(metricAPIRequestsInFlight)
+}
+
+// apiCallLimiter limits the number of concurrent requests to the Prometheus
+// backend. It is shared by all round trippers to the backend, so the limit
+// applies across all MCP sessions and credentials.
+type apiCallLimiter struct {
+	limit int64
+	sem   *semaphore.Weighted
+}
+
+// newAPICallLimiter returns a limiter allowing limit concurrent requests, or
+// nil if limit is not positive.
+func newAPICallLimiter(limit int) *apiCallLimiter {
+	if limit <= 0 {
+		return nil
+	}
+	return &apiCallLimiter{
+		limit: int64(limit),
+		sem:   semaphore.NewWeighted(int64(limit)),
+	}
+}
+
+// concurrencyLimitRoundTripper tracks requests in flight to the Prometheus
+// backend and, with a limiter, waits for a free slot before passing a request
+// to the next round tripper. Requests queue until the deadline of their
+// context, which API calls set from --prometheus.timeout.
+type concurrencyLimitRoundTripper struct {
+	limiter *apiCallLimiter
+	next    http.RoundTripper
+}
+
+func (rt *concurrencyLimitRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	next := rt.next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	release := func() {}
+	if rt.limiter != nil {
+		if err := rt.limiter.sem.Acquire(req.Context(), 1); err != nil {
+			return nil, fmt.Errorf("timed out waiting for a free request slot, %d concurrent requests to Prometheus are allowed by --prometheus.max-concurrent-requests: %w", rt.limiter.limit, err)
+		}
+		release = func() { rt.limiter.sem.Release(1) }
+	}
+	metricAPIRequestsInFlight.Inc()
+	done := func() {
+		metricAPIRequestsInFlight.Dec()
+		release()
+	}
+
+	resp, err := next.RoundTrip(req)
+	if err != nil {
+		done()
+		return nil, err
+	}
+
+	// The request is in flight until its response body is closed.
+	resp.Body = &releasingBody{ReadCloser: resp.Body, release: done}
+	return resp, nil
+}
+
+// releasingBody calls release once when the response body is closed.
+type releasingBody struct {
+	io.ReadCloser
+	once    sync.Once
+	release func()
+}
+
+func (b *releasingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+	return err
+}
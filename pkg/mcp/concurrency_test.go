@@ -0,0 +1,96 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcp
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewAPICallLimiter(t *testing.T) {
+	t.Parallel()
+
+	require.Nil(t, newAPICallLimiter(0))
+	require.Nil(t, newAPICallLimiter(-1))
+
+	l := newAPICallLimiter(4)
+	require.NotNil(t, l)
+	require.Equal(t, int64(4), l.limit)
+}
+
+// TestConcurrencyLimitRoundTripper is not run in parallel, as it checks the
+// global in-flight gauge that other tests' API calls change.
+func TestConcurrencyLimitRoundTripper(t *testing.T) {
+	next := &mockRoundTripper{RoundTripFunc: func(req *http.Request) (*http.Response, error) {
+		if req.URL.Path == "/fail" {
+			return nil, errors.New("connection refused")
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("ok"))}, nil
+	}}
+	rt := &concurrencyLimitRoundTripper{limiter: newAPICallLimiter(1), next: next}
+	inFlight := func() float64 {
+		return testutil.ToFloat64(metricAPIRequestsInFlight)
+	}
+	baseline := inFlight()
+
+	// The slot is held until the response body is closed.
+	resp, err := rt.RoundTrip(httptest.NewRequest(http.MethodGet, "http://prometheus/api/v1/query", nil))
+	require.NoError(t, err)
+	require.InDelta(t, baseline+1, inFlight(), 0)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = rt.RoundTrip(httptest.NewRequest(http.MethodGet, "http://prometheus/api/v1/query", nil).WithContext(ctx))
+	require.ErrorContains(t, err, "--prometheus.max-concurrent-requests")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	require.NoError(t, resp.Body.Close())
+	require.NoError(t, resp.Body.Close(), "closing twice must not release the slot twice")
+	require.InDelta(t, baseline, inFlight(), 0)
+
+	// Failed requests release their slot right away.
+	_, err = rt.RoundTrip(httptest.NewRequest(http.MethodGet, "http://prometheus/fail", nil))
+	require.ErrorContains(t, err, "connection refused")
+	require.InDelta(t, baseline, inFlight(), 0)
+
+	resp, err = rt.RoundTrip(httptest.NewRequest(http.MethodGet, "http://prometheus/api/v1/query", nil))
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, "ok", string(body))
+	require.NoError(t, resp.Body.Close())
+
+	// Without a limiter, requests are only tracked.
+	unlimited := &concurrencyLimitRoundTripper{next: next}
+	var bodies []io.Closer
+	for range 3 {
+		resp, err := unlimited.RoundTrip(httptest.NewRequest(http.MethodGet, "http://prometheus/api/v1/query", nil))
+		require.NoError(t, err)
+		bodies = append(bodies, resp.Body)
+	}
+	require.InDelta(t, baseline+3, inFlight(), 0)
+	for _, b := range bodies {
+		require.NoError(t, b.Close())
+	}
+	require.InDelta(t, baseline, inFlight(), 0)
+}
@@ -0,0 +1,527 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcp
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/common/model"
+	"gopkg.in/yaml.v3"
+)
+
+// configSecretPlaceholder is the value Prometheus substitutes for secrets when
+// serving the loaded configuration over the API.
+const configSecretPlaceholder = "<secret>"
+
+// Change types reported by diffPrometheusConfigs.
+const (
+	configChangeAdded    = "added"
+	configChangeRemoved  = "removed"
+	configChangeModified = "modified"
+)
+
+// configChange describes a single difference between the on-disk and loaded
+// Prometheus configuration.
+type configChange struct {
+	Path   string `json:"path"`
+	Change string `json:"change"`
+	OnDisk any    `json:"on_disk,omitempty"`
+	Loaded any    `json:"loaded,omitempty"`
+}
+
+// diffPrometheusConfigs parses and normalizes two Prometheus configuration
+// YAML documents and returns the differences between them, sorted by path.
+//
+// The loaded configuration served by the API has all defaults filled in and
+// secrets redacted, so the comparison is intentionally lenient: keys only
+// present in the loaded config are assumed to be defaults and ignored,
+// redacted secrets match any value, and durations are compared by value.
+// Lists of objects with a `job_name` are matched by job name so that
+// reordering scrape configs is not reported as a change.
+func diffPrometheusConfigs(onDisk, loaded string) ([]configChange, error) {
+	var diskDoc, loadedDoc any
+	if err := yaml.Unmarshal([]byte(onDisk), &diskDoc); err != nil {
+		return nil, fmt.Errorf("failed to parse on-disk config: %w", err)
+	}
+	if err := yaml.Unmarshal([]byte(loaded), &loadedDoc); err != nil {
+		return nil, fmt.Errorf("failed to parse loaded config: %w", err)
+	}
+
+	changes := []configChange{}
+	diffConfigNode("", diskDoc, loadedDoc, &changes)
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+	return changes, nil
+}
+
+func diffConfigNode(path string, disk, loaded any, changes *[]configChange) {
+	switch d := disk.(type) {
+	case map[string]any:
+		l, ok := loaded.(map[string]any)
+		if !ok {
+			*changes = append(*changes, configChange{Path: path, Change: configChangeModified, OnDisk: disk, Loaded: loaded})
+			return
+		}
+		for k, dv := range d {
+			lv, exists := l[k]
+			if !exists {
+				*changes = append(*changes, configChange{Path: joinConfigPath(path, k), Change: configChangeAdded, OnDisk: dv})
+				continue
+			}
+			diffConfigNode(joinConfigPath(path, k), dv, lv, changes)
+		}
+	case []any:
+		l, ok := loaded.([]any)
+		if !ok {
+			*changes = append(*changes, configChange{Path: path, Change: configChangeModified, OnDisk: disk, Loaded: loaded})
+			return
+		}
+		if diskJobs, loadedJobs, ok := indexByJobName(d, l); ok {
+			diffConfigJobs(path, diskJobs, loadedJobs, changes)
+			return
+		}
+		for i := 0; i < max(len(d), len(l)); i++ {
+			itemPath := path + "[" + strconv.Itoa(i) + "]"
+			switch {
+			case i >= len(l):
+				*changes = append(*changes, configChange{Path: itemPath, Change: configChangeAdded, OnDisk: d[i]})
+			case i >= len(d):
+				*changes = append(*changes, configChange{Path: itemPath, Change: configChangeRemoved, Loaded: l[i]})
+			default:
+				diffConfigNode(itemPath, d[i], l[i], changes)
+			}
+		}
+	default:
+		if !configScalarsEqual(disk, loaded) {
+			*changes = append(*changes, configChange{Path: path, Change: configChangeModified, OnDisk: disk, Loaded: loaded})
+		}
+	}
+}
+
+func diffConfigJobs(path string, disk, loaded map[string]any, changes *[]configChange) {
+	for name, dj := range disk {
+		jobPath := path + "[job_name=" + name + "]"
+		lj, exists := loaded[name]
+		if !exists {
+			*changes = append(*changes, configChange{Path: jobPath, Change: configChangeAdded, OnDisk: dj})
+			continue
+		}
+		diffConfigNode(jobPath, dj, lj, changes)
+	}
+	for name, lj := range loaded {
+		if _, exists := disk[name]; !exists {
+			*changes = append(*changes, configChange{Path: path + "[job_name=" + name + "]", Change: configChangeRemoved, Loaded: lj})
+		}
+	}
+}
+
+// indexByJobName indexes both lists by their `job_name` key. It returns false
+// if any item in either list is not an object with a string `job_name`.
+func indexByJobName(disk, loaded []any) (map[string]any, map[string]any, bool) {
+	index := func(items []any) (map[string]any, bool) {
+		out := make(map[string]any, len(items))
+		for _, item := range items {
+			m, ok := item.(map[string]any)
+			if !ok {
+				return nil, false
+			}
+			name, ok := m["job_name"].(string)
+			if !ok {
+				return nil, false
+			}
+			out[name] = item
+		}
+		return out, true
+	}
+
+	if len(disk) == 0 && len(loaded) == 0 {
+		return nil, nil, false
+	}
+	diskJobs, ok := index(disk)
+	if !ok {
+		return nil, nil, false
+	}
+	loadedJobs, ok := index(loaded)
+	if !ok {
+		return nil, nil, false
+	}
+	return diskJobs, loadedJobs, true
+}
+
+func configScalarsEqual(disk, loaded any) bool {
+	if ls, ok := loaded.(string); ok && ls == configSecretPlaceholder {
+		return true
+	}
+	if reflect.DeepEqual(disk, loaded) {
+		return true
+	}
+
+	ds, lss := fmt.Sprint(disk), fmt.Sprint(loaded)
+	if ds == lss {
+		return true
+	}
+
+	// Durations are rendered in a canonical form by Prometheus, so compare
+	// them by value (e.g. `60s` and `1m` are equal).
+	dd, derr := model.ParseDuration(ds)
+	ld, lerr := model.ParseDuration(lss)
+	return derr == nil && lerr == nil && dd == ld
+}
+
+func joinConfigPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// scrapeConfigsSection holds the scrape configs of a Prometheus configuration
+// as YAML nodes, so they can be redacted and re-encoded without losing any
+// fields.
+type scrapeConfigsSection struct {
+	ScrapeConfigs     []yaml.Node `yaml:"scrape_configs"`
+	ScrapeConfigFiles []string    `yaml:"scrape_config_files"`
+}
+
+// parseScrapeConfigs parses the scrape configs out of the Prometheus
+// configuration YAML.
+func parseScrapeConfigs(configYAML string) (scrapeConfigsSection, error) {
+	var cfg scrapeConfigsSection
+	if err := yaml.Unmarshal([]byte(configYAML), &cfg); err != nil {
+		return scrapeConfigsSection{}, fmt.Errorf("failed to parse config: %w", err)
+	}
+	return cfg, nil
+}
+
+// scrapeConfigJobName returns the job name of a scrape config node.
+func scrapeConfigJobName(sc *yaml.Node) (string, error) {
+	var job struct {
+		JobName string `yaml:"job_name"`
+	}
+	if err := sc.Decode(&job); err != nil {
+		return "", fmt.Errorf("failed to parse scrape config: %w", err)
+	}
+	return job.JobName, nil
+}
+
+// findScrapeJobConfig returns the scrape config with the given job name from
+// the Prometheus configuration YAML. If there is none, the error lists the
+// available job names.
+func findScrapeJobConfig(configYAML, jobName string) (*yaml.Node, error) {
+	cfg, err := parseScrapeConfigs(configYAML)
+	if err != nil {
+		return nil, err
+	}
+	return cfg.findJob(jobName)
+}
+
+// findJob returns the scrape config with the given job name. If there is
+// none, the error lists the available job names.
+func (cfg scrapeConfigsSection) findJob(jobName string) (*yaml.Node, error) {
+	jobNames := make([]string, 0, len(cfg.ScrapeConfigs))
+	for i := range cfg.ScrapeConfigs {
+		sc := &cfg.ScrapeConfigs[i]
+		name, err := scrapeConfigJobName(sc)
+		if err != nil {
+			return nil, err
+		}
+		if name == jobName {
+			return sc, nil
+		}
+		jobNames = append(jobNames, name)
+	}
+
+	sort.Strings(jobNames)
+	msg := fmt.Sprintf("job %q not found in the loaded Prometheus config, available jobs: %s", jobName, strings.Join(jobNames, ", "))
+	if len(jobNames) == 0 {
+		msg = fmt.Sprintf("job %q not found, the loaded Prometheus config has no scrape configs", jobName)
+	}
+	if len(cfg.ScrapeConfigFiles) > 0 {
+		msg += ". Jobs from scrape_config_files are not included in the config served by the API"
+	}
+	return nil, errors.New(msg)
+}
+
+// configSecretKeys are config keys whose values are credentials.
+var configSecretKeys = map[string]bool{
+	"password":                      true,
+	"bearer_token":                  true,
+	"credentials":                   true,
+	"client_secret":                 true,
+	"secret_key":                    true,
+	"secret_access_key":             true,
+	"token":                         true,
+	"application_credential_secret": true,
+	"secrets":                       true,
+}
+
+// redactConfigSecrets replaces credentials in a config YAML node with the
+// placeholder Prometheus uses for secrets. Prometheus already redacts secrets
+// in the config it serves, this guards against backends that don't, and also
+// redacts passwords embedded in URLs and inline TLS keys.
+func redactConfigSecrets(node *yaml.Node, parentKey string) {
+	switch node.Kind {
+	case yaml.DocumentNode, yaml.SequenceNode:
+		for _, child := range node.Content {
+			redactConfigSecrets(child, parentKey)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i].Value, node.Content[i+1]
+			if configSecretKeys[key] || (key == "key" && parentKey == "tls_config") {
+				redactConfigScalars(value)
+				continue
+			}
+			redactConfigSecrets(value, key)
+		}
+	case yaml.ScalarNode:
+		if strings.Contains(node.Value, "://") {
+			if u, err := url.Parse(node.Value); err == nil && u.User != nil {
+				node.Value = u.Redacted()
+			}
+		}
+	}
+}
+
+// redactConfigScalars replaces all non-empty scalar values in node with the
+// secret placeholder.
+func redactConfigScalars(node *yaml.Node) {
+	if node.Kind == yaml.ScalarNode {
+		if node.Value != "" {
+			node.Value = configSecretPlaceholder
+			node.Tag = "!!str"
+			node.Style = 0
+		}
+		return
+	}
+	for _, child := range node.Content {
+		redactConfigScalars(child)
+	}
+}
+
+// configDiffContextLines is the number of unchanged lines shown around each
+// change in a unified config diff.
+const configDiffContextLines = 3
+
+// normalizeConfigYAML re-encodes a Prometheus configuration YAML document
+// with secrets redacted, so that two configs can be compared line by line
+// without formatting differences or credentials showing up in the diff.
+func normalizeConfigYAML(configYAML string) (string, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(configYAML), &doc); err != nil {
+		return "", fmt.Errorf("failed to parse config: %w", err)
+	}
+	if doc.Kind == 0 {
+		return "", nil
+	}
+	redactConfigSecrets(&doc, "")
+	resetConfigNodeStyle(&doc)
+
+	var buf strings.Builder
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return "", fmt.Errorf("failed to encode config: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return "", fmt.Errorf("failed to encode config: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// resetConfigNodeStyle drops comments, quoting, and flow style from a config
+// YAML node, matching how Prometheus renders the loaded config.
+func resetConfigNodeStyle(node *yaml.Node) {
+	node.Style = 0
+	node.HeadComment, node.LineComment, node.FootComment = "", "", ""
+	for _, child := range node.Content {
+		resetConfigNodeStyle(child)
+	}
+}
+
+// Line operations of a line diff.
+const (
+	diffLineEqual  = ' '
+	diffLineDelete = '-'
+	diffLineInsert = '+'
+)
+
+type diffLine struct {
+	op   byte
+	text string
+}
+
+// diffLines returns the line operations that turn a into b with the fewest
+// insertions and deletions. It uses Myers' algorithm, which takes time and
+// memory proportional to (len(a)+len(b))·D for D changed lines, so large
+// configs with few changes stay cheap to diff.
+func diffLines(a, b []string) []diffLine {
+	n, m := len(a), len(b)
+	offset := n + m + 1
+	// v[offset+k] is the furthest x reached on diagonal k = x-y. trace[d]
+	// holds v for diagonals -d..d before the d-th change, to walk the path
+	// back.
+	v := make([]int, 2*offset+1)
+	var trace [][]int
+search:
+	for d := 0; d <= n+m; d++ {
+		trace = append(trace, slices.Clone(v[offset-d:offset+d+1]))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				break search
+			}
+		}
+	}
+
+	lines := make([]diffLine, 0, max(n, m))
+	x, y := n, m
+	for d := len(trace) - 1; d > 0; d-- {
+		prev := trace[d]
+		k := x - y
+		prevK := k - 1
+		if k == -d || (k != d && prev[k-1+d] < prev[k+1+d]) {
+			prevK = k + 1
+		}
+		prevX := prev[prevK+d]
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			x--
+			y--
+			lines = append(lines, diffLine{diffLineEqual, a[x]})
+		}
+		if x == prevX {
+			y--
+			lines = append(lines, diffLine{diffLineInsert, b[y]})
+		} else {
+			x--
+			lines = append(lines, diffLine{diffLineDelete, a[x]})
+		}
+	}
+	for x > 0 {
+		x--
+		lines = append(lines, diffLine{diffLineEqual, a[x]})
+	}
+	slices.Reverse(lines)
+	return lines
+}
+
+// unifiedDiff returns a unified diff from a to b with the given number of
+// context lines around each change, or an empty string if they are equal.
+func unifiedDiff(fromName, toName, a, b string, context int) string {
+	lines := diffLines(splitDiffLines(a), splitDiffLines(b))
+
+	var sb strings.Builder
+	// aLine and bLine are the 0-based line numbers in a and b at the start
+	// of lines[i].
+	aLine, bLine := 0, 0
+	for i := 0; i < len(lines); {
+		if lines[i].op == diffLineEqual {
+			aLine++
+			bLine++
+			i++
+			continue
+		}
+
+		// Extend the hunk until a run of unchanged lines is long enough
+		// to separate it from the next change.
+		start := max(i-context, 0)
+		end := i
+		for end < len(lines) {
+			if lines[end].op != diffLineEqual {
+				end++
+				continue
+			}
+			run := end
+			for run < len(lines) && lines[run].op == diffLineEqual {
+				run++
+			}
+			if run == len(lines) || run-end > 2*context {
+				end = min(end+context, len(lines))
+				break
+			}
+			end = run
+		}
+
+		if sb.Len() == 0 {
+			fmt.Fprintf(&sb, "--- %s\n+++ %s\n", fromName, toName)
+		}
+		aStart, bStart := aLine-(i-start), bLine-(i-start)
+		aLen, bLen := 0, 0
+		for _, l := range lines[start:end] {
+			if l.op != diffLineInsert {
+				aLen++
+			}
+			if l.op != diffLineDelete {
+				bLen++
+			}
+		}
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n", diffHunkRange(aStart, aLen), diffHunkRange(bStart, bLen))
+		for _, l := range lines[start:end] {
+			sb.WriteByte(l.op)
+			sb.WriteString(l.text)
+			sb.WriteByte('\n')
+		}
+
+		for _, l := range lines[i:end] {
+			if l.op != diffLineInsert {
+				aLine++
+			}
+			if l.op != diffLineDelete {
+				bLine++
+			}
+		}
+		i = end
+	}
+	return sb.String()
+}
+
+// diffHunkRange formats the range of a unified diff hunk header. start is
+// 0-based, an empty range refers to the line before it.
+func diffHunkRange(start, length int) string {
+	if length == 0 {
+		return strconv.Itoa(start) + ",0"
+	}
+	if length == 1 {
+		return strconv.Itoa(start + 1)
+	}
+	return strconv.Itoa(start+1) + "," + strconv.Itoa(length)
+}
+
+func splitDiffLines(s string) []string {
+	s = strings.TrimSuffix(s, "\n")
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "\n")
+}
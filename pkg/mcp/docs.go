@@ -14,16 +14,21 @@
 package mcp
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"io/fs"
 	"path/filepath"
 	"regexp"
 	"strings"
+	"time"
+	"unicode/utf8"
 )
 
 var (
 	stripFrontmatterRegex = regexp.MustCompile(`(?s)^---\n.*?\n---\n?`)
+	promqlYAMLKeyRegex    = regexp.MustCompile(`^[a-z_]+:(\s|$)`)
 )
 
 // stripFrontmatter removes the frontmatter block from the beginning of the
@@ -67,6 +72,57 @@ func getDocFileContent(fsys fs.FS, path string) (string, error) {
 	return stripFrontmatter(string(content)), nil
 }
 
+// docLineRange returns the lines start through end (1-based, inclusive) of
+// content, along with the effective range and the total number of lines.
+// Out-of-range values are clamped to the bounds of the content, and unset (0)
+// values default to the first and last line respectively.
+func docLineRange(content string, start, end int) (string, int, int, int) {
+	lines := strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+	total := len(lines)
+
+	if start < 1 {
+		start = 1
+	}
+	start = min(start, total)
+	if end < 1 || end > total {
+		end = total
+	}
+	end = max(end, start)
+
+	return strings.Join(lines[start-1:end], "\n"), start, end, total
+}
+
+// docsReadRetryBackoff is the base delay between docs file read retries. It
+// grows linearly with each attempt.
+var docsReadRetryBackoff = 100 * time.Millisecond
+
+// getDocFileContentWithRetries reads a doc file, retrying up to retries times
+// on errors that may be transient (e.g. from a network-mounted filesystem).
+// Errors that won't go away on retry, such as a missing file, are returned
+// immediately, as is the last error once ctx is done.
+func getDocFileContentWithRetries(ctx context.Context, fsys fs.FS, path string, retries int) (string, error) {
+	for attempt := 0; ; attempt++ {
+		content, err := getDocFileContent(fsys, path)
+		if err == nil || attempt >= retries || !isRetryableDocsReadError(err) {
+			return content, err
+		}
+
+		timer := time.NewTimer(docsReadRetryBackoff * time.Duration(attempt+1))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return content, err
+		case <-timer.C:
+		}
+	}
+}
+
+func isRetryableDocsReadError(err error) bool {
+	return !errors.Is(err, fs.ErrNotExist) &&
+		!errors.Is(err, fs.ErrInvalid) &&
+		!errors.Is(err, fs.ErrPermission)
+}
+
 const (
 	docChunkSize           = 8 * 1024
 	docChunkOverlap        = 1 * 1024
@@ -82,3 +138,108 @@ type chunk struct {
 func (c *chunk) String() string {
 	return fmt.Sprintf("%s#%d", c.Name, c.ID)
 }
+
+// docSnippetLength is the approximate length, in bytes, of snippets extracted
+// from matched doc chunks.
+const docSnippetLength = 400
+
+// docSnippet returns a short excerpt of content centered on the first
+// occurrence of any of the query's terms. If no term is found, the beginning
+// of the content is returned.
+func docSnippet(content, query string, maxLen int) string {
+	content = strings.TrimSpace(content)
+	if len(content) <= maxLen {
+		return content
+	}
+
+	lower := strings.ToLower(content)
+	pos := -1
+	for _, term := range strings.Fields(strings.ToLower(query)) {
+		if len(term) < 3 {
+			continue
+		}
+		if i := strings.Index(lower, term); i >= 0 && (pos < 0 || i < pos) {
+			pos = i
+		}
+	}
+
+	start := 0
+	if pos > maxLen/4 {
+		start = pos - maxLen/4
+	}
+	end := min(start+maxLen, len(content))
+
+	// Avoid splitting multi-byte runes at either edge.
+	for start > 0 && !utf8.RuneStart(content[start]) {
+		start--
+	}
+	for end < len(content) && !utf8.RuneStart(content[end]) {
+		end++
+	}
+
+	snippet := content[start:end]
+	if start > 0 {
+		snippet = "..." + snippet
+	}
+	if end < len(content) {
+		snippet += "..."
+	}
+	return snippet
+}
+
+// extractPromQLExamples returns the contents of fenced code blocks in the
+// given markdown that contain PromQL. Blocks tagged with the `promql` language
+// are always included. Untagged blocks are only included when
+// includeUntagged is set, since many docs use them for shell output or
+// configuration snippets.
+func extractPromQLExamples(content string, includeUntagged bool) []string {
+	var (
+		examples []string
+		inFence  bool
+		keep     bool
+		fence    string
+		lang     string
+		block    []string
+	)
+
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if !inFence {
+			if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+				inFence = true
+				fence = trimmed[:3]
+				lang = strings.ToLower(strings.TrimSpace(strings.TrimLeft(trimmed, fence[:1])))
+				keep = lang == "promql" || (lang == "" && includeUntagged)
+				block = block[:0]
+			}
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == "" {
+			inFence = false
+			example := strings.TrimSpace(strings.Join(block, "\n"))
+			if keep && example != "" && (lang == "promql" || looksLikePromQL(example)) {
+				examples = append(examples, example)
+			}
+			continue
+		}
+		block = append(block, line)
+	}
+
+	return examples
+}
+
+// looksLikePromQL is a loose heuristic used to filter untagged code blocks,
+// rejecting obvious shell sessions, JSON, and YAML.
+func looksLikePromQL(block string) bool {
+	if strings.HasPrefix(block, "$") || strings.HasPrefix(block, "{\n") || strings.HasPrefix(block, `{"`) {
+		return false
+	}
+	for _, line := range strings.Split(block, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "curl ") || strings.HasPrefix(line, "- ") || promqlYAMLKeyRegex.MatchString(line) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,242 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcp
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"testing/fstest"
+
+	"github.com/prometheus/common/promslog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDocSnippet(t *testing.T) {
+	t.Parallel()
+
+	t.Run("short content returned as-is", func(t *testing.T) {
+		require.Equal(t, "short content", docSnippet("  short content\n", "content", 100))
+	})
+
+	t.Run("long content centered on first matching term", func(t *testing.T) {
+		content := strings.Repeat("filler ", 100) + "histogram_quantile is used here " + strings.Repeat("tail ", 100)
+		snippet := docSnippet(content, "histogram_quantile", 80)
+		require.Contains(t, snippet, "histogram_quantile")
+		require.True(t, strings.HasPrefix(snippet, "..."))
+		require.True(t, strings.HasSuffix(snippet, "..."))
+	})
+}
+
+func TestDocLineRange(t *testing.T) {
+	t.Parallel()
+
+	content := "line 1\nline 2\nline 3\nline 4\n"
+	testCases := []struct {
+		name       string
+		start, end int
+		want       string
+		wantStart  int
+		wantEnd    int
+	}{
+		{name: "unset returns everything", want: "line 1\nline 2\nline 3\nline 4", wantStart: 1, wantEnd: 4},
+		{name: "middle range", start: 2, end: 3, want: "line 2\nline 3", wantStart: 2, wantEnd: 3},
+		{name: "start only", start: 4, want: "line 4", wantStart: 4, wantEnd: 4},
+		{name: "end only", end: 1, want: "line 1", wantStart: 1, wantEnd: 1},
+		{name: "end past last line is clamped", start: 3, end: 100, want: "line 3\nline 4", wantStart: 3, wantEnd: 4},
+		{name: "start past last line is clamped", start: 100, want: "line 4", wantStart: 4, wantEnd: 4},
+		{name: "negative start is clamped", start: -5, end: 1, want: "line 1", wantStart: 1, wantEnd: 1},
+		{name: "end before start is clamped", start: 3, end: 2, want: "line 3", wantStart: 3, wantEnd: 3},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, start, end, total := docLineRange(content, tc.start, tc.end)
+			require.Equal(t, tc.want, got)
+			require.Equal(t, tc.wantStart, start)
+			require.Equal(t, tc.wantEnd, end)
+			require.Equal(t, 4, total)
+		})
+	}
+}
+
+func TestExtractPromQLExamples(t *testing.T) {
+	t.Parallel()
+
+	content := "# Examples\n\n" +
+		"```promql\nrate(http_requests_total[5m])\n```\n\n" +
+		"```\nsum by (job) (up)\n```\n\n" +
+		"```yaml\nscrape_configs:\n  - job_name: node\n```\n\n" +
+		"```\n$ curl localhost:9090/api/v1/query\n```\n\n" +
+		"```\nglobal:\n  scrape_interval: 15s\n```\n"
+
+	testCases := []struct {
+		name            string
+		includeUntagged bool
+		expected        []string
+	}{
+		{
+			name:            "only promql tagged blocks",
+			includeUntagged: false,
+			expected:        []string{"rate(http_requests_total[5m])"},
+		},
+		{
+			name:            "untagged blocks filtered by heuristic",
+			includeUntagged: true,
+			expected:        []string{"rate(http_requests_total[5m])", "sum by (job) (up)"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, extractPromQLExamples(content, tc.includeUntagged))
+		})
+	}
+}
+
+// flakyFS wraps an fs.FS and fails the next failures opens of doc files with
+// a transient error, simulating a network-mounted filesystem.
+type flakyFS struct {
+	fs.FS
+	failures atomic.Int32
+	opens    atomic.Int32
+}
+
+var errFlakyRead = errors.New("input/output error")
+
+func (f *flakyFS) Open(name string) (fs.File, error) {
+	if !strings.HasSuffix(name, ".md") {
+		return f.FS.Open(name)
+	}
+	f.opens.Add(1)
+	if f.failures.Add(-1) >= 0 {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: errFlakyRead}
+	}
+	return f.FS.Open(name)
+}
+
+func newFlakyFS(failures int32) *flakyFS {
+	f := &flakyFS{FS: fstest.MapFS{
+		"querying/basics.md": &fstest.MapFile{Data: []byte("# Querying Basics")},
+	}}
+	f.failures.Store(failures)
+	return f
+}
+
+func TestGetDocFileContentWithRetries(t *testing.T) {
+	t.Parallel()
+
+	t.Run("succeeds after transient errors", func(t *testing.T) {
+		t.Parallel()
+		fsys := newFlakyFS(2)
+
+		content, err := getDocFileContentWithRetries(context.Background(), fsys, "querying/basics.md", 2)
+		require.NoError(t, err)
+		require.Equal(t, "# Querying Basics", content)
+		require.Equal(t, int32(3), fsys.opens.Load())
+	})
+
+	t.Run("gives up after retries are exhausted", func(t *testing.T) {
+		t.Parallel()
+		fsys := newFlakyFS(3)
+
+		_, err := getDocFileContentWithRetries(context.Background(), fsys, "querying/basics.md", 1)
+		require.ErrorIs(t, err, errFlakyRead)
+		require.Equal(t, int32(2), fsys.opens.Load())
+	})
+
+	t.Run("does not retry missing files", func(t *testing.T) {
+		t.Parallel()
+		fsys := newFlakyFS(0)
+
+		_, err := getDocFileContentWithRetries(context.Background(), fsys, "does/not/exist.md", 3)
+		require.ErrorIs(t, err, fs.ErrNotExist)
+		require.Equal(t, int32(1), fsys.opens.Load())
+	})
+
+	t.Run("stops retrying once the context is done", func(t *testing.T) {
+		t.Parallel()
+		fsys := newFlakyFS(3)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := getDocFileContentWithRetries(ctx, fsys, "querying/basics.md", 3)
+		require.ErrorIs(t, err, errFlakyRead)
+		require.Equal(t, int32(1), fsys.opens.Load())
+	})
+}
+
+func TestGetDocFileContent_ReadRetries(t *testing.T) {
+	t.Parallel()
+
+	newContainer := func(t *testing.T, fsys fs.FS, embedded bool) *ServerContainer {
+		t.Helper()
+		container, err := newServerContainer(context.Background(), ServerConfig{
+			Logger:          promslog.NewNopLogger(),
+			PrometheusURL:   "http://localhost:9090",
+			RoundTripper:    http.DefaultTransport,
+			DocsFS:          fsys,
+			DocsReadRetries: 1,
+			DocsFSEmbedded:  embedded,
+		})
+		require.NoError(t, err)
+		return container
+	}
+
+	t.Run("docs from --docs.dir are retried", func(t *testing.T) {
+		t.Parallel()
+		fsys := newFlakyFS(1)
+		container := newContainer(t, fsys, false)
+		require.Equal(t, 1, container.docsStatus().ReadRetries)
+
+		// The failed read while building the search index was retried.
+		require.Equal(t, int32(2), fsys.opens.Load())
+		indexed, err := container.docs.searchIndex.DocCount()
+		require.NoError(t, err)
+		require.Equal(t, uint64(1), indexed)
+
+		fsys.failures.Store(1)
+		content, err := container.GetDocFileContent(context.Background(), "querying/basics.md")
+		require.NoError(t, err)
+		require.Equal(t, "# Querying Basics", content)
+		require.Equal(t, int32(4), fsys.opens.Load())
+
+		// Docs swapped in by the docs updater are held in memory and not
+		// retried.
+		memFS := newFlakyFS(0)
+		state, err := buildDocsState(context.Background(), promslog.NewNopLogger(), memFS, 0)
+		require.NoError(t, err)
+		container.swapDocsState(state)
+		memFS.failures.Store(1)
+		_, err = container.GetDocFileContent(context.Background(), "querying/basics.md")
+		require.ErrorIs(t, err, errFlakyRead)
+		require.Equal(t, int32(2), memFS.opens.Load())
+	})
+
+	t.Run("embedded docs fail fast", func(t *testing.T) {
+		t.Parallel()
+		fsys := newFlakyFS(0)
+		container := newContainer(t, fsys, true)
+		require.Zero(t, container.docsStatus().ReadRetries)
+
+		fsys.failures.Store(1)
+		_, err := container.GetDocFileContent(context.Background(), "querying/basics.md")
+		require.ErrorIs(t, err, errFlakyRead)
+		require.Equal(t, int32(2), fsys.opens.Load())
+	})
+}
@@ -237,7 +237,10 @@ func (u *DocsUpdater) fetchAndUpdateDocsFS(ctx context.Context) error {
 		return fmt.Errorf("failed to extract docs from archive: %w", err)
 	}
 
-	newState, err := buildDocsState(u.logger, memFS)
+	indexCtx, cancel := u.container.docsIndexContext(ctx)
+	defer cancel()
+
+	newState, err := buildDocsState(indexCtx, u.logger, memFS, 0)
 	if err != nil {
 		return fmt.Errorf("failed to build docs state: %w", err)
 	}
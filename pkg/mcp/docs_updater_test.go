@@ -427,7 +427,7 @@ func TestDocsUpdaterFetchAndApply(t *testing.T) {
 		require.NotEmpty(t, names)
 
 		// Verify the new content is available.
-		content, err := container.GetDocFileContent("querying/basics.md")
+		content, err := container.GetDocFileContent(context.Background(), "querying/basics.md")
 		require.NoError(t, err)
 		require.Contains(t, content, "Updated Basics")
 	})
@@ -483,12 +483,12 @@ func TestDocsUpdaterAtomicSwap(t *testing.T) {
 		initialFS := fstest.MapFS{
 			"old.md": &fstest.MapFile{Data: []byte("# Old")},
 		}
-		initialState, err := buildDocsState(slog.Default(), initialFS)
+		initialState, err := buildDocsState(context.Background(), slog.Default(), initialFS, 0)
 		require.NoError(t, err)
 		container.swapDocsState(initialState)
 
 		// Verify initial state.
-		content, err := container.GetDocFileContent("old.md")
+		content, err := container.GetDocFileContent(context.Background(), "old.md")
 		require.NoError(t, err)
 		require.Contains(t, content, "Old")
 
@@ -496,17 +496,17 @@ func TestDocsUpdaterAtomicSwap(t *testing.T) {
 		newFS := fstest.MapFS{
 			"new.md": &fstest.MapFile{Data: []byte("# New")},
 		}
-		newState, err := buildDocsState(slog.Default(), newFS)
+		newState, err := buildDocsState(context.Background(), slog.Default(), newFS, 0)
 		require.NoError(t, err)
 		container.swapDocsState(newState)
 
 		// Verify new state.
-		content, err = container.GetDocFileContent("new.md")
+		content, err = container.GetDocFileContent(context.Background(), "new.md")
 		require.NoError(t, err)
 		require.Contains(t, content, "New")
 
 		// Old file should not exist.
-		_, err = container.GetDocFileContent("old.md")
+		_, err = container.GetDocFileContent(context.Background(), "old.md")
 		require.Error(t, err)
 	})
 
@@ -519,7 +519,7 @@ func TestDocsUpdaterAtomicSwap(t *testing.T) {
 		initialFS := fstest.MapFS{
 			"test.md": &fstest.MapFile{Data: []byte("# Initial")},
 		}
-		initialState, err := buildDocsState(slog.Default(), initialFS)
+		initialState, err := buildDocsState(context.Background(), slog.Default(), initialFS, 0)
 		require.NoError(t, err)
 		container.swapDocsState(initialState)
 
@@ -548,7 +548,7 @@ func TestDocsUpdaterAtomicSwap(t *testing.T) {
 			newFS := fstest.MapFS{
 				"test.md": &fstest.MapFile{Data: []byte("# Updated")},
 			}
-			newState, err := buildDocsState(slog.Default(), newFS)
+			newState, err := buildDocsState(context.Background(), slog.Default(), newFS, 0)
 			require.NoError(t, err)
 			container.swapDocsState(newState)
 			time.Sleep(time.Millisecond)
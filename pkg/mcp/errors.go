@@ -17,6 +17,8 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 
 	promv1 "github.com/prometheus/client_golang/api/prometheus/v1"
 )
@@ -24,15 +26,29 @@ import (
 // ErrEndpointNotSupported indicates that a Prometheus API endpoint returned
 // HTTP 404, which typically means the endpoint is not available on the running
 // version of Prometheus. This wraps the original error for unwrapping.
+//
+// Backend is set when a Prometheus compatible backend other than Prometheus
+// itself is configured, since those may not implement the endpoint at all.
 type ErrEndpointNotSupported struct {
 	Endpoint   string
 	StatusCode int
+	Backend    string
 	Err        error
 }
 
 // Error returns a user-friendly message explaining the 404 and suggesting
 // version-related remediation.
 func (e *ErrEndpointNotSupported) Error() string {
+	if e.Backend != "" {
+		return fmt.Sprintf(
+			"the API endpoint %q returned HTTP %d (%s) -- "+
+				"this endpoint is not supported by the %s backend.",
+			e.Endpoint,
+			e.StatusCode,
+			http.StatusText(e.StatusCode),
+			e.Backend,
+		)
+	}
 	return fmt.Sprintf(
 		"the API endpoint %q returned HTTP %d (%s) -- "+
 			"this endpoint may not be supported by your version of Prometheus. "+
@@ -48,36 +64,124 @@ func (e *ErrEndpointNotSupported) Unwrap() error {
 	return e.Err
 }
 
-// isNotFoundError checks whether an error returned by the prometheus
-// client_golang API client represents an HTTP 404 Not Found response.
+// ErrAccessDenied indicates that a Prometheus API endpoint returned HTTP 401
+// or 403, which typically means the configured credentials are missing or
+// insufficient. This wraps the original error, if any, for unwrapping.
+//
+// Reason holds the start of the response body, if any, since Prometheus
+// explains some 403 responses there, e.g. a disabled lifecycle API.
+type ErrAccessDenied struct {
+	Endpoint   string
+	StatusCode int
+	Reason     string
+	Err        error
+}
+
+// maxAccessDeniedReasonLen caps the length of ErrAccessDenied reasons, as
+// proxies in front of Prometheus may return whole HTML pages.
+const maxAccessDeniedReasonLen = 256
+
+// Error returns a user-friendly message explaining the 401 or 403 and
+// suggesting auth or config related remediation.
+func (e *ErrAccessDenied) Error() string {
+	var msg string
+	if e.StatusCode == http.StatusUnauthorized {
+		msg = fmt.Sprintf(
+			"the API endpoint %q returned HTTP %d (%s) -- "+
+				"authentication is required. Check the credentials in the --http.config file, "+
+				"or the Authorization header sent by the MCP client.",
+			e.Endpoint,
+			e.StatusCode,
+			http.StatusText(e.StatusCode),
+		)
+	} else {
+		msg = fmt.Sprintf(
+			"the API endpoint %q returned HTTP %d (%s) -- "+
+				"access was denied. Check that the credentials in the --http.config file, "+
+				"or the Authorization header sent by the MCP client, are allowed to access this endpoint. "+
+				"Prometheus also denies access to the lifecycle and TSDB admin APIs unless started with "+
+				"--web.enable-lifecycle or --web.enable-admin-api respectively.",
+			e.Endpoint,
+			e.StatusCode,
+			http.StatusText(e.StatusCode),
+		)
+	}
+	if e.Reason != "" {
+		msg += fmt.Sprintf(" Response: %q", e.Reason)
+	}
+	return msg
+}
+
+// Unwrap returns the underlying error for errors.Is/As compatibility.
+func (e *ErrAccessDenied) Unwrap() error {
+	return e.Err
+}
+
+// newErrAccessDenied returns an ErrAccessDenied for the endpoint, with the
+// response body trimmed to a short reason.
+func newErrAccessDenied(endpoint string, statusCode int, body string, err error) *ErrAccessDenied {
+	reason := strings.TrimSpace(body)
+	if len(reason) > maxAccessDeniedReasonLen {
+		reason = strings.ToValidUTF8(reason[:maxAccessDeniedReasonLen], "") + "..."
+	}
+	return &ErrAccessDenied{
+		Endpoint:   endpoint,
+		StatusCode: statusCode,
+		Reason:     reason,
+		Err:        err,
+	}
+}
+
+// clientErrorStatusCode returns the HTTP status code of an error returned by
+// the prometheus client_golang API client for a 4xx response, or 0 if the
+// error doesn't represent one.
 //
 // client_golang constructs 4xx error messages as "client error: <status_code>"
-// (see the ErrorType/Error types in prometheus/client_golang). We use exact
-// equality rather than substring matching to avoid false positives from
-// messages that happen to contain "404" (e.g. "client error: 4040").
-func isNotFoundError(err error) bool {
+// (see the ErrorType/Error types in prometheus/client_golang). We parse the
+// whole status code rather than substring matching to avoid false positives
+// from messages that happen to contain one (e.g. "client error: 4040").
+func clientErrorStatusCode(err error) int {
 	if err == nil {
-		return false
+		return 0
 	}
 
 	var promErr *promv1.Error
-	if !errors.As(err, &promErr) {
-		return false
+	if !errors.As(err, &promErr) || promErr.Type != promv1.ErrClient {
+		return 0
 	}
 
-	return promErr.Type == promv1.ErrClient && promErr.Msg == "client error: 404"
+	code, ok := strings.CutPrefix(promErr.Msg, "client error: ")
+	if !ok {
+		return 0
+	}
+	statusCode, err := strconv.Atoi(code)
+	if err != nil || statusCode < 400 || statusCode > 499 {
+		return 0
+	}
+	return statusCode
+}
+
+// isNotFoundError checks whether an error returned by the prometheus
+// client_golang API client represents an HTTP 404 Not Found response.
+func isNotFoundError(err error) bool {
+	return clientErrorStatusCode(err) == http.StatusNotFound
 }
 
-// wrapErrorIfNotFound wraps a 404 error from client_golang into
-// ErrEndpointNotSupported with the given endpoint path. Non-404 errors are
-// returned unchanged.
-func wrapErrorIfNotFound(err error, endpoint string) error {
-	if isNotFoundError(err) {
+// wrapClientError wraps a 404 error from client_golang into
+// ErrEndpointNotSupported, and 401 and 403 errors into ErrAccessDenied, with
+// the given endpoint path. Other errors are returned unchanged.
+func wrapClientError(err error, endpoint string) error {
+	switch statusCode := clientErrorStatusCode(err); statusCode {
+	case http.StatusNotFound:
 		return &ErrEndpointNotSupported{
 			Endpoint:   endpoint,
 			StatusCode: http.StatusNotFound,
 			Err:        err,
 		}
+	case http.StatusUnauthorized, http.StatusForbidden:
+		var promErr *promv1.Error
+		errors.As(err, &promErr)
+		return newErrAccessDenied(endpoint, statusCode, promErr.Detail, err)
 	}
 	return err
 }
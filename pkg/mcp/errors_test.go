@@ -17,7 +17,9 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 	"testing"
+	"unicode/utf8"
 
 	promv1 "github.com/prometheus/client_golang/api/prometheus/v1"
 	"github.com/stretchr/testify/require"
@@ -92,7 +94,7 @@ func TestIsNotFoundError(t *testing.T) {
 	}
 }
 
-func TestWrapNotFoundError(t *testing.T) {
+func TestWrapClientError(t *testing.T) {
 	t.Parallel()
 
 	t.Run("wraps 404 error into ErrEndpointNotSupported", func(t *testing.T) {
@@ -104,7 +106,7 @@ func TestWrapNotFoundError(t *testing.T) {
 		}
 		endpoint := "/api/v1/status/tsdb/blocks"
 
-		wrapped := wrapErrorIfNotFound(origErr, endpoint)
+		wrapped := wrapClientError(origErr, endpoint)
 
 		var notSupported *ErrEndpointNotSupported
 		require.ErrorAs(t, wrapped, &notSupported)
@@ -122,7 +124,7 @@ func TestWrapNotFoundError(t *testing.T) {
 		}
 		endpoint := "/api/v1/status/tsdb/blocks"
 
-		wrapped := wrapErrorIfNotFound(origErr, endpoint)
+		wrapped := wrapClientError(origErr, endpoint)
 
 		require.Equal(t, origErr, wrapped)
 	})
@@ -133,7 +135,7 @@ func TestWrapNotFoundError(t *testing.T) {
 		origErr := errors.New("connection refused")
 		endpoint := "/api/v1/status/tsdb/blocks"
 
-		wrapped := wrapErrorIfNotFound(origErr, endpoint)
+		wrapped := wrapClientError(origErr, endpoint)
 
 		require.Equal(t, origErr, wrapped)
 	})
@@ -153,4 +155,90 @@ func TestErrEndpointNotSupportedError(t *testing.T) {
 	require.Contains(t, msg, "Not Found")
 	require.Contains(t, msg, "may not be supported by your version of Prometheus")
 	require.Contains(t, msg, "build_info")
+
+	err.Backend = "victoriametrics"
+	msg = err.Error()
+	require.Contains(t, msg, "/api/v1/status/tsdb/blocks")
+	require.Contains(t, msg, "not supported by the victoriametrics backend")
+	require.NotContains(t, msg, "build_info")
+}
+
+func TestWrapClientErrorAccessDenied(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name       string
+		err        error
+		statusCode int
+		reason     string
+	}{
+		{
+			name:       "401 error",
+			err:        &promv1.Error{Type: promv1.ErrClient, Msg: "client error: 401", Detail: "Unauthorized\n"},
+			statusCode: http.StatusUnauthorized,
+			reason:     "Unauthorized",
+		},
+		{
+			name:       "wrapped 403 error",
+			err:        fmt.Errorf("outer: %w", &promv1.Error{Type: promv1.ErrClient, Msg: "client error: 403"}),
+			statusCode: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			wrapped := wrapClientError(tc.err, "/api/v1/query")
+
+			var accessDenied *ErrAccessDenied
+			require.ErrorAs(t, wrapped, &accessDenied)
+			require.Equal(t, "/api/v1/query", accessDenied.Endpoint)
+			require.Equal(t, tc.statusCode, accessDenied.StatusCode)
+			require.Equal(t, tc.reason, accessDenied.Reason)
+			require.ErrorIs(t, wrapped, tc.err)
+		})
+	}
+
+	t.Run("passes through other client errors unchanged", func(t *testing.T) {
+		t.Parallel()
+
+		origErr := &promv1.Error{Type: promv1.ErrClient, Msg: "client error: 4010"}
+		require.Equal(t, origErr, wrapClientError(origErr, "/api/v1/query"))
+	})
+}
+
+func TestErrAccessDeniedError(t *testing.T) {
+	t.Parallel()
+
+	err := &ErrAccessDenied{
+		Endpoint:   "/api/v1/query",
+		StatusCode: http.StatusUnauthorized,
+	}
+
+	msg := err.Error()
+	require.Contains(t, msg, "/api/v1/query")
+	require.Contains(t, msg, "401 (Unauthorized)")
+	require.Contains(t, msg, "authentication is required")
+	require.Contains(t, msg, "--http.config")
+	require.NotContains(t, msg, "Response:")
+
+	err.StatusCode = http.StatusForbidden
+	err.Reason = "Lifecycle API is not enabled"
+	msg = err.Error()
+	require.Contains(t, msg, "403 (Forbidden)")
+	require.Contains(t, msg, "access was denied")
+	require.Contains(t, msg, "--web.enable-lifecycle")
+	require.Contains(t, msg, `Response: "Lifecycle API is not enabled"`)
+}
+
+func TestNewErrAccessDeniedTruncatesReason(t *testing.T) {
+	t.Parallel()
+
+	body := "  " + strings.Repeat("é", maxAccessDeniedReasonLen) + "\n"
+	err := newErrAccessDenied("/-/reload", http.StatusForbidden, body, nil)
+
+	require.True(t, strings.HasSuffix(err.Reason, "..."))
+	require.LessOrEqual(t, len(err.Reason), maxAccessDeniedReasonLen+len("..."))
+	require.True(t, utf8.ValidString(err.Reason))
 }
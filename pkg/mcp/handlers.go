@@ -15,21 +15,37 @@ package mcp
 
 import (
 	"context"
+	"crypto/subtle"
+	"encoding/csv"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"math"
+	"net"
 	"net/http"
 	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	promv1 "github.com/prometheus/client_golang/api/prometheus/v1"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/common/model"
+	promversion "github.com/prometheus/common/version"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/relabel"
+	"github.com/prometheus/prometheus/promql/parser"
+	"gopkg.in/yaml.v3"
 
 	"github.com/prometheus/prometheus-mcp/internal/metrics"
 	mcpProm "github.com/prometheus/prometheus-mcp/pkg/prometheus"
@@ -37,7 +53,8 @@ import (
 
 // Constants and shared types for handlers.
 var (
-	// DefaultLookbackDelta is the default time range for queries.
+	// DefaultLookbackDelta is the default time range for queries when no
+	// lookback delta is configured with --prometheus.lookback-delta.
 	DefaultLookbackDelta = -5 * time.Minute
 
 	// Prometheus metrics for API call instrumentation.
@@ -60,6 +77,7 @@ var (
 	)
 
 	errTSDBAdminToolsNotEnabled = errors.New("TSDB admin tools must be enabled with `--dangerous.enable-tsdb-admin-tools` flag")
+	errConfigPathNotSet         = errors.New("the Prometheus config file path must be set with the `--prometheus.config-path` flag")
 )
 
 // Management API endpoint constants.
@@ -74,6 +92,27 @@ const (
 	// queries when step is not explicitly provided. The step is auto-calculated
 	// to produce approximately this many data points across the query range.
 	defaultRangeQueryDataPoints = 250
+
+	// prometheusMaxPointsPerSeries is the maximum number of points per series
+	// Prometheus will return for a range query. Queries with a smaller step
+	// over a longer range are rejected by the server.
+	prometheusMaxPointsPerSeries = 11000
+
+	// defaultLabelExplosionThreshold is the number of distinct values above
+	// which the label_explosion tool flags a label.
+	defaultLabelExplosionThreshold = 1000
+
+	// defaultWhyNoDataLookback is how far back the why_no_data tool looks
+	// for series that went stale, if no lookback is given.
+	defaultWhyNoDataLookback = time.Hour
+
+	// whyNoDataMaxDownTargets is the maximum number of down targets listed
+	// per job by the why_no_data tool.
+	whyNoDataMaxDownTargets = 10
+
+	// labelExplosionSampleSize is the number of label values included in
+	// the label_explosion tool's response.
+	labelExplosionSampleSize = 10
 )
 
 func init() {
@@ -87,6 +126,8 @@ func init() {
 type queryAPIResponse struct {
 	Result   string          `json:"result"`
 	Warnings promv1.Warnings `json:"warnings"`
+	Notes    []string        `json:"notes,omitempty"`
+	Range    *QueryRange     `json:"range,omitempty"`
 }
 
 // truncateStringByLines truncates a string to the specified number of lines.
@@ -117,6 +158,52 @@ func truncateStringByLines(s string, limit int) (string, bool) {
 	return s[:endMarker], true
 }
 
+// truncateStringByBytes truncates a string to at most the specified number of
+// bytes, cutting on a UTF-8 rune boundary.
+// Returns the truncated string and a boolean indicating if truncation occurred.
+func truncateStringByBytes(s string, limit int) (string, bool) {
+	if limit <= 0 || len(s) <= limit {
+		return s, false
+	}
+
+	// Back up to the start of the rune that would be split by the limit, so
+	// multi-byte runes are never cut in half.
+	end := limit
+	for end > 0 && !utf8.RuneStart(s[end]) {
+		end--
+	}
+	return s[:end], true
+}
+
+// charsPerToken is the number of characters assumed per token when
+// estimating the size of results in tokens truncation mode. Tokenizers
+// differ between LLMs, so this is only a rough average for text and code.
+const charsPerToken = 4
+
+// estimateTokens returns an estimate of the number of LLM tokens in s.
+func estimateTokens(s string) int {
+	return (utf8.RuneCountInString(s) + charsPerToken - 1) / charsPerToken
+}
+
+// truncateStringByTokens truncates s to fit within an estimated limit of
+// tokens. The cut is made at the end of the last complete line that fits, or
+// on a character boundary if the first line alone doesn't fit.
+func truncateStringByTokens(s string, limit int) (string, bool) {
+	if limit <= 0 || estimateTokens(s) <= limit {
+		return s, false
+	}
+
+	end := 0
+	for range limit * charsPerToken {
+		_, size := utf8.DecodeRuneInString(s[end:])
+		end += size
+	}
+	if nl := strings.LastIndexByte(s[:end], '\n'); nl >= 0 {
+		end = nl + 1
+	}
+	return s[:end], true
+}
+
 const (
 	truncationWarningTemplate = "\n\n" +
 		"Warning: The result was truncated because the Prometheus MCP server was started with the flag '--prometheus.truncation-limit=%d'.\n" +
@@ -159,7 +246,12 @@ func parseTimeRangeInputWithDefaults(tr TimeRangeInput, startDefault, endDefault
 // Tool handler methods for ServerContainer
 
 // QueryHandler handles the instant query tool.
-func (s *ServerContainer) QueryHandler(ctx context.Context, req *mcp.CallToolRequest, input QueryInput) (*mcp.CallToolResult, any, error) {
+func (s *ServerContainer) QueryHandler(ctx context.Context, req *mcp.CallToolRequest, input QueryInput) (*mcp.CallToolResult, *QueryResultOutput, error) {
+	ctx, err := s.withTarget(ctx, input.Target)
+	if err != nil {
+		return newToolErrorResult(err.Error()), nil, nil
+	}
+
 	if input.Query == "" {
 		return newToolErrorResult("query parameter is required"), nil, nil
 	}
@@ -169,17 +261,140 @@ func (s *ServerContainer) QueryHandler(ctx context.Context, req *mcp.CallToolReq
 		return newToolErrorResult(fmt.Sprintf("failed to parse timestamp: %v", err)), nil, nil
 	}
 
+	timeout, err := parseTimeoutArgument(input.Timeout)
+	if err != nil {
+		return newToolErrorResult(err.Error()), nil, nil
+	}
+	ctx, timeout = s.withAPITimeout(ctx, timeout)
+
+	if input.SeriesLimit < 0 {
+		return newToolErrorResult("series_limit must not be negative"), nil, nil
+	}
+
+	sortOpts, err := parseResultSort(input.SortInput)
+	if err != nil {
+		return newToolErrorResult(err.Error()), nil, nil
+	}
+
 	truncationLimit := s.GetEffectiveTruncationLimit(input.TruncationLimit)
-	result, err := s.queryAPICall(ctx, input.Query, ts, truncationLimit)
+	result, output, err := s.queryAPICall(ctx, input.Query, ts, timeout, uint64(input.SeriesLimit), sortOpts, truncationLimit)
 	if err != nil {
 		return newToolErrorResult("failed making query api call: " + err.Error()), nil, nil
 	}
 
-	return newToolTextResult(result), nil, nil
+	return newToolTextResult(result), output, nil
+}
+
+type queryStatsSummaryResponse struct {
+	Query           string   `json:"query"`
+	Timestamp       string   `json:"timestamp"`
+	Count           int      `json:"count"`
+	Sum             *float64 `json:"sum,omitempty"`
+	Min             *float64 `json:"min,omitempty"`
+	Max             *float64 `json:"max,omitempty"`
+	Mean            *float64 `json:"mean,omitempty"`
+	Median          *float64 `json:"median,omitempty"`
+	ExcludedSamples int      `json:"excluded_samples,omitempty"`
+	Message         string   `json:"message,omitempty"`
+}
+
+// QueryStatsSummaryHandler handles the query stats summary tool.
+func (s *ServerContainer) QueryStatsSummaryHandler(ctx context.Context, req *mcp.CallToolRequest, input QueryStatsSummaryInput) (*mcp.CallToolResult, any, error) {
+	ctx, err := s.withTarget(ctx, input.Target)
+	if err != nil {
+		return newToolErrorResult(err.Error()), nil, nil
+	}
+
+	if input.Query == "" {
+		return newToolErrorResult("query parameter is required"), nil, nil
+	}
+
+	ts, err := parseTimeWithDefault(input.Timestamp, time.Now())
+	if err != nil {
+		return newToolErrorResult(fmt.Sprintf("failed to parse timestamp: %v", err)), nil, nil
+	}
+
+	result, err := s.doAPICall(ctx, "/api/v1/query", "failed to execute instant query",
+		func(ctx context.Context, client promv1.API) (any, error) {
+			v, _, err := client.Query(ctx, input.Query, ts)
+			return v, err
+		})
+	if err != nil {
+		return newToolErrorResult("failed making query api call: " + err.Error()), nil, nil
+	}
+
+	vector, ok := result.(model.Vector)
+	if !ok {
+		valueType := "unknown"
+		if v, ok := result.(model.Value); ok {
+			valueType = v.Type().String()
+		}
+		return newToolErrorResult(fmt.Sprintf("query must return an instant vector to be summarized, got %s", valueType)), nil, nil
+	}
+
+	resp := summarizeVectorValues(vector)
+	resp.Query = input.Query
+	resp.Timestamp = ts.UTC().Format(time.RFC3339)
+
+	output, err := s.FormatOutput(resp)
+	if err != nil {
+		return newToolErrorResult("failed to format query stats summary: " + err.Error()), nil, nil
+	}
+	return newToolTextResult(output), nil, nil
+}
+
+// summarizeVectorValues computes summary statistics of the sample values in
+// the vector. NaN, infinite, and native histogram samples can't be summarized
+// meaningfully and are counted as excluded instead.
+func summarizeVectorValues(vector model.Vector) queryStatsSummaryResponse {
+	var (
+		resp   queryStatsSummaryResponse
+		values = make([]float64, 0, len(vector))
+		sum    float64
+	)
+	for _, sample := range vector {
+		v := float64(sample.Value)
+		if sample.Histogram != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+			resp.ExcludedSamples++
+			continue
+		}
+		values = append(values, v)
+		sum += v
+	}
+
+	resp.Count = len(values)
+	switch {
+	case len(vector) == 0:
+		resp.Message = "The query returned no series."
+		return resp
+	case len(values) == 0:
+		resp.Message = "The query returned no finite float samples to summarize."
+		return resp
+	case resp.ExcludedSamples > 0:
+		resp.Message = "NaN, infinite, and native histogram samples were excluded from the statistics."
+	}
+
+	sort.Float64s(values)
+	median := values[len(values)/2]
+	if len(values)%2 == 0 {
+		median = (values[len(values)/2-1] + median) / 2
+	}
+
+	resp.Sum = ptr(sum)
+	resp.Min = ptr(values[0])
+	resp.Max = ptr(values[len(values)-1])
+	resp.Mean = ptr(sum / float64(len(values)))
+	resp.Median = ptr(median)
+	return resp
 }
 
 // RangeQueryHandler handles the range query tool.
-func (s *ServerContainer) RangeQueryHandler(ctx context.Context, req *mcp.CallToolRequest, input RangeQueryInput) (*mcp.CallToolResult, any, error) {
+func (s *ServerContainer) RangeQueryHandler(ctx context.Context, req *mcp.CallToolRequest, input RangeQueryInput) (*mcp.CallToolResult, *QueryResultOutput, error) {
+	ctx, err := s.withTarget(ctx, input.Target)
+	if err != nil {
+		return newToolErrorResult(err.Error()), nil, nil
+	}
+
 	if input.Query == "" {
 		return newToolErrorResult("query parameter is required"), nil, nil
 	}
@@ -189,7 +404,7 @@ func (s *ServerContainer) RangeQueryHandler(ctx context.Context, req *mcp.CallTo
 		return newToolErrorResult(fmt.Sprintf("failed to parse end_time: %v", err)), nil, nil
 	}
 
-	startTs, err := parseTimeWithDefault(input.StartTime, endTs.Add(DefaultLookbackDelta))
+	startTs, err := parseTimeWithDefault(input.StartTime, endTs.Add(s.getLookbackDelta()))
 	if err != nil {
 		return newToolErrorResult(fmt.Sprintf("failed to parse start_time: %v", err)), nil, nil
 	}
@@ -212,16 +427,45 @@ func (s *ServerContainer) RangeQueryHandler(ctx context.Context, req *mcp.CallTo
 		step = time.Duration(resolution) * time.Second
 	}
 
+	timeout, err := parseTimeoutArgument(input.Timeout)
+	if err != nil {
+		return newToolErrorResult(err.Error()), nil, nil
+	}
+	ctx, timeout = s.withAPITimeout(ctx, timeout)
+
+	if input.SeriesLimit < 0 {
+		return newToolErrorResult("series_limit must not be negative"), nil, nil
+	}
+
+	sortOpts, err := parseResultSort(input.SortInput)
+	if err != nil {
+		return newToolErrorResult(err.Error()), nil, nil
+	}
+
+	format := strings.ToLower(input.Format)
+	switch format {
+	case "", queryResultFormatDefault, queryResultFormatCSV:
+	default:
+		return newToolErrorResult("format must be one of 'default' or 'csv'"), nil, nil
+	}
+
 	truncationLimit := s.GetEffectiveTruncationLimit(input.TruncationLimit)
-	result, err := s.rangeQueryAPICall(ctx, input.Query, startTs, endTs, step, truncationLimit)
+	stopProgress := s.startProgressHeartbeat(ctx, req, "range query")
+	result, output, err := s.rangeQueryAPICall(ctx, input.Query, startTs, endTs, step, timeout, uint64(input.SeriesLimit), sortOpts, format, truncationLimit)
+	stopProgress()
 	if err != nil {
 		return newToolErrorResult("failed making range query api call: " + err.Error()), nil, nil
 	}
-	return newToolTextResult(result), nil, nil
+	return newToolTextResult(result), output, nil
 }
 
 // ExemplarQueryHandler handles the exemplar query tool.
 func (s *ServerContainer) ExemplarQueryHandler(ctx context.Context, req *mcp.CallToolRequest, input ExemplarQueryInput) (*mcp.CallToolResult, any, error) {
+	ctx, err := s.withTarget(ctx, input.Target)
+	if err != nil {
+		return newToolErrorResult(err.Error()), nil, nil
+	}
+
 	if input.Query == "" {
 		return newToolErrorResult("query parameter is required"), nil, nil
 	}
@@ -231,771 +475,5055 @@ func (s *ServerContainer) ExemplarQueryHandler(ctx context.Context, req *mcp.Cal
 		return newToolErrorResult(fmt.Sprintf("failed to parse end_time: %v", err)), nil, nil
 	}
 
-	startTs, err := parseTimeWithDefault(input.StartTime, endTs.Add(DefaultLookbackDelta))
+	startTs, err := parseTimeWithDefault(input.StartTime, endTs.Add(s.getLookbackDelta()))
 	if err != nil {
 		return newToolErrorResult(fmt.Sprintf("failed to parse start_time: %v", err)), nil, nil
 	}
 
+	format := strings.ToLower(input.Format)
+	switch format {
+	case "", exemplarFormatDefault, exemplarFormatJSONL:
+	default:
+		return newToolErrorResult("format must be one of 'default' or 'jsonl'"), nil, nil
+	}
+
 	truncationLimit := s.GetEffectiveTruncationLimit(input.TruncationLimit)
-	result, err := s.exemplarQueryAPICall(ctx, input.Query, startTs, endTs, truncationLimit)
+	result, err := s.exemplarQueryAPICall(ctx, input.Query, startTs, endTs, format, truncationLimit)
 	if err != nil {
 		return newToolErrorResult("failed making exemplar api call: " + err.Error()), nil, nil
 	}
 	return newToolTextResult(result), nil, nil
 }
 
-// SeriesHandler handles the series query tool.
-func (s *ServerContainer) SeriesHandler(ctx context.Context, req *mcp.CallToolRequest, input SeriesInput) (*mcp.CallToolResult, any, error) {
-	if len(input.Matches) == 0 {
-		return newToolErrorResult("at least one matches parameter is required"), nil, nil
-	}
+// defaultExemplarCoverageTraceIDSamples is the number of trace IDs sampled by
+// the exemplar coverage tool when none is requested.
+const defaultExemplarCoverageTraceIDSamples = 5
 
-	startTs, endTs, err := parseTimeRangeInputWithDefaults(input.TimeRangeInput, time.Time{}, time.Time{})
+// exemplarTraceIDLabels are the exemplar label names checked for a trace ID,
+// in order of preference.
+var exemplarTraceIDLabels = []string{"trace_id", "traceID", "traceId", "TraceID"}
+
+type exemplarCoverageSeries struct {
+	Series    string `json:"series"`
+	Exemplars int    `json:"exemplars"`
+}
+
+type exemplarCoverageResponse struct {
+	Selector            string                   `json:"selector"`
+	StartTime           string                   `json:"start_time"`
+	EndTime             string                   `json:"end_time"`
+	SeriesWithExemplars int                      `json:"series_with_exemplars"`
+	TotalExemplars      int                      `json:"total_exemplars"`
+	SampleTraceIDs      []string                 `json:"sample_trace_ids"`
+	Series              []exemplarCoverageSeries `json:"series"`
+	Message             string                   `json:"message,omitempty"`
+	Truncated           string                   `json:"truncated,omitempty"`
+}
+
+// ExemplarCoverageHandler handles the exemplar coverage tool.
+func (s *ServerContainer) ExemplarCoverageHandler(ctx context.Context, req *mcp.CallToolRequest, input ExemplarCoverageInput) (*mcp.CallToolResult, any, error) {
+	ctx, err := s.withTarget(ctx, input.Target)
 	if err != nil {
 		return newToolErrorResult(err.Error()), nil, nil
 	}
 
-	truncationLimit := s.GetEffectiveTruncationLimit(input.TruncationLimit)
-	result, err := s.seriesAPICall(ctx, input.Matches, startTs, endTs, truncationLimit)
+	if strings.TrimSpace(input.Selector) == "" {
+		return newToolErrorResult("selector parameter is required"), nil, nil
+	}
+	if _, err := promqlParser.ParseMetricSelector(input.Selector); err != nil {
+		return newToolErrorResult(fmt.Sprintf("selector must be a series selector, e.g. http_request_duration_seconds_bucket{job=\"api\"}: %v", err)), nil, nil
+	}
+
+	traceIDSamples := input.TraceIDSamples
+	if traceIDSamples == 0 {
+		traceIDSamples = defaultExemplarCoverageTraceIDSamples
+	}
+	if traceIDSamples < 0 {
+		return newToolErrorResult("trace_id_samples must not be negative"), nil, nil
+	}
+
+	endTs, err := parseTimeWithDefault(input.EndTime, time.Now())
 	if err != nil {
-		return newToolErrorResult("failed making series api call: " + err.Error()), nil, nil
+		return newToolErrorResult(fmt.Sprintf("failed to parse end_time: %v", err)), nil, nil
 	}
-	return newToolTextResult(result), nil, nil
-}
 
-// LabelNamesHandler handles the label names query tool.
-func (s *ServerContainer) LabelNamesHandler(ctx context.Context, req *mcp.CallToolRequest, input LabelNamesInput) (*mcp.CallToolResult, any, error) {
-	startTs, endTs, err := parseTimeRangeInputWithDefaults(input.TimeRangeInput, time.Time{}, time.Time{})
+	startTs, err := parseTimeWithDefault(input.StartTime, endTs.Add(s.getLookbackDelta()))
 	if err != nil {
-		return newToolErrorResult(err.Error()), nil, nil
+		return newToolErrorResult(fmt.Sprintf("failed to parse start_time: %v", err)), nil, nil
 	}
 
 	truncationLimit := s.GetEffectiveTruncationLimit(input.TruncationLimit)
-	result, err := s.labelNamesAPICall(ctx, input.Matches, startTs, endTs, truncationLimit)
+	result, err := s.exemplarCoverageAPICall(ctx, input.Selector, startTs, endTs, traceIDSamples, truncationLimit)
 	if err != nil {
-		return newToolErrorResult("failed making label names api call: " + err.Error()), nil, nil
+		return newToolErrorResult("failed making exemplar api call: " + err.Error()), nil, nil
 	}
 	return newToolTextResult(result), nil, nil
 }
 
-// LabelValuesHandler handles the label values query tool.
-func (s *ServerContainer) LabelValuesHandler(ctx context.Context, req *mcp.CallToolRequest, input LabelValuesInput) (*mcp.CallToolResult, any, error) {
-	if input.Label == "" {
-		return newToolErrorResult("label parameter is required"), nil, nil
+// QueryExplainHandler handles the query explain tool.
+func (s *ServerContainer) QueryExplainHandler(ctx context.Context, req *mcp.CallToolRequest, input QueryExplainInput) (*mcp.CallToolResult, any, error) {
+	if strings.TrimSpace(input.Query) == "" {
+		return newToolErrorResult("query is required"), nil, nil
 	}
 
-	startTs, endTs, err := parseTimeRangeInputWithDefaults(input.TimeRangeInput, time.Time{}, time.Time{})
+	explained, err := explainPromQLQuery(input.Query)
 	if err != nil {
-		return newToolErrorResult(err.Error()), nil, nil
+		return newToolErrorResult("failed to parse query: " + err.Error()), nil, nil
 	}
 
-	truncationLimit := s.GetEffectiveTruncationLimit(input.TruncationLimit)
-	result, err := s.labelValuesAPICall(ctx, input.Label, input.Matches, startTs, endTs, truncationLimit)
+	result, err := s.FormatOutput(explained)
 	if err != nil {
-		return newToolErrorResult("failed making label values api call: " + err.Error()), nil, nil
+		return newToolErrorResult("failed to format query explanation: " + err.Error()), nil, nil
 	}
+
 	return newToolTextResult(result), nil, nil
 }
 
-// MetricMetadataHandler handles the metric metadata tool.
-func (s *ServerContainer) MetricMetadataHandler(ctx context.Context, req *mcp.CallToolRequest, input MetricMetadataInput) (*mcp.CallToolResult, any, error) {
-	result, err := s.metricMetadataAPICall(ctx, input.Metric, input.Limit)
+// ValidateQueryHandler handles the validate query tool. Invalid and empty
+// queries are reported in the result rather than as tool errors.
+func (s *ServerContainer) ValidateQueryHandler(ctx context.Context, req *mcp.CallToolRequest, input ValidateQueryInput) (*mcp.CallToolResult, any, error) {
+	result, err := s.FormatOutput(validatePromQLQuery(input.Query))
 	if err != nil {
-		return newToolErrorResult("failed making metric metadata api call: " + err.Error()), nil, nil
+		return newToolErrorResult("failed to format query validation: " + err.Error()), nil, nil
 	}
+
 	return newToolTextResult(result), nil, nil
 }
 
-// TargetsMetadataHandler handles the targets metadata tool.
-func (s *ServerContainer) TargetsMetadataHandler(ctx context.Context, req *mcp.CallToolRequest, input TargetsMetadataInput) (*mcp.CallToolResult, any, error) {
-	result, err := s.targetsMetadataAPICall(ctx, input.MatchTarget, input.Metric, input.Limit)
-	if err != nil {
-		return newToolErrorResult("failed making targets metadata api call: " + err.Error()), nil, nil
+// Truncation limits enforced on series results when they are enumerated
+// without matchers, since the result may include every series in the time
+// range.
+const (
+	emptyMatchersTruncationLimitLines  = 1000
+	emptyMatchersTruncationLimitBytes  = 64 * 1024
+	emptyMatchersTruncationLimitTokens = emptyMatchersTruncationLimitBytes / charsPerToken
+)
+
+// emptyMatchersWarning is added to the warnings of series results enumerated
+// without matchers.
+const emptyMatchersWarning = "no matchers were given, so all series in the time range were enumerated." +
+	" This is expensive for the Prometheus backend, add matchers to narrow the selection where possible."
+
+// emptyMatchersTruncationLimit caps the truncation limit for series results
+// enumerated without matchers, even if truncation is otherwise disabled.
+func (s *ServerContainer) emptyMatchersTruncationLimit(limit int) int {
+	maxLimit := emptyMatchersTruncationLimitLines
+	switch s.truncationMode {
+	case TruncationModeBytes:
+		maxLimit = emptyMatchersTruncationLimitBytes
+	case TruncationModeTokens:
+		maxLimit = emptyMatchersTruncationLimitTokens
 	}
-	return newToolTextResult(result), nil, nil
+	if limit <= 0 || limit > maxLimit {
+		return maxLimit
+	}
+	return limit
 }
 
-// callAPIAndReturnToolResult encapsulates the common pattern for simple tool
-// handlers that call a single API method and return the result as a tool text
-// response.
-func callAPIAndReturnToolResult(ctx context.Context, call func(context.Context) (string, error), errPrefix string) (*mcp.CallToolResult, any, error) {
-	result, err := call(ctx)
+// SeriesHandler handles the series query tool.
+func (s *ServerContainer) SeriesHandler(ctx context.Context, req *mcp.CallToolRequest, input SeriesInput) (*mcp.CallToolResult, any, error) {
+	ctx, err := s.withTarget(ctx, input.Target)
 	if err != nil {
-		return newToolErrorResult(errPrefix + err.Error()), nil, nil
+		return newToolErrorResult(err.Error()), nil, nil
 	}
-	return newToolTextResult(result), nil, nil
-}
 
-// AlertmanagersHandler handles the alertmanagers tool.
-func (s *ServerContainer) AlertmanagersHandler(ctx context.Context, req *mcp.CallToolRequest, input EmptyInput) (*mcp.CallToolResult, any, error) {
-	return callAPIAndReturnToolResult(ctx, s.alertmanagersAPICall, "failed making alertmanagers api call: ")
-}
+	if len(input.Matches) == 0 && !s.allowEmptyMatchers {
+		return newToolErrorResult("at least one matches parameter is required"), nil, nil
+	}
 
-// FlagsHandler handles the flags tool.
-func (s *ServerContainer) FlagsHandler(ctx context.Context, req *mcp.CallToolRequest, input EmptyInput) (*mcp.CallToolResult, any, error) {
-	return callAPIAndReturnToolResult(ctx, s.flagsAPICall, "failed making flags api call: ")
-}
+	startTs, endTs, err := parseTimeRangeInputWithDefaults(input.TimeRangeInput, time.Time{}, time.Time{})
+	if err != nil {
+		return newToolErrorResult(err.Error()), nil, nil
+	}
 
-// ListAlertsHandler handles the list alerts tool.
-func (s *ServerContainer) ListAlertsHandler(ctx context.Context, req *mcp.CallToolRequest, input EmptyInput) (*mcp.CallToolResult, any, error) {
-	return callAPIAndReturnToolResult(ctx, s.listAlertsAPICall, "failed making list alerts api call: ")
-}
+	timeout, err := parseTimeoutArgument(input.Timeout)
+	if err != nil {
+		return newToolErrorResult(err.Error()), nil, nil
+	}
+	ctx, _ = s.withAPITimeout(ctx, timeout)
 
-// TsdbStatsHandler handles the TSDB stats tool.
-func (s *ServerContainer) TsdbStatsHandler(ctx context.Context, req *mcp.CallToolRequest, input EmptyInput) (*mcp.CallToolResult, any, error) {
-	return callAPIAndReturnToolResult(ctx, s.tsdbStatsAPICall, "failed making TSDB stats api call: ")
-}
+	order, err := parseListSort(input.Sort)
+	if err != nil {
+		return newToolErrorResult(err.Error()), nil, nil
+	}
 
-// BuildInfoHandler handles the build info tool.
-func (s *ServerContainer) BuildInfoHandler(ctx context.Context, req *mcp.CallToolRequest, input EmptyInput) (*mcp.CallToolResult, any, error) {
-	return callAPIAndReturnToolResult(ctx, s.buildinfoAPICall, "failed making build info api call: ")
-}
+	if input.paginated() {
+		page, pageSize, err := input.pageBounds()
+		if err != nil {
+			return newToolErrorResult(err.Error()), nil, nil
+		}
+		result, nextPage, err := s.seriesPageAPICall(ctx, input.Matches, startTs, endTs, order, page, pageSize)
+		if err != nil {
+			return newToolErrorResult("failed making series api call: " + err.Error()), nil, nil
+		}
+		return newPaginatedToolResult(result, nextPage), nil, nil
+	}
 
-// ConfigHandler handles the config tool.
-func (s *ServerContainer) ConfigHandler(ctx context.Context, req *mcp.CallToolRequest, input EmptyInput) (*mcp.CallToolResult, any, error) {
-	return callAPIAndReturnToolResult(ctx, s.configAPICall, "failed making config api call: ")
+	truncationLimit := s.GetEffectiveTruncationLimit(input.TruncationLimit)
+	if len(input.Matches) == 0 {
+		truncationLimit = s.emptyMatchersTruncationLimit(truncationLimit)
+	}
+	result, err := s.seriesAPICall(ctx, input.Matches, startTs, endTs, order, truncationLimit)
+	if err != nil {
+		return newToolErrorResult("failed making series api call: " + err.Error()), nil, nil
+	}
+	return newToolTextResult(result), nil, nil
 }
 
-// RuntimeInfoHandler handles the runtime info tool.
-func (s *ServerContainer) RuntimeInfoHandler(ctx context.Context, req *mcp.CallToolRequest, input EmptyInput) (*mcp.CallToolResult, any, error) {
-	return callAPIAndReturnToolResult(ctx, s.runtimeinfoAPICall, "failed making runtime info api call: ")
+type seriesCountResponse struct {
+	Count    int             `json:"count"`
+	Warnings promv1.Warnings `json:"warnings"`
 }
 
-// ListRulesHandler handles the list rules tool.
-func (s *ServerContainer) ListRulesHandler(ctx context.Context, req *mcp.CallToolRequest, input EmptyInput) (*mcp.CallToolResult, any, error) {
-	return callAPIAndReturnToolResult(ctx, s.rulesAPICall, "failed making rules api call: ")
-}
+// SeriesCountHandler handles the series count tool.
+func (s *ServerContainer) SeriesCountHandler(ctx context.Context, req *mcp.CallToolRequest, input SeriesCountInput) (*mcp.CallToolResult, any, error) {
+	ctx, err := s.withTarget(ctx, input.Target)
+	if err != nil {
+		return newToolErrorResult(err.Error()), nil, nil
+	}
 
-// ListTargetsHandler handles the list targets tool.
-func (s *ServerContainer) ListTargetsHandler(ctx context.Context, req *mcp.CallToolRequest, input EmptyInput) (*mcp.CallToolResult, any, error) {
-	return callAPIAndReturnToolResult(ctx, s.targetsAPICall, "failed making targets api call: ")
+	if len(input.Matches) == 0 && !s.allowEmptyMatchers {
+		return newToolErrorResult("at least one matches parameter is required"), nil, nil
+	}
+
+	startTs, endTs, err := parseTimeRangeInputWithDefaults(input.TimeRangeInput, time.Time{}, time.Time{})
+	if err != nil {
+		return newToolErrorResult(err.Error()), nil, nil
+	}
+
+	result, err := s.seriesCountAPICall(ctx, input.Matches, startTs, endTs)
+	if err != nil {
+		return newToolErrorResult("failed making series api call: " + err.Error()), nil, nil
+	}
+	return newToolTextResult(result), nil, nil
 }
 
-// WALReplayHandler handles the WAL replay status tool.
-func (s *ServerContainer) WALReplayHandler(ctx context.Context, req *mcp.CallToolRequest, input EmptyInput) (*mcp.CallToolResult, any, error) {
-	return callAPIAndReturnToolResult(ctx, s.walReplayAPICall, "failed making WAL replay api call: ")
+// defaultLabelsCardinalityTopN is the number of labels returned by the labels
+// cardinality tool when top_n isn't set.
+const defaultLabelsCardinalityTopN = 10
+
+type labelCardinality struct {
+	Label  string `json:"label"`
+	Values int    `json:"values"`
+	Series int    `json:"series"`
 }
 
-// Prometheus TSDB Admin tool handlers
+type labelsCardinalityResponse struct {
+	Selector    string             `json:"selector"`
+	SeriesCount int                `json:"series_count"`
+	TotalLabels int                `json:"total_labels"`
+	Labels      []labelCardinality `json:"labels"`
+	Warnings    promv1.Warnings    `json:"warnings"`
+}
 
-// CleanTombstonesHandler handles the clean tombstones admin tool.
-func (s *ServerContainer) CleanTombstonesHandler(ctx context.Context, req *mcp.CallToolRequest, input EmptyInput) (*mcp.CallToolResult, any, error) {
-	if !s.tsdbAdminToolsEnabled {
-		return newToolErrorResult("failed making clean tombstones api call: " + errTSDBAdminToolsNotEnabled.Error()), nil, nil
+// LabelsCardinalityHandler handles the labels cardinality tool.
+func (s *ServerContainer) LabelsCardinalityHandler(ctx context.Context, req *mcp.CallToolRequest, input LabelsCardinalityInput) (*mcp.CallToolResult, any, error) {
+	ctx, err := s.withTarget(ctx, input.Target)
+	if err != nil {
+		return newToolErrorResult(err.Error()), nil, nil
 	}
 
-	logger := s.GetToolLogger(req, nil)
+	if strings.TrimSpace(input.Selector) == "" {
+		return newToolErrorResult("selector parameter is required"), nil, nil
+	}
+	if _, err := promqlParser.ParseMetricSelector(input.Selector); err != nil {
+		return newToolErrorResult(fmt.Sprintf("selector must be a metric name or series selector, e.g. http_requests_total{job=\"api\"}: %v", err)), nil, nil
+	}
 
-	logger.Warn("executing TSDB admin operation: clean tombstones")
+	topN := input.TopN
+	if topN == 0 {
+		topN = defaultLabelsCardinalityTopN
+	}
+	if topN < 0 {
+		return newToolErrorResult("top_n must not be negative"), nil, nil
+	}
 
-	result, err := s.cleanTombstonesAPICall(ctx)
+	startTs, endTs, err := parseTimeRangeInputWithDefaults(input.TimeRangeInput, time.Time{}, time.Time{})
 	if err != nil {
-		return newToolErrorResult("failed making clean tombstones api call: " + err.Error()), nil, nil
+		return newToolErrorResult(err.Error()), nil, nil
 	}
 
-	logger.Warn("clean tombstones completed successfully")
+	result, err := s.labelsCardinalityAPICall(ctx, input.Selector, topN, startTs, endTs)
+	if err != nil {
+		return newToolErrorResult("failed making series api call: " + err.Error()), nil, nil
+	}
 	return newToolTextResult(result), nil, nil
 }
 
-// DeleteSeriesHandler handles the delete series admin tool.
-func (s *ServerContainer) DeleteSeriesHandler(ctx context.Context, req *mcp.CallToolRequest, input DeleteSeriesInput) (*mcp.CallToolResult, any, error) {
-	if !s.tsdbAdminToolsEnabled {
-		return newToolErrorResult("failed making delete series api call: " + errTSDBAdminToolsNotEnabled.Error()), nil, nil
-	}
+type seriesByLabelRegexResponse struct {
+	Selector string          `json:"selector"`
+	Result   string          `json:"result"`
+	Warnings promv1.Warnings `json:"warnings"`
+}
 
-	if len(input.Matches) == 0 {
-		return newToolErrorResult("at least one matches parameter is required"), nil, nil
+// SeriesByLabelRegexHandler handles the series by label regex tool.
+func (s *ServerContainer) SeriesByLabelRegexHandler(ctx context.Context, req *mcp.CallToolRequest, input SeriesByLabelRegexInput) (*mcp.CallToolResult, any, error) {
+	ctx, err := s.withTarget(ctx, input.Target)
+	if err != nil {
+		return newToolErrorResult(err.Error()), nil, nil
 	}
 
-	// Require explicit time bounds for destructive operations. Without them,
-	// Prometheus defaults to deleting across the entire retention window.
-	// This is almost certainly not intended behavior from the end user,
-	// but if it is, make them ask for it.
-	if input.StartTime == "" || input.EndTime == "" {
-		return newToolErrorResult("both start_time and end_time are required for delete_series to prevent accidental deletion of all data; specify explicit time bounds"), nil, nil
+	selector, err := buildLabelRegexSelector(input.Metric, input.Label, input.Regex)
+	if err != nil {
+		return newToolErrorResult(err.Error()), nil, nil
 	}
 
-	logger := s.GetToolLogger(req, input)
-
 	startTs, endTs, err := parseTimeRangeInputWithDefaults(input.TimeRangeInput, time.Time{}, time.Time{})
 	if err != nil {
 		return newToolErrorResult(err.Error()), nil, nil
 	}
 
-	logger.Warn("executing TSDB admin operation: delete series")
-
-	result, err := s.deleteSeriesAPICall(ctx, input.Matches, startTs, endTs)
+	truncationLimit := s.GetEffectiveTruncationLimit(input.TruncationLimit)
+	result, err := s.seriesByLabelRegexAPICall(ctx, selector, startTs, endTs, truncationLimit)
 	if err != nil {
-		return newToolErrorResult("failed making delete series api call: " + err.Error()), nil, nil
+		return newToolErrorResult("failed making series api call: " + err.Error()), nil, nil
 	}
-
-	logger.Warn("delete series completed successfully")
 	return newToolTextResult(result), nil, nil
 }
 
-// SnapshotHandler handles the snapshot admin tool.
-func (s *ServerContainer) SnapshotHandler(ctx context.Context, req *mcp.CallToolRequest, input SnapshotInput) (*mcp.CallToolResult, any, error) {
-	if !s.tsdbAdminToolsEnabled {
-		return newToolErrorResult("failed making snapshot api call: " + errTSDBAdminToolsNotEnabled.Error()), nil, nil
-	}
-
-	logger := s.GetToolLogger(req, input)
+// defaultFindMetricsLimit is the number of metric names returned by the find
+// metrics tool when no limit is requested.
+const defaultFindMetricsLimit = 100
 
-	logger.Warn("executing TSDB admin operation: snapshot")
+type findMetricsResponse struct {
+	Pattern      string          `json:"pattern"`
+	Metrics      []string        `json:"metrics"`
+	TotalMatches int             `json:"total_matches"`
+	Message      string          `json:"message,omitempty"`
+	Warnings     promv1.Warnings `json:"warnings"`
+}
 
-	result, err := s.snapshotAPICall(ctx, input.SkipHead)
+// FindMetricsHandler handles the find metrics tool.
+func (s *ServerContainer) FindMetricsHandler(ctx context.Context, req *mcp.CallToolRequest, input FindMetricsInput) (*mcp.CallToolResult, any, error) {
+	ctx, err := s.withTarget(ctx, input.Target)
 	if err != nil {
-		return newToolErrorResult("failed making snapshot api call: " + err.Error()), nil, nil
+		return newToolErrorResult(err.Error()), nil, nil
 	}
 
-	logger.Warn("snapshot completed successfully")
-	return newToolTextResult(result), nil, nil
-}
+	if input.Pattern == "" {
+		return newToolErrorResult("pattern parameter is required"), nil, nil
+	}
+	re, err := regexp.Compile(input.Pattern)
+	if err != nil {
+		return newToolErrorResult(fmt.Sprintf("invalid pattern: %v", err)), nil, nil
+	}
 
-// Management API handlers
+	limit := input.Limit
+	if limit == 0 {
+		limit = defaultFindMetricsLimit
+	}
+	if limit < 0 {
+		return newToolErrorResult("limit must not be negative"), nil, nil
+	}
 
-// HealthyHandler handles the healthy check tool.
-func (s *ServerContainer) HealthyHandler(ctx context.Context, req *mcp.CallToolRequest, input EmptyInput) (*mcp.CallToolResult, any, error) {
-	result, err := s.doManagementAPICall(ctx, http.MethodGet, mgmtAPIHealthyEndpoint)
+	result, err := s.findMetricsAPICall(ctx, re, limit)
 	if err != nil {
-		return newToolErrorResult("failed making healthy api call: " + err.Error()), nil, nil
+		return newToolErrorResult("failed making label values api call: " + err.Error()), nil, nil
 	}
 	return newToolTextResult(result), nil, nil
 }
 
-// ReadyHandler handles the ready check tool.
-func (s *ServerContainer) ReadyHandler(ctx context.Context, req *mcp.CallToolRequest, input EmptyInput) (*mcp.CallToolResult, any, error) {
-	result, err := s.doManagementAPICall(ctx, http.MethodGet, mgmtAPIReadyEndpoint)
+// defaultOTLPMetricsNamesPattern matches the metric names that only the
+// OTLP to Prometheus translation produces: the target_info and
+// otel_scope_info metrics created for resource and scope attributes, and
+// dotted names kept as-is by the NoTranslation strategies. Unit suffixes and
+// semantic convention namespaces are common in native Prometheus
+// instrumentation too, so they are left to a custom pattern.
+const defaultOTLPMetricsNamesPattern = `^(target_info|otel_scope_info)$|\.`
+
+// OTLPMetricsNamesHandler handles the OTLP metrics names tool.
+func (s *ServerContainer) OTLPMetricsNamesHandler(ctx context.Context, req *mcp.CallToolRequest, input OTLPMetricsNamesInput) (*mcp.CallToolResult, any, error) {
+	ctx, err := s.withTarget(ctx, input.Target)
 	if err != nil {
-		return newToolErrorResult("failed making ready api call: " + err.Error()), nil, nil
+		return newToolErrorResult(err.Error()), nil, nil
 	}
-	return newToolTextResult(result), nil, nil
-}
-
-// ReloadHandler handles the reload config tool.
-func (s *ServerContainer) ReloadHandler(ctx context.Context, req *mcp.CallToolRequest, input EmptyInput) (*mcp.CallToolResult, any, error) {
-	logger := s.GetToolLogger(req, nil)
-
-	logger.Warn("triggering Prometheus configuration reload")
 
-	result, err := s.doManagementAPICall(ctx, http.MethodPost, mgmtAPIReloadEndpoint)
+	pattern := input.Pattern
+	if pattern == "" {
+		pattern = defaultOTLPMetricsNamesPattern
+	}
+	re, err := regexp.Compile(pattern)
 	if err != nil {
-		return newToolErrorResult("failed making reload api call: " + err.Error()), nil, nil
+		return newToolErrorResult(fmt.Sprintf("invalid pattern: %v", err)), nil, nil
 	}
 
-	logger.Warn("reload completed successfully")
-	return newToolTextResult(result), nil, nil
-}
-
-// QuitHandler handles the quit/shutdown tool.
-func (s *ServerContainer) QuitHandler(ctx context.Context, req *mcp.CallToolRequest, input EmptyInput) (*mcp.CallToolResult, any, error) {
-	logger := s.GetToolLogger(req, nil)
-
-	logger.Warn("triggering Prometheus shutdown")
+	limit := input.Limit
+	if limit == 0 {
+		limit = defaultFindMetricsLimit
+	}
+	if limit < 0 {
+		return newToolErrorResult("limit must not be negative"), nil, nil
+	}
 
-	result, err := s.doManagementAPICall(ctx, http.MethodPost, mgmtAPIQuitEndpoint)
+	result, err := s.findMetricsAPICall(ctx, re, limit)
 	if err != nil {
-		return newToolErrorResult("failed making quit api call: " + err.Error()), nil, nil
+		return newToolErrorResult("failed making label values api call: " + err.Error()), nil, nil
 	}
-
-	logger.Warn("quit signal sent successfully - Prometheus is shutting down")
 	return newToolTextResult(result), nil, nil
 }
 
-// Documentation tool handlers
-
-// DocsListHandler handles the docs list tool.
-func (s *ServerContainer) DocsListHandler(ctx context.Context, req *mcp.CallToolRequest, input EmptyInput) (*mcp.CallToolResult, any, error) {
-	resourceReq := &mcp.ReadResourceRequest{
-		Params: &mcp.ReadResourceParams{
-			URI: resourcePrefix + "docs",
-		},
+// buildLabelRegexSelector builds a series selector matching the metric whose
+// label value matches regex, e.g. `metric{label=~"regex"}`. The regex is
+// validated and quoted into the selector, so it may contain any characters.
+func buildLabelRegexSelector(metric, label, regex string) (string, error) {
+	if metric == "" {
+		return "", errors.New("metric parameter is required")
+	}
+	if !model.UTF8Validation.IsValidLabelName(label) {
+		return "", fmt.Errorf("invalid label name %q", label)
+	}
+	if label == model.MetricNameLabel {
+		return "", errors.New("label must not be the metric name label, use the metric parameter instead")
 	}
 
-	resourceResult, err := s.DocsListResourceHandler(ctx, resourceReq)
+	regexMatcher, err := labels.NewMatcher(labels.MatchRegexp, label, regex)
 	if err != nil {
-		return newToolErrorResult("failed listing docs: " + err.Error()), nil, nil
+		return "", fmt.Errorf("invalid regex: %w", err)
 	}
+	nameMatcher := labels.MustNewMatcher(labels.MatchEqual, model.MetricNameLabel, metric)
 
-	return embedResourceContentsInToolResult(resourceResult, &mcp.CallToolResult{}), nil, nil
-}
+	vs := &parser.VectorSelector{LabelMatchers: []*labels.Matcher{nameMatcher, regexMatcher}}
+	// Metric names outside the legacy character set must be given as a
+	// quoted __name__ matcher instead.
+	if model.LegacyValidation.IsValidMetricName(metric) {
+		vs.Name = metric
+	}
+	selector := vs.String()
 
-// DocsReadHandler handles the docs read tool.
-func (s *ServerContainer) DocsReadHandler(ctx context.Context, req *mcp.CallToolRequest, input DocsReadInput) (*mcp.CallToolResult, any, error) {
-	if input.File == "" {
-		return newToolErrorResult("file parameter is required"), nil, nil
+	// Sanity check that the selector round-trips through the parser.
+	if _, err := promqlParser.ParseMetricSelector(selector); err != nil {
+		return "", fmt.Errorf("failed to build a valid selector: %w", err)
 	}
+	return selector, nil
+}
 
-	// Construct the resource URI with proper URL encoding
-	uri := resourcePrefix + "docs/" + url.PathEscape(input.File)
-	resourceReq := &mcp.ReadResourceRequest{
-		Params: &mcp.ReadResourceParams{
-			URI: uri,
-		},
+// LabelNamesHandler handles the label names query tool.
+func (s *ServerContainer) LabelNamesHandler(ctx context.Context, req *mcp.CallToolRequest, input LabelNamesInput) (*mcp.CallToolResult, any, error) {
+	ctx, err := s.withTarget(ctx, input.Target)
+	if err != nil {
+		return newToolErrorResult(err.Error()), nil, nil
 	}
 
-	resourceResult, err := s.DocsReadResourceHandler(ctx, resourceReq)
+	startTs, endTs, err := parseTimeRangeInputWithDefaults(input.TimeRangeInput, time.Time{}, time.Time{})
 	if err != nil {
-		return newToolErrorResult("failed reading doc file: " + err.Error()), nil, nil
+		return newToolErrorResult(err.Error()), nil, nil
 	}
 
-	return embedResourceContentsInToolResult(resourceResult, &mcp.CallToolResult{}), nil, nil
+	truncationLimit := s.GetEffectiveTruncationLimit(input.TruncationLimit)
+	result, err := s.labelNamesAPICall(ctx, input.Matches, input.TimeRangeInput, startTs, endTs, truncationLimit)
+	if err != nil {
+		return newToolErrorResult("failed making label names api call: " + err.Error()), nil, nil
+	}
+	return newToolTextResult(result), nil, nil
 }
 
-// DocsSearchHandler handles the docs search tool.
-func (s *ServerContainer) DocsSearchHandler(ctx context.Context, req *mcp.CallToolRequest, input DocsSearchInput) (*mcp.CallToolResult, any, error) {
-	logger := s.GetToolLogger(req, input)
+// LabelValuesHandler handles the label values query tool.
+func (s *ServerContainer) LabelValuesHandler(ctx context.Context, req *mcp.CallToolRequest, input LabelValuesInput) (*mcp.CallToolResult, any, error) {
+	ctx, err := s.withTarget(ctx, input.Target)
+	if err != nil {
+		return newToolErrorResult(err.Error()), nil, nil
+	}
 
-	if input.Query == "" {
-		return newToolErrorResult("query parameter is required"), nil, nil
+	if input.Label == "" {
+		return newToolErrorResult("label parameter is required"), nil, nil
 	}
 
-	matchingChunkIDs, err := s.SearchDocs(input.Query, input.Limit)
+	startTs, endTs, err := parseTimeRangeInputWithDefaults(input.TimeRangeInput, time.Time{}, time.Time{})
 	if err != nil {
-		return newToolErrorResult("failed searching docs: " + err.Error()), nil, nil
+		return newToolErrorResult(err.Error()), nil, nil
 	}
 
-	if len(matchingChunkIDs) == 0 {
-		return newToolTextResult("No documentation found matching query: " + input.Query), nil, nil
+	order, err := parseListSort(input.Sort)
+	if err != nil {
+		return newToolErrorResult(err.Error()), nil, nil
 	}
 
-	// Extract unique file names from chunk IDs.
-	matchingDocsFiles := []string{}
-	docsFilesSeen := make(map[string]struct{})
-	for _, chunkID := range matchingChunkIDs {
-		parts := strings.Split(chunkID, "#")
-		if len(parts) != 2 {
-			// Valid chunk format is `filename#chunkID`.
-			logger.Warn("skipping malformed chunk ID", "chunk_id", chunkID)
-			continue
+	if input.paginated() {
+		page, pageSize, err := input.pageBounds()
+		if err != nil {
+			return newToolErrorResult(err.Error()), nil, nil
 		}
-		name := parts[0]
-		if _, seen := docsFilesSeen[name]; !seen {
-			docsFilesSeen[name] = struct{}{}
-			matchingDocsFiles = append(matchingDocsFiles, name)
+		result, nextPage, err := s.labelValuesPageAPICall(ctx, input.Label, input.Matches, startTs, endTs, order, page, pageSize)
+		if err != nil {
+			return newToolErrorResult("failed making label values api call: " + err.Error()), nil, nil
 		}
+		return newPaginatedToolResult(result, nextPage), nil, nil
 	}
 
-	logger.Debug("docs search completed", "matching_files", len(matchingDocsFiles))
+	truncationLimit := s.GetEffectiveTruncationLimit(input.TruncationLimit)
+	result, err := s.labelValuesAPICall(ctx, input.Label, input.Matches, input.TimeRangeInput, startTs, endTs, order, truncationLimit)
+	if err != nil {
+		return newToolErrorResult("failed making label values api call: " + err.Error()), nil, nil
+	}
+	return newToolTextResult(result), nil, nil
+}
 
-	// Read all matching files via the resource handler and combine results.
-	resourceResults := make([]*mcp.ReadResourceResult, 0, len(matchingDocsFiles))
-	for _, file := range matchingDocsFiles {
-		uri := resourcePrefix + "docs/" + url.PathEscape(file)
-		// Allocate a fresh request per iteration to avoid shared-struct mutation
-		// across concurrent or sequential calls to DocsReadResourceHandler.
-		resourceReq := &mcp.ReadResourceRequest{Params: &mcp.ReadResourceParams{URI: uri}}
+// LabelExplosionHandler handles the label explosion tool.
+func (s *ServerContainer) LabelExplosionHandler(ctx context.Context, req *mcp.CallToolRequest, input LabelExplosionInput) (*mcp.CallToolResult, any, error) {
+	ctx, err := s.withTarget(ctx, input.Target)
+	if err != nil {
+		return newToolErrorResult(err.Error()), nil, nil
+	}
 
-		resourceResult, err := s.DocsReadResourceHandler(ctx, resourceReq)
-		if err != nil {
-			return newToolErrorResult("failed reading doc file: " + err.Error()), nil, nil
-		}
-		resourceResults = append(resourceResults, resourceResult)
+	if input.Label == "" {
+		return newToolErrorResult("label parameter is required"), nil, nil
 	}
 
-	searchSummary := fmt.Sprintf("Found %d documentation files matching query %q: %q", len(matchingDocsFiles), input.Query, matchingDocsFiles)
+	threshold := input.Threshold
+	if threshold < 0 {
+		return newToolErrorResult("threshold must not be negative"), nil, nil
+	}
+	if threshold == 0 {
+		threshold = defaultLabelExplosionThreshold
+	}
 
-	combinedSearchContents := concatResourceContents(resourceResults...)
-	content := make([]mcp.Content, 0, len(combinedSearchContents)+1)
-	content = append(content, &mcp.TextContent{Text: searchSummary})
-	for _, rc := range combinedSearchContents {
-		content = append(content, &mcp.EmbeddedResource{Resource: rc})
+	startTs, endTs, err := parseTimeRangeInputWithDefaults(input.TimeRangeInput, time.Time{}, time.Time{})
+	if err != nil {
+		return newToolErrorResult(err.Error()), nil, nil
 	}
 
-	return &mcp.CallToolResult{Content: content}, nil, nil
+	result, err := s.labelExplosionAPICall(ctx, input.Label, input.Matches, startTs, endTs, threshold)
+	if err != nil {
+		return newToolErrorResult("failed making label values api call: " + err.Error()), nil, nil
+	}
+	return newToolTextResult(result), nil, nil
 }
 
-// Thanos-specific handlers
+// MetricMetadataHandler handles the metric metadata tool.
+func (s *ServerContainer) MetricMetadataHandler(ctx context.Context, req *mcp.CallToolRequest, input MetricMetadataInput) (*mcp.CallToolResult, any, error) {
+	ctx, err := s.withTarget(ctx, input.Target)
+	if err != nil {
+		return newToolErrorResult(err.Error()), nil, nil
+	}
 
-// ThanosStoresHandler handles the Thanos list stores tool.
-func (s *ServerContainer) ThanosStoresHandler(ctx context.Context, req *mcp.CallToolRequest, input EmptyInput) (*mcp.CallToolResult, any, error) {
-	_, rt := s.GetAPIClient(ctx)
+	result, err := s.metricMetadataAPICall(ctx, input.Metric, input.Limit, s.GetEffectiveStripHelp(input.StripHelp))
+	if err != nil {
+		return newToolErrorResult("failed making metric metadata api call: " + err.Error()), nil, nil
+	}
+	return newToolTextResult(result), nil, nil
+}
 
-	ctx, cancel := context.WithTimeout(ctx, s.apiTimeout)
-	defer cancel()
+// MetricsWithHelpHandler handles the metrics with help tool.
+func (s *ServerContainer) MetricsWithHelpHandler(ctx context.Context, req *mcp.CallToolRequest, input MetricsWithHelpInput) (*mcp.CallToolResult, any, error) {
+	ctx, err := s.withTarget(ctx, input.Target)
+	if err != nil {
+		return newToolErrorResult(err.Error()), nil, nil
+	}
 
-	path := "/api/v1/stores"
-	result, err := s.doHTTPRequest(ctx, http.MethodGet, rt, path, true)
+	truncationLimit := s.GetEffectiveTruncationLimit(input.TruncationLimit)
+	result, err := s.metricsWithHelpAPICall(ctx, input.Prefix, truncationLimit)
 	if err != nil {
-		return newToolErrorResult("failed getting stores from Thanos: " + err.Error()), nil, nil
+		return newToolErrorResult("failed making metric metadata api call: " + err.Error()), nil, nil
 	}
 	return newToolTextResult(result), nil, nil
 }
 
-// Prometheus API call methods on ServerContainer
+// TargetsMetadataHandler handles the targets metadata tool.
+func (s *ServerContainer) TargetsMetadataHandler(ctx context.Context, req *mcp.CallToolRequest, input TargetsMetadataInput) (*mcp.CallToolResult, any, error) {
+	result, err := s.targetsMetadataAPICall(ctx, input.MatchTarget, input.Metric, input.Limit, s.GetEffectiveStripHelp(input.StripHelp))
+	if err != nil {
+		return newToolErrorResult("failed making targets metadata api call: " + err.Error()), nil, nil
+	}
+	return newToolTextResult(result), nil, nil
+}
 
-// formatTruncatedQueryAPIResponse applies line-based truncation to a result string, adds
-// a warning if truncated, wraps it in a queryAPIResponse with optional
-// warnings, and formats the output.
-func (s *ServerContainer) formatTruncatedQueryAPIResponse(resultString string, warnings promv1.Warnings, truncationLimit int) (string, error) {
-	truncatedResult, truncated := truncateStringByLines(resultString, truncationLimit)
-	if truncated {
-		resultString = truncatedResult + displayTruncationWarning(truncationLimit)
+// callAPIAndReturnToolResult encapsulates the common pattern for simple tool
+// handlers that call a single API method and return the result as a tool text
+// response.
+func callAPIAndReturnToolResult(ctx context.Context, call func(context.Context) (string, error), errPrefix string) (*mcp.CallToolResult, any, error) {
+	result, err := call(ctx)
+	if err != nil {
+		return newToolErrorResult(errPrefix + err.Error()), nil, nil
 	}
-	return s.FormatOutput(queryAPIResponse{
-		Result:   resultString,
-		Warnings: warnings,
-	})
+	return newToolTextResult(result), nil, nil
 }
 
-func (s *ServerContainer) queryAPICall(ctx context.Context, query string, ts time.Time, truncationLimit int) (string, error) {
-	client, _ := s.GetAPIClient(ctx)
-	ctx, cancel := context.WithTimeout(ctx, s.apiTimeout)
-	defer cancel()
+// AlertmanagersHandler handles the alertmanagers tool.
+func (s *ServerContainer) AlertmanagersHandler(ctx context.Context, req *mcp.CallToolRequest, input EmptyInput) (*mcp.CallToolResult, any, error) {
+	return callAPIAndReturnToolResult(ctx, s.alertmanagersAPICall, "failed making alertmanagers api call: ")
+}
 
-	path := "/api/v1/query"
-	startTs := time.Now()
-	result, warnings, err := client.Query(ctx, query, ts)
-	metricAPICallDuration.With(prometheus.Labels{"target_path": path}).Observe(time.Since(startTs).Seconds())
-	if err != nil {
-		metricAPICallsFailed.With(prometheus.Labels{"target_path": path}).Inc()
-		return "", fmt.Errorf("failed to execute instant query: %w", wrapErrorIfNotFound(err, path))
+// FlagsHandler handles the flags tool.
+func (s *ServerContainer) FlagsHandler(ctx context.Context, req *mcp.CallToolRequest, input EmptyInput) (*mcp.CallToolResult, any, error) {
+	return callAPIAndReturnToolResult(ctx, s.flagsAPICall, "failed making flags api call: ")
+}
+
+// ListAlertsHandler handles the list alerts tool.
+func (s *ServerContainer) ListAlertsHandler(ctx context.Context, req *mcp.CallToolRequest, input ListAlertsInput) (*mcp.CallToolResult, any, error) {
+	state := promv1.AlertState(strings.ToLower(input.State))
+	switch state {
+	case "", promv1.AlertStateFiring, promv1.AlertStatePending, promv1.AlertStateInactive:
+	default:
+		return newToolErrorResult("state must be one of 'firing', 'pending', or 'inactive'"), nil, nil
+	}
+
+	var matchers []*labels.Matcher
+	if len(input.Matchers) > 0 {
+		parsed, err := parseAlertmanagerMatchers(input.Matchers)
+		if err != nil {
+			return newToolErrorResult(err.Error()), nil, nil
+		}
+		matchers = parsed
+	}
+
+	result, err := s.listAlertsAPICall(ctx, state, matchers)
+	if err != nil {
+		return newToolErrorResult("failed making list alerts api call: " + err.Error()), nil, nil
+	}
+
+	return newToolTextResult(result), nil, nil
+}
+
+// TsdbStatsHandler handles the TSDB stats tool.
+func (s *ServerContainer) TsdbStatsHandler(ctx context.Context, req *mcp.CallToolRequest, input EmptyInput) (*mcp.CallToolResult, any, error) {
+	return callAPIAndReturnToolResult(ctx, s.tsdbStatsAPICall, "failed making TSDB stats api call: ")
+}
+
+// BuildInfoHandler handles the build info tool.
+func (s *ServerContainer) BuildInfoHandler(ctx context.Context, req *mcp.CallToolRequest, input EmptyInput) (*mcp.CallToolResult, any, error) {
+	return callAPIAndReturnToolResult(ctx, s.buildinfoAPICall, "failed making build info api call: ")
+}
+
+// ConfigHandler handles the config tool.
+func (s *ServerContainer) ConfigHandler(ctx context.Context, req *mcp.CallToolRequest, input EmptyInput) (*mcp.CallToolResult, any, error) {
+	return callAPIAndReturnToolResult(ctx, s.configAPICall, "failed making config api call: ")
+}
+
+type configPendingChangesResponse struct {
+	ConfigPath   string         `json:"config_path"`
+	Status       string         `json:"status"`
+	ReloadNeeded bool           `json:"reload_needed"`
+	Changes      []configChange `json:"changes"`
+}
+
+// ConfigPendingChangesHandler handles the config pending changes tool.
+func (s *ServerContainer) ConfigPendingChangesHandler(ctx context.Context, req *mcp.CallToolRequest, input EmptyInput) (*mcp.CallToolResult, any, error) {
+	if s.prometheusConfigPath == "" {
+		return newToolErrorResult("failed checking config pending changes: " + errConfigPathNotSet.Error()), nil, nil
+	}
+
+	result, err := s.configPendingChangesAPICall(ctx)
+	if err != nil {
+		return newToolErrorResult("failed checking config pending changes: " + err.Error()), nil, nil
+	}
+
+	return newToolTextResult(result), nil, nil
+}
+
+// ConfigDiffHandler handles the config diff tool.
+func (s *ServerContainer) ConfigDiffHandler(ctx context.Context, req *mcp.CallToolRequest, input EmptyInput) (*mcp.CallToolResult, any, error) {
+	if s.prometheusConfigPath == "" {
+		return newToolErrorResult("failed diffing config: " + errConfigPathNotSet.Error()), nil, nil
+	}
+
+	result, err := s.configDiffAPICall(ctx)
+	if err != nil {
+		return newToolErrorResult("failed diffing config: " + err.Error()), nil, nil
+	}
+
+	return newToolTextResult(result), nil, nil
+}
+
+type jobConfigResponse struct {
+	JobName string `json:"job_name"`
+	Config  string `json:"config"`
+}
+
+// JobConfigHandler handles the job config tool.
+func (s *ServerContainer) JobConfigHandler(ctx context.Context, req *mcp.CallToolRequest, input JobConfigInput) (*mcp.CallToolResult, any, error) {
+	if input.JobName == "" {
+		return newToolErrorResult("job_name parameter is required"), nil, nil
+	}
+
+	result, err := s.jobConfigAPICall(ctx, input.JobName)
+	if err != nil {
+		return newToolErrorResult("failed getting job config: " + err.Error()), nil, nil
+	}
+
+	return newToolTextResult(result), nil, nil
+}
+
+type scrapeConfigsResponse struct {
+	ScrapeConfigs     []map[string]any `json:"scrape_configs"`
+	ScrapeConfigFiles []string         `json:"scrape_config_files,omitempty"`
+	Message           string           `json:"message,omitempty"`
+}
+
+// ScrapeConfigsHandler handles the scrape configs tool.
+func (s *ServerContainer) ScrapeConfigsHandler(ctx context.Context, req *mcp.CallToolRequest, input ScrapeConfigsInput) (*mcp.CallToolResult, any, error) {
+	result, err := s.scrapeConfigsAPICall(ctx, input.JobName)
+	if err != nil {
+		return newToolErrorResult("failed getting scrape configs: " + err.Error()), nil, nil
+	}
+
+	return newToolTextResult(result), nil, nil
+}
+
+type activeAlertDetail struct {
+	AlertName        string         `json:"alertname"`
+	State            string         `json:"state"`
+	ActiveAt         time.Time      `json:"active_at"`
+	ActiveFor        string         `json:"active_for"`
+	ActiveForSeconds float64        `json:"active_for_seconds"`
+	Value            string         `json:"value"`
+	Labels           model.LabelSet `json:"labels"`
+	Annotations      model.LabelSet `json:"annotations"`
+}
+
+type activeAlertsDetailResponse struct {
+	Alerts    []activeAlertDetail `json:"alerts"`
+	Truncated string              `json:"truncated,omitempty"`
+}
+
+// ActiveAlertsDetailHandler handles the active alerts detail tool.
+func (s *ServerContainer) ActiveAlertsDetailHandler(ctx context.Context, req *mcp.CallToolRequest, input ActiveAlertsDetailInput) (*mcp.CallToolResult, any, error) {
+	state := promv1.AlertState(strings.ToLower(input.State))
+	switch state {
+	case "", promv1.AlertStateFiring, promv1.AlertStatePending:
+	default:
+		return newToolErrorResult("state must be one of 'firing' or 'pending'"), nil, nil
+	}
+
+	truncationLimit := s.GetEffectiveTruncationLimit(input.TruncationLimit)
+	result, err := s.activeAlertsDetailAPICall(ctx, state, time.Now(), truncationLimit)
+	if err != nil {
+		return newToolErrorResult("failed making alerts api call: " + err.Error()), nil, nil
+	}
+
+	return newToolTextResult(result), nil, nil
+}
+
+type targetChurnEntry struct {
+	ScrapePool string         `json:"scrape_pool"`
+	ScrapeURL  string         `json:"scrape_url,omitempty"`
+	Labels     model.LabelSet `json:"labels,omitempty"`
+}
+
+type targetChurnResponse struct {
+	BaselineSet     bool               `json:"baseline_set,omitempty"`
+	PreviousTargets int                `json:"previous_targets"`
+	CurrentTargets  int                `json:"current_targets"`
+	Appeared        []targetChurnEntry `json:"appeared"`
+	Disappeared     []targetChurnEntry `json:"disappeared"`
+	Truncated       string             `json:"truncated,omitempty"`
+}
+
+// TargetChurnHandler handles the target churn tool.
+func (s *ServerContainer) TargetChurnHandler(ctx context.Context, req *mcp.CallToolRequest, input TargetChurnInput) (*mcp.CallToolResult, any, error) {
+	ctx, err := s.withTarget(ctx, input.Target)
+	if err != nil {
+		return newToolErrorResult(err.Error()), nil, nil
+	}
+
+	truncationLimit := s.GetEffectiveTruncationLimit(input.TruncationLimit)
+	result, err := s.targetChurnAPICall(ctx, truncationLimit)
+	if err != nil {
+		return newToolErrorResult("failed making targets api call: " + err.Error()), nil, nil
+	}
+
+	return newToolTextResult(result), nil, nil
+}
+
+// defaultSampleLimitThreshold is the fraction of a target's sample limit at
+// which it is flagged as at risk by the sample limits tool.
+const defaultSampleLimitThreshold = 0.8
+
+type sampleLimitTarget struct {
+	Job                   string  `json:"job"`
+	Instance              string  `json:"instance"`
+	SamplesScraped        float64 `json:"samples_scraped"`
+	SamplesPostRelabeling float64 `json:"samples_post_metric_relabeling"`
+	SampleLimit           int     `json:"sample_limit"`
+	Utilization           float64 `json:"utilization"`
+	AtRisk                bool    `json:"at_risk"`
+}
+
+type sampleLimitsResponse struct {
+	Threshold        float64             `json:"threshold"`
+	AtRiskCount      int                 `json:"at_risk_count"`
+	UnlimitedTargets int                 `json:"unlimited_targets"`
+	Targets          []sampleLimitTarget `json:"targets"`
+	Truncated        string              `json:"truncated,omitempty"`
+}
+
+// SampleLimitsHandler handles the sample limits tool.
+func (s *ServerContainer) SampleLimitsHandler(ctx context.Context, req *mcp.CallToolRequest, input SampleLimitsInput) (*mcp.CallToolResult, any, error) {
+	threshold := input.Threshold
+	if threshold == 0 {
+		threshold = defaultSampleLimitThreshold
+	}
+	if threshold < 0 || threshold > 1 {
+		return newToolErrorResult("threshold must be between 0 and 1"), nil, nil
+	}
+
+	truncationLimit := s.GetEffectiveTruncationLimit(input.TruncationLimit)
+	result, err := s.sampleLimitsAPICall(ctx, threshold, time.Now(), truncationLimit)
+	if err != nil {
+		return newToolErrorResult("failed checking sample limits: " + err.Error()), nil, nil
+	}
+
+	return newToolTextResult(result), nil, nil
+}
+
+// defaultFleetHealthThreshold is the percentage of targets up below which a
+// group is flagged by the fleet health tool.
+const defaultFleetHealthThreshold = 90.0
+
+type fleetHealthGroup struct {
+	Value          string  `json:"value"`
+	MissingLabel   bool    `json:"missing_label,omitempty"`
+	TargetsUp      int     `json:"targets_up"`
+	TargetsTotal   int     `json:"targets_total"`
+	PercentUp      float64 `json:"percent_up"`
+	BelowThreshold bool    `json:"below_threshold"`
+}
+
+type fleetHealthResponse struct {
+	GroupBy              string             `json:"group_by"`
+	Threshold            float64            `json:"threshold"`
+	TargetsUp            int                `json:"targets_up"`
+	TargetsTotal         int                `json:"targets_total"`
+	PercentUp            float64            `json:"percent_up"`
+	GroupsBelowThreshold int                `json:"groups_below_threshold"`
+	Groups               []fleetHealthGroup `json:"groups"`
+	Truncated            string             `json:"truncated,omitempty"`
+}
+
+// FleetHealthHandler handles the fleet health tool.
+func (s *ServerContainer) FleetHealthHandler(ctx context.Context, req *mcp.CallToolRequest, input FleetHealthInput) (*mcp.CallToolResult, any, error) {
+	ctx, err := s.withTarget(ctx, input.Target)
+	if err != nil {
+		return newToolErrorResult(err.Error()), nil, nil
+	}
+
+	if input.GroupBy == "" {
+		return newToolErrorResult("group_by parameter is required"), nil, nil
+	}
+
+	threshold := input.Threshold
+	if threshold == 0 {
+		threshold = defaultFleetHealthThreshold
+	}
+	if threshold < 0 || threshold > 100 {
+		return newToolErrorResult("threshold must be between 0 and 100"), nil, nil
+	}
+
+	truncationLimit := s.GetEffectiveTruncationLimit(input.TruncationLimit)
+	result, err := s.fleetHealthAPICall(ctx, input.GroupBy, threshold, time.Now(), truncationLimit)
+	if err != nil {
+		return newToolErrorResult("failed checking fleet health: " + err.Error()), nil, nil
+	}
+
+	return newToolTextResult(result), nil, nil
+}
+
+type whyNoDataTarget struct {
+	ScrapeURL  string    `json:"scrape_url"`
+	Health     string    `json:"health"`
+	LastError  string    `json:"last_error,omitempty"`
+	LastScrape time.Time `json:"last_scrape"`
+}
+
+type whyNoDataJob struct {
+	Job            string            `json:"job"`
+	TargetsUp      int               `json:"targets_up"`
+	TargetsDown    int               `json:"targets_down"`
+	TargetsUnknown int               `json:"targets_unknown"`
+	TargetsDropped int               `json:"targets_dropped"`
+	DownTargets    []whyNoDataTarget `json:"down_targets,omitempty"`
+}
+
+type whyNoDataResponse struct {
+	Selector         string         `json:"selector"`
+	Lookback         string         `json:"lookback"`
+	Status           string         `json:"status"`
+	Diagnosis        string         `json:"diagnosis"`
+	SeriesInLookback int            `json:"series_in_lookback"`
+	CurrentSeries    int            `json:"current_series"`
+	Jobs             []whyNoDataJob `json:"jobs,omitempty"`
+	Findings         []string       `json:"findings"`
+}
+
+// Statuses of the why_no_data tool's diagnosis.
+const (
+	whyNoDataStatusHasData    = "has_data"
+	whyNoDataStatusStale      = "stale"
+	whyNoDataStatusNoSeries   = "no_matching_series"
+	whyNoDataStatusTargetDown = "target_down"
+)
+
+// WhyNoDataHandler handles the why no data tool.
+func (s *ServerContainer) WhyNoDataHandler(ctx context.Context, req *mcp.CallToolRequest, input WhyNoDataInput) (*mcp.CallToolResult, any, error) {
+	ctx, err := s.withTarget(ctx, input.Target)
+	if err != nil {
+		return newToolErrorResult(err.Error()), nil, nil
+	}
+
+	if input.Selector == "" {
+		return newToolErrorResult("selector parameter is required"), nil, nil
+	}
+	matchers, err := promqlParser.ParseMetricSelector(input.Selector)
+	if err != nil {
+		return newToolErrorResult(fmt.Sprintf("failed to parse selector: %v", err)), nil, nil
+	}
+
+	lookback := defaultWhyNoDataLookback
+	if input.Lookback != "" {
+		d, err := model.ParseDuration(input.Lookback)
+		if err != nil {
+			return newToolErrorResult(fmt.Sprintf("failed to parse lookback: %v", err)), nil, nil
+		}
+		if d <= 0 {
+			return newToolErrorResult("lookback must be a positive duration (e.g. '1h')"), nil, nil
+		}
+		lookback = time.Duration(d)
+	}
+
+	result, err := s.whyNoDataAPICall(ctx, input.Selector, matchers, lookback, time.Now())
+	if err != nil {
+		return newToolErrorResult("failed diagnosing missing data: " + err.Error()), nil, nil
+	}
+
+	return newToolTextResult(result), nil, nil
+}
+
+type seriesGap struct {
+	Start          time.Time `json:"start"`
+	End            time.Time `json:"end"`
+	Duration       string    `json:"duration"`
+	MissingSamples int       `json:"missing_samples"`
+}
+
+type detectGapsSeries struct {
+	Labels         string      `json:"labels"`
+	Samples        int         `json:"samples"`
+	MissingSamples int         `json:"missing_samples"`
+	GapCount       int         `json:"gap_count"`
+	Gaps           []seriesGap `json:"gaps"`
+	Truncated      string      `json:"truncated,omitempty"`
+}
+
+type detectGapsResponse struct {
+	Selector       string             `json:"selector"`
+	Resolution     string             `json:"resolution"`
+	Start          time.Time          `json:"start"`
+	End            time.Time          `json:"end"`
+	SeriesCount    int                `json:"series_count"`
+	SeriesWithGaps int                `json:"series_with_gaps"`
+	Series         []detectGapsSeries `json:"series"`
+	Message        string             `json:"message,omitempty"`
+	Truncated      string             `json:"truncated,omitempty"`
+}
+
+// DetectGapsHandler handles the detect gaps tool.
+func (s *ServerContainer) DetectGapsHandler(ctx context.Context, req *mcp.CallToolRequest, input DetectGapsInput) (*mcp.CallToolResult, any, error) {
+	ctx, err := s.withTarget(ctx, input.Target)
+	if err != nil {
+		return newToolErrorResult(err.Error()), nil, nil
+	}
+
+	if input.Selector == "" {
+		return newToolErrorResult("selector parameter is required"), nil, nil
+	}
+	if _, err := promqlParser.ParseMetricSelector(input.Selector); err != nil {
+		return newToolErrorResult(fmt.Sprintf("selector must be a series selector, e.g. up{job=\"node\"}: %v", err)), nil, nil
+	}
+	if input.Resolution == "" {
+		return newToolErrorResult("resolution parameter is required"), nil, nil
+	}
+	resolution, err := model.ParseDuration(input.Resolution)
+	if err != nil {
+		return newToolErrorResult(fmt.Sprintf("failed to parse resolution: %v", err)), nil, nil
+	}
+	if resolution <= 0 {
+		return newToolErrorResult("resolution must be a positive duration (e.g. '15s', '1m')"), nil, nil
+	}
+
+	endTs, err := parseTimeWithDefault(input.EndTime, time.Now())
+	if err != nil {
+		return newToolErrorResult(fmt.Sprintf("failed to parse end_time: %v", err)), nil, nil
+	}
+	startTs, err := parseTimeWithDefault(input.StartTime, endTs.Add(s.getLookbackDelta()))
+	if err != nil {
+		return newToolErrorResult(fmt.Sprintf("failed to parse start_time: %v", err)), nil, nil
+	}
+	if !startTs.Before(endTs) {
+		return newToolErrorResult("start_time must be before end_time"), nil, nil
+	}
+
+	truncationLimit := s.GetEffectiveTruncationLimit(input.TruncationLimit)
+	result, err := s.detectGapsAPICall(ctx, input.Selector, startTs, endTs, time.Duration(resolution), truncationLimit)
+	if err != nil {
+		return newToolErrorResult("failed detecting gaps: " + err.Error()), nil, nil
+	}
+
+	return newToolTextResult(result), nil, nil
+}
+
+type metricsMissingMetadataResponse struct {
+	MetricsChecked           int      `json:"metrics_checked"`
+	ExcludedRecordingRules   int      `json:"excluded_recording_rules"`
+	ExcludedSyntheticMetrics int      `json:"excluded_synthetic_metrics"`
+	MissingMetadataCount     int      `json:"missing_metadata_count"`
+	MissingMetadata          []string `json:"missing_metadata"`
+	Notes                    []string `json:"notes,omitempty"`
+	Truncated                string   `json:"truncated,omitempty"`
+}
+
+// MetricsMissingMetadataHandler handles the metrics missing metadata tool.
+func (s *ServerContainer) MetricsMissingMetadataHandler(ctx context.Context, req *mcp.CallToolRequest, input MetricsMissingMetadataInput) (*mcp.CallToolResult, any, error) {
+	startTs, endTs, err := parseTimeRangeInputWithDefaults(input.TimeRangeInput, time.Time{}, time.Time{})
+	if err != nil {
+		return newToolErrorResult(err.Error()), nil, nil
+	}
+
+	truncationLimit := s.GetEffectiveTruncationLimit(input.TruncationLimit)
+	result, err := s.metricsMissingMetadataAPICall(ctx, startTs, endTs, truncationLimit)
+	if err != nil {
+		return newToolErrorResult("failed checking metrics for missing metadata: " + err.Error()), nil, nil
+	}
+
+	return newToolTextResult(result), nil, nil
+}
+
+// RuntimeInfoHandler handles the runtime info tool.
+func (s *ServerContainer) RuntimeInfoHandler(ctx context.Context, req *mcp.CallToolRequest, input EmptyInput) (*mcp.CallToolResult, any, error) {
+	return callAPIAndReturnToolResult(ctx, s.runtimeinfoAPICall, "failed making runtime info api call: ")
+}
+
+// ListRulesHandler handles the list rules tool.
+func (s *ServerContainer) ListRulesHandler(ctx context.Context, req *mcp.CallToolRequest, input ListRulesInput) (*mcp.CallToolResult, any, error) {
+	ruleType := strings.ToLower(input.Type)
+	switch ruleType {
+	case "", ruleTypeAny, ruleTypeAlerting, ruleTypeRecording:
+	default:
+		return newToolErrorResult("type must be one of 'alerting', 'recording', or 'any'"), nil, nil
+	}
+
+	result, err := s.rulesAPICall(ctx, ruleType, input.RuleGroup, input.File, input.ExcludeAlerts)
+	if err != nil {
+		return newToolErrorResult("failed making rules api call: " + err.Error()), nil, nil
+	}
+
+	return newToolTextResult(result), nil, nil
+}
+
+// ListTargetsHandler handles the list targets tool.
+func (s *ServerContainer) ListTargetsHandler(ctx context.Context, req *mcp.CallToolRequest, input ListTargetsInput) (*mcp.CallToolResult, any, error) {
+	state := strings.ToLower(input.State)
+	switch state {
+	case "", targetStateAny, targetStateActive, targetStateDropped:
+	default:
+		return newToolErrorResult("state must be one of 'active', 'dropped', or 'any'"), nil, nil
+	}
+
+	health := promv1.HealthStatus(strings.ToLower(input.Health))
+	switch health {
+	case "", promv1.HealthGood, promv1.HealthBad, promv1.HealthUnknown:
+	default:
+		return newToolErrorResult("health must be one of 'up', 'down', or 'unknown'"), nil, nil
+	}
+
+	result, err := s.targetsAPICall(ctx, input.ScrapePool, state, health)
+	if err != nil {
+		return newToolErrorResult("failed making targets api call: " + err.Error()), nil, nil
+	}
+
+	return newToolTextResult(result), nil, nil
+}
+
+// ScrapeTargetHandler handles the scrape target tool.
+func (s *ServerContainer) ScrapeTargetHandler(ctx context.Context, req *mcp.CallToolRequest, input ScrapeTargetInput) (*mcp.CallToolResult, any, error) {
+	if input.URL == "" {
+		return newToolErrorResult("url is required"), nil, nil
+	}
+	u, err := url.Parse(input.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return newToolErrorResult("url must be an absolute http or https URL"), nil, nil
+	}
+
+	truncationLimit := s.GetEffectiveTruncationLimit(input.TruncationLimit)
+	result, err := s.scrapeTargetAPICall(ctx, input.URL, truncationLimit)
+	if err != nil {
+		return newToolErrorResult("failed scraping target: " + err.Error()), nil, nil
+	}
+
+	return newToolTextResult(result), nil, nil
+}
+
+// WALReplayHandler handles the WAL replay status tool.
+func (s *ServerContainer) WALReplayHandler(ctx context.Context, req *mcp.CallToolRequest, input EmptyInput) (*mcp.CallToolResult, any, error) {
+	return callAPIAndReturnToolResult(ctx, s.walReplayAPICall, "failed making WAL replay api call: ")
+}
+
+// Prometheus TSDB Admin tool handlers
+
+// checkConfirmation verifies the confirmation token passed to a destructive
+// tool. It returns an error describing how to confirm the operation if the
+// server requires confirmation and the token is missing or wrong, or nil if
+// the operation may proceed.
+func (s *ServerContainer) checkConfirmation(toolName, confirm string) error {
+	if !s.requireConfirmation {
+		return nil
+	}
+
+	if confirm == "" {
+		if s.revealConfirmation {
+			return fmt.Errorf("%s requires confirmation because it is destructive. Double check the arguments and, to proceed, call it again with the same arguments and the confirm argument set to %q", toolName, s.confirmationToken)
+		}
+		return fmt.Errorf("%s requires confirmation because it is destructive. Ask the user for the confirmation token configured on the MCP server and, to proceed, call it again with the same arguments and the confirm argument set to that token", toolName)
+	}
+
+	if subtle.ConstantTimeCompare([]byte(confirm), []byte(s.confirmationToken)) != 1 {
+		return fmt.Errorf("invalid confirmation token for %s", toolName)
+	}
+	return nil
+}
+
+// CleanTombstonesHandler handles the clean tombstones admin tool.
+func (s *ServerContainer) CleanTombstonesHandler(ctx context.Context, req *mcp.CallToolRequest, input CleanTombstonesInput) (*mcp.CallToolResult, any, error) {
+	if !s.tsdbAdminToolsEnabled {
+		return newToolErrorResult("failed making clean tombstones api call: " + errTSDBAdminToolsNotEnabled.Error()), nil, nil
+	}
+
+	if err := s.checkConfirmation(cleanTombstonesToolDef.Name, input.Confirm); err != nil {
+		return newToolErrorResult(err.Error()), nil, nil
+	}
+
+	logger := s.GetToolLogger(req, input)
+
+	logger.Warn("executing TSDB admin operation: clean tombstones")
+
+	result, err := s.cleanTombstonesAPICall(ctx)
+	if err != nil {
+		return newToolErrorResult("failed making clean tombstones api call: " + err.Error()), nil, nil
+	}
+
+	logger.Warn("clean tombstones completed successfully")
+	return newToolTextResult(result), nil, nil
+}
+
+// DeleteSeriesHandler handles the delete series admin tool.
+func (s *ServerContainer) DeleteSeriesHandler(ctx context.Context, req *mcp.CallToolRequest, input DeleteSeriesInput) (*mcp.CallToolResult, any, error) {
+	if !s.tsdbAdminToolsEnabled {
+		return newToolErrorResult("failed making delete series api call: " + errTSDBAdminToolsNotEnabled.Error()), nil, nil
+	}
+
+	if len(input.Matches) == 0 {
+		return newToolErrorResult("at least one matches parameter is required"), nil, nil
+	}
+
+	// Require explicit time bounds for destructive operations. Without them,
+	// Prometheus defaults to deleting across the entire retention window.
+	// This is almost certainly not intended behavior from the end user,
+	// but if it is, make them ask for it.
+	if input.StartTime == "" || input.EndTime == "" {
+		return newToolErrorResult("both start_time and end_time are required for delete_series to prevent accidental deletion of all data; specify explicit time bounds"), nil, nil
+	}
+
+	logger := s.GetToolLogger(req, input)
+
+	startTs, endTs, err := parseTimeRangeInputWithDefaults(input.TimeRangeInput, time.Time{}, time.Time{})
+	if err != nil {
+		return newToolErrorResult(err.Error()), nil, nil
+	}
+
+	if err := s.checkConfirmation(deleteSeriesToolDef.Name, input.Confirm); err != nil {
+		return newToolErrorResult(err.Error()), nil, nil
+	}
+
+	logger.Warn("executing TSDB admin operation: delete series")
+
+	result, err := s.deleteSeriesAPICall(ctx, input.Matches, startTs, endTs)
+	if err != nil {
+		return newToolErrorResult("failed making delete series api call: " + err.Error()), nil, nil
+	}
+
+	logger.Warn("delete series completed successfully")
+	return newToolTextResult(result), nil, nil
+}
+
+// SnapshotHandler handles the snapshot admin tool.
+func (s *ServerContainer) SnapshotHandler(ctx context.Context, req *mcp.CallToolRequest, input SnapshotInput) (*mcp.CallToolResult, any, error) {
+	if !s.tsdbAdminToolsEnabled {
+		return newToolErrorResult("failed making snapshot api call: " + errTSDBAdminToolsNotEnabled.Error()), nil, nil
+	}
+
+	logger := s.GetToolLogger(req, input)
+
+	logger.Warn("executing TSDB admin operation: snapshot")
+
+	result, err := s.snapshotAPICall(ctx, input.SkipHead)
+	if err != nil {
+		return newToolErrorResult("failed making snapshot api call: " + err.Error()), nil, nil
+	}
+
+	logger.Warn("snapshot completed successfully")
+	return newToolTextResult(result), nil, nil
+}
+
+// Management API handlers
+
+// HealthyHandler handles the healthy check tool.
+func (s *ServerContainer) HealthyHandler(ctx context.Context, req *mcp.CallToolRequest, input EmptyInput) (*mcp.CallToolResult, any, error) {
+	result, err := s.doManagementAPICall(ctx, http.MethodGet, mgmtAPIHealthyEndpoint)
+	if err != nil {
+		return newToolErrorResult("failed making healthy api call: " + err.Error()), nil, nil
+	}
+	return newToolTextResult(result), nil, nil
+}
+
+// ReadyHandler handles the ready check tool.
+func (s *ServerContainer) ReadyHandler(ctx context.Context, req *mcp.CallToolRequest, input EmptyInput) (*mcp.CallToolResult, any, error) {
+	result, err := s.doManagementAPICall(ctx, http.MethodGet, mgmtAPIReadyEndpoint)
+	if err != nil {
+		return newToolErrorResult("failed making ready api call: " + err.Error()), nil, nil
+	}
+	return newToolTextResult(result), nil, nil
+}
+
+// WaitReadyHandler handles the wait ready tool.
+func (s *ServerContainer) WaitReadyHandler(ctx context.Context, req *mcp.CallToolRequest, input WaitReadyInput) (*mcp.CallToolResult, any, error) {
+	timeout := defaultWaitReadyTimeout
+	if input.Timeout != "" {
+		parsedTimeout, err := model.ParseDuration(input.Timeout)
+		if err != nil {
+			return newToolErrorResult(fmt.Sprintf("failed to parse timeout: %v", err)), nil, nil
+		}
+		timeout = time.Duration(parsedTimeout)
+	}
+	if timeout <= 0 || timeout > maxWaitReadyTimeout {
+		return newToolErrorResult(fmt.Sprintf("timeout must be a positive duration of at most %s", model.Duration(maxWaitReadyTimeout))), nil, nil
+	}
+
+	interval := defaultWaitReadyPollInterval
+	if input.PollInterval != "" {
+		parsedInterval, err := model.ParseDuration(input.PollInterval)
+		if err != nil {
+			return newToolErrorResult(fmt.Sprintf("failed to parse poll_interval: %v", err)), nil, nil
+		}
+		interval = time.Duration(parsedInterval)
+	}
+	if interval < minWaitReadyPollInterval {
+		return newToolErrorResult(fmt.Sprintf("poll_interval must be at least %s", model.Duration(minWaitReadyPollInterval))), nil, nil
+	}
+
+	stopProgress := s.startProgressHeartbeat(ctx, req, "wait for ready")
+	result, err := s.waitReadyAPICall(ctx, timeout, interval, input.IncludeWALReplay)
+	stopProgress()
+	if err != nil {
+		return newToolErrorResult("failed waiting for ready: " + err.Error()), nil, nil
+	}
+	return newToolTextResult(result), nil, nil
+}
+
+// StatusOverviewHandler handles the status overview tool.
+func (s *ServerContainer) StatusOverviewHandler(ctx context.Context, req *mcp.CallToolRequest, input EmptyInput) (*mcp.CallToolResult, any, error) {
+	result, err := s.statusOverviewAPICall(ctx)
+	if err != nil {
+		return newToolErrorResult("failed making status overview api call: " + err.Error()), nil, nil
+	}
+	return newToolTextResult(result), nil, nil
+}
+
+// ReloadHandler handles the reload config tool.
+func (s *ServerContainer) ReloadHandler(ctx context.Context, req *mcp.CallToolRequest, input EmptyInput) (*mcp.CallToolResult, any, error) {
+	logger := s.GetToolLogger(req, nil)
+
+	logger.Warn("triggering Prometheus configuration reload")
+
+	result, err := s.doManagementAPICall(ctx, http.MethodPost, mgmtAPIReloadEndpoint)
+	if err != nil {
+		return newToolErrorResult("failed making reload api call: " + err.Error()), nil, nil
+	}
+
+	logger.Warn("reload completed successfully")
+	return newToolTextResult(result), nil, nil
+}
+
+// QuitHandler handles the quit/shutdown tool.
+func (s *ServerContainer) QuitHandler(ctx context.Context, req *mcp.CallToolRequest, input EmptyInput) (*mcp.CallToolResult, any, error) {
+	logger := s.GetToolLogger(req, nil)
+
+	logger.Warn("triggering Prometheus shutdown")
+
+	result, err := s.doManagementAPICall(ctx, http.MethodPost, mgmtAPIQuitEndpoint)
+	if err != nil {
+		return newToolErrorResult("failed making quit api call: " + err.Error()), nil, nil
+	}
+
+	logger.Warn("quit signal sent successfully - Prometheus is shutting down")
+	return newToolTextResult(result), nil, nil
+}
+
+type mcpConfigDocs struct {
+	Available    bool   `json:"available"`
+	SearchIndex  string `json:"search_index"`
+	IndexError   string `json:"index_error,omitempty"`
+	IndexTimeout string `json:"index_timeout"`
+	ReadRetries  int    `json:"read_retries"`
+}
+
+type mcpConfigResponse struct {
+	Version                 string            `json:"version"`
+	PrometheusURL           string            `json:"prometheus_url"`
+	PrometheusTargets       map[string]string `json:"prometheus_targets,omitempty"`
+	PrometheusBackend       string            `json:"prometheus_backend,omitempty"`
+	PrometheusConfigPath    string            `json:"prometheus_config_path,omitempty"`
+	PrometheusTimeout       string            `json:"prometheus_timeout"`
+	PrometheusMaxTimeout    string            `json:"prometheus_max_timeout"`
+	PrometheusLookbackDelta string            `json:"prometheus_lookback_delta"`
+	PrometheusRetries       int               `json:"prometheus_retries"`
+	PrometheusRetryBackoff  string            `json:"prometheus_retry_backoff"`
+	HTTPMaxResponseBytes    int64             `json:"http_max_response_bytes"`
+	UserAgent               string            `json:"user_agent,omitempty"`
+	TruncationLimit         int               `json:"truncation_limit"`
+	TruncationMode          string            `json:"truncation_mode"`
+	DisplayTimezone         string            `json:"display_timezone,omitempty"`
+	OutputFormat            string            `json:"output_format"`
+	StripHelpText           bool              `json:"strip_help_text"`
+	ClientLoggingEnabled    bool              `json:"client_logging_enabled"`
+	LogToolCalls            bool              `json:"log_tool_calls"`
+	AuditFile               string            `json:"audit_file,omitempty"`
+	RateLimitRPS            float64           `json:"rate_limit_rps"`
+	RateLimitBurst          int               `json:"rate_limit_burst"`
+	TSDBAdminToolsEnabled   bool              `json:"tsdb_admin_tools_enabled"`
+	RequireConfirmation     bool              `json:"require_confirmation"`
+	AllowEmptyMatchers      bool              `json:"allow_empty_matchers"`
+	AlertmanagerURL         string            `json:"alertmanager_url,omitempty"`
+	AlertmanagerSilences    bool              `json:"alertmanager_silences_enabled"`
+	RemoteReadURL           string            `json:"remote_read_url,omitempty"`
+	Transport               string            `json:"transport,omitempty"`
+	KeepAliveInterval       string            `json:"keepalive_interval"`
+	ProgressInterval        string            `json:"progress_interval"`
+	InstructionsFile        string            `json:"instructions_file,omitempty"`
+	CacheTTL                string            `json:"cache_ttl"`
+	MimirTenant             string            `json:"mimir_tenant,omitempty"`
+	RequestAuthorization    string            `json:"request_authorization,omitempty"`
+	EnabledTools            []string          `json:"enabled_tools"`
+	DisabledTools           []string          `json:"disabled_tools"`
+	Docs                    mcpConfigDocs     `json:"docs"`
+}
+
+// MCPConfigHandler handles the MCP server config tool.
+func (s *ServerContainer) MCPConfigHandler(ctx context.Context, req *mcp.CallToolRequest, input EmptyInput) (*mcp.CallToolResult, any, error) {
+	outputFormat := s.outputFormat
+	if outputFormat == "" {
+		outputFormat = OutputFormatJSON
+	}
+
+	resp := mcpConfigResponse{
+		Version:                 promversion.Version,
+		PrometheusURL:           redactURL(s.prometheusURL),
+		PrometheusTargets:       s.redactedPrometheusTargets(),
+		PrometheusBackend:       s.prometheusBackend,
+		PrometheusConfigPath:    s.prometheusConfigPath,
+		PrometheusTimeout:       model.Duration(s.apiTimeout).String(),
+		PrometheusMaxTimeout:    model.Duration(s.maxAPITimeout()).String(),
+		PrometheusLookbackDelta: model.Duration(-s.getLookbackDelta()).String(),
+		PrometheusRetries:       s.apiRetries,
+		PrometheusRetryBackoff:  model.Duration(s.apiRetryBackoff).String(),
+		HTTPMaxResponseBytes:    s.maxResponseBytes,
+		UserAgent:               s.userAgent,
+		TruncationLimit:         s.truncationLimit,
+		TruncationMode:          s.truncationMode,
+		DisplayTimezone:         s.displayTimezone(),
+		OutputFormat:            outputFormat,
+		StripHelpText:           s.stripHelpText,
+		ClientLoggingEnabled:    s.clientLoggingEnabled,
+		LogToolCalls:            s.logToolCalls,
+		AuditFile:               s.auditFile,
+		TSDBAdminToolsEnabled:   s.tsdbAdminToolsEnabled,
+		RequireConfirmation:     s.requireConfirmation,
+		AllowEmptyMatchers:      s.allowEmptyMatchers,
+		AlertmanagerSilences:    s.silenceToolsEnabled,
+		Transport:               s.transport,
+		KeepAliveInterval:       model.Duration(s.keepAlive).String(),
+		ProgressInterval:        model.Duration(s.progressInterval).String(),
+		InstructionsFile:        s.instructionsFile,
+		CacheTTL:                model.Duration(s.responseCache.ttlOrZero()).String(),
+		MimirTenant:             s.tenant(ctx),
+		EnabledTools:            s.enabledTools,
+		DisabledTools:           s.disabledTools,
+		Docs:                    s.docsStatus(),
+	}
+	if s.alertmanagerURL != "" {
+		resp.AlertmanagerURL = redactURL(s.alertmanagerURL)
+	}
+	if s.remoteReadURL != "" {
+		resp.RemoteReadURL = redactURL(s.remoteReadURL)
+	}
+	if s.rateLimiter != nil {
+		resp.RateLimitRPS = float64(s.rateLimiter.limit)
+		resp.RateLimitBurst = s.rateLimiter.burst
+	}
+	if getAuthFromContext(ctx) != "" {
+		resp.RequestAuthorization = "<redacted>"
+	}
+
+	result, err := s.FormatOutput(resp)
+	if err != nil {
+		return newToolErrorResult("failed to format MCP server config: " + err.Error()), nil, nil
+	}
+
+	return newToolTextResult(result), nil, nil
+}
+
+// redactedPrometheusTargets returns the named Prometheus targets with any
+// credentials in their URLs redacted.
+func (s *ServerContainer) redactedPrometheusTargets() map[string]string {
+	if len(s.prometheusTargets) == 0 {
+		return nil
+	}
+
+	targets := make(map[string]string, len(s.prometheusTargets))
+	for name, target := range s.prometheusTargets {
+		targets[name] = redactURL(target.url)
+	}
+	return targets
+}
+
+// docsStatus summarizes the state of the documentation filesystem and
+// search index for the MCP server config tool.
+func (s *ServerContainer) docsStatus() mcpConfigDocs {
+	s.docsMu.RLock()
+	ds := s.docs
+	s.docsMu.RUnlock()
+
+	status := mcpConfigDocs{
+		SearchIndex:  "unavailable",
+		IndexTimeout: model.Duration(s.docsIndexTimeout).String(),
+	}
+	if ds == nil {
+		return status
+	}
+
+	status.Available = ds.fs != nil
+	status.ReadRetries = ds.readRetries
+	switch {
+	case ds.indexErr != nil:
+		status.IndexError = ds.indexErr.Error()
+	case ds.searchIndex != nil:
+		status.SearchIndex = "ready"
+	}
+	return status
+}
+
+// redactURL returns the URL with any password replaced, so it is safe to
+// show to clients.
+func redactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "<unparseable>"
+	}
+	return u.Redacted()
+}
+
+// truncationLimitNote explains the unit of the truncation limit for the given
+// truncation mode.
+func truncationLimitNote(mode string) string {
+	unit := "lines/entries"
+	switch mode {
+	case TruncationModeBytes:
+		unit = "bytes for query results and entries for structured responses"
+	case TruncationModeTokens:
+		unit = fmt.Sprintf("estimated tokens (%d characters each) for query results and entries for structured responses", charsPerToken)
+	}
+	return "truncation_limit is in " + unit + ", 0 means truncation is disabled. It can be overridden per call with the truncation_limit argument, where -1 disables truncation."
+}
+
+type effectiveLimitsResponse struct {
+	APITimeout                   string   `json:"api_timeout"`
+	MaxAPITimeout                string   `json:"max_api_timeout"`
+	TruncationLimit              int      `json:"truncation_limit"`
+	TruncationMode               string   `json:"truncation_mode"`
+	RangeQueryMaxPointsPerSeries int      `json:"range_query_max_points_per_series"`
+	RangeQueryDefaultPoints      int      `json:"range_query_default_points"`
+	RangeQueryDefaultRange       string   `json:"range_query_default_range"`
+	MaxRange                     string   `json:"max_range"`
+	ToolCallRateLimit            string   `json:"tool_call_rate_limit"`
+	MaxConcurrentRequests        string   `json:"max_concurrent_requests"`
+	ClientLogMinInterval         string   `json:"client_log_min_interval,omitempty"`
+	SessionAuthorization         bool     `json:"session_authorization"`
+	Notes                        []string `json:"notes"`
+}
+
+// EffectiveLimitsHandler handles the effective limits tool.
+func (s *ServerContainer) EffectiveLimitsHandler(ctx context.Context, req *mcp.CallToolRequest, input EmptyInput) (*mcp.CallToolResult, any, error) {
+	resp := effectiveLimitsResponse{
+		APITimeout:                   model.Duration(s.apiTimeout).String(),
+		MaxAPITimeout:                model.Duration(s.maxAPITimeout()).String(),
+		TruncationLimit:              s.truncationLimit,
+		TruncationMode:               s.truncationMode,
+		RangeQueryMaxPointsPerSeries: prometheusMaxPointsPerSeries,
+		RangeQueryDefaultPoints:      defaultRangeQueryDataPoints,
+		RangeQueryDefaultRange:       model.Duration(-s.getLookbackDelta()).String(),
+		MaxRange:                     "unlimited",
+		ToolCallRateLimit:            "none",
+		MaxConcurrentRequests:        "unlimited",
+		SessionAuthorization:         getAuthFromContext(ctx) != "",
+		Notes: []string{
+			truncationLimitNote(s.truncationMode),
+			fmt.Sprintf("Prometheus rejects range queries where (end - start) / step exceeds %d points per series; increase step for long ranges.", prometheusMaxPointsPerSeries),
+			"Prometheus may enforce additional limits that are not visible here, such as --query.timeout and --query.max-samples. Use the flags tool to check them.",
+			"The query, range_query, and series tools accept a timeout argument overriding api_timeout for a single call, capped at max_api_timeout.",
+		},
+	}
+	if s.clientLoggingEnabled {
+		resp.ClientLogMinInterval = model.Duration(clientLoggingInterval).String()
+	}
+	if s.rateLimiter != nil {
+		resp.ToolCallRateLimit = fmt.Sprintf("%g calls/s shared by all clients with a burst of %d", float64(s.rateLimiter.limit), s.rateLimiter.burst)
+	}
+	if s.apiCallLimiter != nil {
+		resp.MaxConcurrentRequests = fmt.Sprintf("%d requests to Prometheus shared by all sessions, further requests wait until the API timeout", s.apiCallLimiter.limit)
+	}
+	if resp.SessionAuthorization {
+		resp.Notes = append(resp.Notes, "This session uses its own Authorization header; Prometheus may apply per-tenant limits to it.")
+	}
+
+	result, err := s.FormatOutput(resp)
+	if err != nil {
+		return newToolErrorResult("failed to format effective limits: " + err.Error()), nil, nil
+	}
+
+	return newToolTextResult(result), nil, nil
+}
+
+type relabelStep struct {
+	Rule   int               `json:"rule"`
+	Action string            `json:"action"`
+	Labels map[string]string `json:"labels"`
+	Kept   bool              `json:"kept"`
+}
+
+type testRelabelResponse struct {
+	InputLabels  map[string]string `json:"input_labels"`
+	OutputLabels map[string]string `json:"output_labels"`
+	Kept         bool              `json:"kept"`
+	Steps        []relabelStep     `json:"steps"`
+}
+
+// TestRelabelHandler handles the test relabel tool.
+func (s *ServerContainer) TestRelabelHandler(ctx context.Context, req *mcp.CallToolRequest, input TestRelabelInput) (*mcp.CallToolResult, any, error) {
+	if len(input.Labels) == 0 {
+		return newToolErrorResult("labels parameter is required"), nil, nil
+	}
+	if strings.TrimSpace(input.Config) == "" {
+		return newToolErrorResult("config parameter is required"), nil, nil
+	}
+
+	cfgs, err := parseRelabelConfigs(input.Config)
+	if err != nil {
+		return newToolErrorResult(err.Error()), nil, nil
+	}
+
+	resp := testRelabelResponse{
+		InputLabels: input.Labels,
+		Kept:        true,
+		Steps:       make([]relabelStep, 0, len(cfgs)),
+	}
+
+	// Apply rules one at a time to record the label set after each step.
+	lb := labels.NewBuilder(labels.FromMap(input.Labels))
+	for i, cfg := range cfgs {
+		kept := relabel.ProcessBuilder(lb, cfg)
+		resp.Steps = append(resp.Steps, relabelStep{
+			Rule:   i,
+			Action: string(cfg.Action),
+			Labels: lb.Labels().Map(),
+			Kept:   kept,
+		})
+		if !kept {
+			resp.Kept = false
+			break
+		}
+	}
+	if resp.Kept {
+		resp.OutputLabels = lb.Labels().Map()
+	}
+
+	result, err := s.FormatOutput(resp)
+	if err != nil {
+		return newToolErrorResult(err.Error()), nil, nil
+	}
+
+	return newToolTextResult(result), nil, nil
+}
+
+// parseRelabelConfigs parses and validates relabel configs from YAML. The
+// YAML may be either a list of relabel config entries or a single entry.
+func parseRelabelConfigs(config string) ([]*relabel.Config, error) {
+	var node yaml.Node
+	if err := yaml.Unmarshal([]byte(config), &node); err != nil {
+		return nil, fmt.Errorf("failed to parse relabel config YAML: %w", err)
+	}
+
+	var cfgs []*relabel.Config
+	if len(node.Content) > 0 && node.Content[0].Kind == yaml.MappingNode {
+		cfg := &relabel.Config{}
+		if err := node.Content[0].Decode(cfg); err != nil {
+			return nil, fmt.Errorf("failed to parse relabel config: %w", err)
+		}
+		cfgs = append(cfgs, cfg)
+	} else if err := node.Decode(&cfgs); err != nil {
+		return nil, fmt.Errorf("failed to parse relabel configs: %w", err)
+	}
+
+	if len(cfgs) == 0 {
+		return nil, errors.New("relabel config must contain at least one rule")
+	}
+
+	for i, cfg := range cfgs {
+		if cfg == nil {
+			return nil, fmt.Errorf("relabel config rule %d is empty", i)
+		}
+		if err := cfg.Validate(model.UTF8Validation); err != nil {
+			return nil, fmt.Errorf("relabel config rule %d is invalid: %w", i, err)
+		}
+	}
+
+	return cfgs, nil
+}
+
+// Documentation tool handlers
+
+// DocsListHandler handles the docs list tool.
+func (s *ServerContainer) DocsListHandler(ctx context.Context, req *mcp.CallToolRequest, input EmptyInput) (*mcp.CallToolResult, any, error) {
+	resourceReq := &mcp.ReadResourceRequest{
+		Params: &mcp.ReadResourceParams{
+			URI: resourcePrefix + "docs",
+		},
+	}
+
+	resourceResult, err := s.DocsListResourceHandler(ctx, resourceReq)
+	if err != nil {
+		return newToolErrorResult("failed listing docs: " + err.Error()), nil, nil
+	}
+
+	return embedResourceContentsInToolResult(resourceResult, &mcp.CallToolResult{}), nil, nil
+}
+
+// DocsReadHandler handles the docs read tool.
+func (s *ServerContainer) DocsReadHandler(ctx context.Context, req *mcp.CallToolRequest, input DocsReadInput) (*mcp.CallToolResult, any, error) {
+	if input.File == "" {
+		return newToolErrorResult("file parameter is required"), nil, nil
+	}
+
+	// Construct the resource URI with proper URL encoding
+	uri := resourcePrefix + "docs/" + url.PathEscape(input.File)
+	resourceReq := &mcp.ReadResourceRequest{
+		Params: &mcp.ReadResourceParams{
+			URI: uri,
+		},
+	}
+
+	resourceResult, err := s.DocsReadResourceHandler(ctx, resourceReq)
+	if err != nil {
+		return newToolErrorResult("failed reading doc file: " + err.Error()), nil, nil
+	}
+
+	if input.StartLine == 0 && input.EndLine == 0 {
+		return embedResourceContentsInToolResult(resourceResult, &mcp.CallToolResult{}), nil, nil
+	}
+
+	var (
+		start, end, total int
+		toolResult        = &mcp.CallToolResult{}
+	)
+	for _, contents := range resourceResult.Contents {
+		contents.Text, start, end, total = docLineRange(contents.Text, input.StartLine, input.EndLine)
+	}
+	toolResult.Content = append(toolResult.Content, &mcp.TextContent{
+		Text: fmt.Sprintf("Showing lines %d-%d of %d total lines in %s.", start, end, total, input.File),
+	})
+
+	return embedResourceContentsInToolResult(resourceResult, toolResult), nil, nil
+}
+
+// DocsSearchHandler handles the docs search tool.
+func (s *ServerContainer) DocsSearchHandler(ctx context.Context, req *mcp.CallToolRequest, input DocsSearchInput) (*mcp.CallToolResult, any, error) {
+	logger := s.GetToolLogger(req, input)
+
+	if input.Query == "" {
+		return newToolErrorResult("query parameter is required"), nil, nil
+	}
+
+	matches, err := s.SearchDocs(input.Query, input.Limit)
+	if err != nil {
+		return newToolErrorResult("failed searching docs: " + err.Error()), nil, nil
+	}
+
+	if len(matches) == 0 {
+		return newToolTextResult("No documentation found matching query: " + input.Query), nil, nil
+	}
+
+	// Extract unique file names from chunk IDs, in order of relevance.
+	matchingDocsFiles := []string{}
+	docsFilesSeen := make(map[string]struct{})
+	for _, match := range matches {
+		parts := strings.Split(match.ChunkID, "#")
+		if len(parts) != 2 {
+			// Valid chunk format is `filename#chunkID`.
+			logger.Warn("skipping malformed chunk ID", "chunk_id", match.ChunkID)
+			continue
+		}
+		name := parts[0]
+		if _, seen := docsFilesSeen[name]; !seen {
+			docsFilesSeen[name] = struct{}{}
+			matchingDocsFiles = append(matchingDocsFiles, name)
+		}
+	}
+
+	logger.Debug("docs search completed", "matching_files", len(matchingDocsFiles))
+
+	// Read all matching files via the resource handler and combine results.
+	resourceResults := make([]*mcp.ReadResourceResult, 0, len(matchingDocsFiles))
+	for _, file := range matchingDocsFiles {
+		uri := resourcePrefix + "docs/" + url.PathEscape(file)
+		// Allocate a fresh request per iteration to avoid shared-struct mutation
+		// across concurrent or sequential calls to DocsReadResourceHandler.
+		resourceReq := &mcp.ReadResourceRequest{Params: &mcp.ReadResourceParams{URI: uri}}
+
+		resourceResult, err := s.DocsReadResourceHandler(ctx, resourceReq)
+		if err != nil {
+			return newToolErrorResult("failed reading doc file: " + err.Error()), nil, nil
+		}
+		resourceResults = append(resourceResults, resourceResult)
+	}
+
+	rankedMatches, err := s.FormatOutput(matches)
+	if err != nil {
+		return newToolErrorResult(err.Error()), nil, nil
+	}
+	searchSummary := fmt.Sprintf("Found %d documentation files matching query %q: %q\n", len(matchingDocsFiles), input.Query, matchingDocsFiles) +
+		"Matches ranked by relevance, with a snippet of each:\n" + rankedMatches
+
+	combinedSearchContents := concatResourceContents(resourceResults...)
+	content := make([]mcp.Content, 0, len(combinedSearchContents)+1)
+	content = append(content, &mcp.TextContent{Text: searchSummary})
+	for _, rc := range combinedSearchContents {
+		content = append(content, &mcp.EmbeddedResource{Resource: rc})
+	}
+
+	return &mcp.CallToolResult{Content: content}, nil, nil
+}
+
+// promqlRecipeDocsLimit is the default number of related docs snippets
+// returned by the PromQL recipe tool.
+const promqlRecipeDocsLimit = 5
+
+// promqlRecipeNote is included in every PromQL recipe response to make clear
+// that the suggestion is advisory.
+const promqlRecipeNote = "This is a suggested starting point only and has not been executed. Replace <placeholders> with real metric names and label matchers (see the metric_metadata, label_names, and label_values tools), then validate the query before relying on it."
+
+type promqlRecipeDoc struct {
+	ChunkID string `json:"chunk_id"`
+	File    string `json:"file"`
+	Snippet string `json:"snippet"`
+}
+
+type promqlRecipeResponse struct {
+	Goal string `json:"goal"`
+	Note string `json:"note"`
+	promqlRecipe
+	RelatedDocs     []promqlRecipeDoc `json:"related_docs"`
+	DocsSearchError string            `json:"docs_search_error,omitempty"`
+}
+
+// PromQLRecipeHandler handles the PromQL recipe tool.
+func (s *ServerContainer) PromQLRecipeHandler(ctx context.Context, req *mcp.CallToolRequest, input PromQLRecipeInput) (*mcp.CallToolResult, any, error) {
+	logger := s.GetToolLogger(req, input)
+
+	if input.Goal == "" {
+		return newToolErrorResult("goal parameter is required"), nil, nil
+	}
+
+	limit := input.Limit
+	if limit < 1 {
+		limit = promqlRecipeDocsLimit
+	}
+
+	recipe := suggestPromQLRecipe(input.Goal)
+	resp := promqlRecipeResponse{
+		Goal:         input.Goal,
+		Note:         promqlRecipeNote,
+		promqlRecipe: recipe,
+		RelatedDocs:  []promqlRecipeDoc{},
+	}
+
+	// Bias the search towards the functions used by the suggested skeleton.
+	searchQuery := input.Goal + " " + strings.Join(recipe.Functions, " ")
+	hits, err := s.searchDocsHits(searchQuery, limit)
+	if err != nil {
+		// Docs are supplementary here, so still return the skeleton.
+		logger.Warn("docs search failed for promql recipe", "err", err)
+		resp.DocsSearchError = err.Error()
+	}
+	for _, hit := range hits {
+		resp.RelatedDocs = append(resp.RelatedDocs, promqlRecipeDoc{
+			ChunkID: hit.ID,
+			File:    hit.File,
+			Snippet: docSnippet(hit.Content, searchQuery, docSnippetLength),
+		})
+	}
+
+	result, err := s.FormatOutput(resp)
+	if err != nil {
+		return newToolErrorResult(err.Error()), nil, nil
+	}
+
+	return newToolTextResult(result), nil, nil
+}
+
+// defaultExamplesLimit is the default number of examples returned by the
+// PromQL examples tool.
+const defaultExamplesLimit = 50
+
+type promqlExample struct {
+	File  string `json:"file"`
+	Query string `json:"query"`
+}
+
+// ExamplesHandler handles the PromQL examples tool.
+func (s *ServerContainer) ExamplesHandler(ctx context.Context, req *mcp.CallToolRequest, input ExamplesInput) (*mcp.CallToolResult, any, error) {
+	logger := s.GetToolLogger(req, input)
+
+	limit := input.Limit
+	if limit < 1 {
+		limit = defaultExamplesLimit
+	}
+
+	var files []string
+	if input.Query != "" {
+		hits, err := s.searchDocsHits(input.Query, defaultDocsSearchLimit)
+		if err != nil {
+			return newToolErrorResult("failed searching docs: " + err.Error()), nil, nil
+		}
+
+		seen := make(map[string]struct{})
+		for _, hit := range hits {
+			if _, ok := seen[hit.File]; ok || hit.File == "" {
+				continue
+			}
+			seen[hit.File] = struct{}{}
+			files = append(files, hit.File)
+		}
+	} else {
+		var err error
+		files, err = s.GetDocFileNames()
+		if err != nil {
+			return newToolErrorResult("failed listing docs: " + err.Error()), nil, nil
+		}
+	}
+
+	examples := []promqlExample{}
+	for _, file := range files {
+		content, err := s.GetDocFileContent(ctx, file)
+		if err != nil {
+			logger.Warn("skipping unreadable doc file", "file", file, "err", err)
+			continue
+		}
+
+		// Untagged code blocks are only trusted in docs that are about
+		// querying or are explicitly examples.
+		lowerFile := strings.ToLower(file)
+		includeUntagged := strings.Contains(lowerFile, "example") || strings.Contains(lowerFile, "querying/")
+
+		for _, query := range extractPromQLExamples(content, includeUntagged) {
+			examples = append(examples, promqlExample{File: file, Query: query})
+			if len(examples) >= limit {
+				break
+			}
+		}
+		if len(examples) >= limit {
+			break
+		}
+	}
+
+	if len(examples) == 0 {
+		return newToolTextResult("No PromQL examples found in documentation"), nil, nil
+	}
+
+	result, err := s.FormatOutput(examples)
+	if err != nil {
+		return newToolErrorResult(err.Error()), nil, nil
+	}
+
+	return newToolTextResult(result), nil, nil
+}
+
+// Thanos-specific handlers
+
+// ThanosStoresHandler handles the Thanos list stores tool.
+func (s *ServerContainer) ThanosStoresHandler(ctx context.Context, req *mcp.CallToolRequest, input EmptyInput) (*mcp.CallToolResult, any, error) {
+	_, rt := s.GetAPIClient(ctx)
+
+	ctx, cancel := context.WithTimeout(ctx, s.getAPITimeout(ctx))
+	defer cancel()
+
+	path := "/api/v1/stores"
+	result, err := s.doHTTPRequest(ctx, http.MethodGet, rt, path, true)
+	if err != nil {
+		return newToolErrorResult("failed getting stores from Thanos: " + err.Error()), nil, nil
+	}
+	return newToolTextResult(result), nil, nil
+}
+
+// Mimir-specific handlers
+
+// DefaultMimirTenantsPath is the default of --mimir.tenants-path and the
+// endpoint listing tenants when it is unset. Mimir and Cortex distributors
+// list the tenants that sent samples recently there.
+const DefaultMimirTenantsPath = "/distributor/all_user_stats"
+
+type listTenantsResponse struct {
+	Endpoint string   `json:"endpoint"`
+	Count    int      `json:"count"`
+	Tenants  []string `json:"tenants"`
+}
+
+// ListTenantsHandler handles the Mimir tenant listing tool.
+func (s *ServerContainer) ListTenantsHandler(ctx context.Context, req *mcp.CallToolRequest, input ListTenantsInput) (*mcp.CallToolResult, any, error) {
+	ctx, err := s.withTarget(ctx, input.Target)
+	if err != nil {
+		return newToolErrorResult(err.Error()), nil, nil
+	}
+
+	result, err := s.listTenantsAPICall(ctx)
+	if err != nil {
+		return newToolErrorResult("failed listing tenants: " + err.Error()), nil, nil
+	}
+	return newToolTextResult(result), nil, nil
+}
+
+// VictoriaMetrics-specific handlers
+
+// defaultVMCardinalityTopN is the number of entries VictoriaMetrics returns in
+// each top list of the TSDB status by default.
+const defaultVMCardinalityTopN = 10
+
+// vmTSDBStatusEntry is an entry in one of the top lists of the VictoriaMetrics
+// TSDB status. The request stats are only set for metric names, and only
+// when VictoriaMetrics tracks metric name usage.
+type vmTSDBStatusEntry struct {
+	Name                 string `json:"name"`
+	Value                uint64 `json:"value"`
+	RequestsCount        uint64 `json:"requestsCount"`
+	LastRequestTimestamp int64  `json:"lastRequestTimestamp"`
+}
+
+// vmTSDBStatus is the data of a VictoriaMetrics `/api/v1/status/tsdb`
+// response. It is a superset of the Prometheus TSDB status, so TotalSeries
+// is a pointer to tell VictoriaMetrics responses apart.
+type vmTSDBStatus struct {
+	TotalSeries                  *uint64             `json:"totalSeries"`
+	TotalLabelValuePairs         uint64              `json:"totalLabelValuePairs"`
+	SeriesCountByMetricName      []vmTSDBStatusEntry `json:"seriesCountByMetricName"`
+	SeriesCountByLabelName       []vmTSDBStatusEntry `json:"seriesCountByLabelName"`
+	SeriesCountByFocusLabelValue []vmTSDBStatusEntry `json:"seriesCountByFocusLabelValue"`
+	SeriesCountByLabelValuePair  []vmTSDBStatusEntry `json:"seriesCountByLabelValuePair"`
+	LabelValueCountByLabelName   []vmTSDBStatusEntry `json:"labelValueCountByLabelName"`
+}
+
+type vmCardinalityEntry struct {
+	Name          string     `json:"name"`
+	Count         uint64     `json:"count"`
+	RequestsCount uint64     `json:"requests_count,omitempty"`
+	LastRequest   *time.Time `json:"last_request,omitempty"`
+}
+
+type vmCardinalityResponse struct {
+	Date                         string               `json:"date,omitempty"`
+	Match                        string               `json:"match,omitempty"`
+	FocusLabel                   string               `json:"focus_label,omitempty"`
+	TotalSeries                  uint64               `json:"total_series"`
+	TotalLabelValuePairs         uint64               `json:"total_label_value_pairs"`
+	SeriesCountByMetricName      []vmCardinalityEntry `json:"series_count_by_metric_name"`
+	SeriesCountByLabelName       []vmCardinalityEntry `json:"series_count_by_label_name"`
+	SeriesCountByFocusLabelValue []vmCardinalityEntry `json:"series_count_by_focus_label_value,omitempty"`
+	SeriesCountByLabelValuePair  []vmCardinalityEntry `json:"series_count_by_label_value_pair"`
+	LabelValueCountByLabelName   []vmCardinalityEntry `json:"label_value_count_by_label_name"`
+}
+
+// VMCardinalityHandler handles the VictoriaMetrics cardinality tool.
+func (s *ServerContainer) VMCardinalityHandler(ctx context.Context, req *mcp.CallToolRequest, input VMCardinalityInput) (*mcp.CallToolResult, any, error) {
+	ctx, err := s.withTarget(ctx, input.Target)
+	if err != nil {
+		return newToolErrorResult(err.Error()), nil, nil
+	}
+
+	topN := input.TopN
+	if topN == 0 {
+		topN = defaultVMCardinalityTopN
+	}
+	if topN < 0 {
+		return newToolErrorResult("top_n must not be negative"), nil, nil
+	}
+	if input.Date != "" {
+		if _, err := time.Parse(time.DateOnly, input.Date); err != nil {
+			return newToolErrorResult("date must be in YYYY-MM-DD format"), nil, nil
+		}
+	}
+
+	result, err := s.vmCardinalityAPICall(ctx, topN, input.Date, input.Match, input.FocusLabel)
+	if err != nil {
+		return newToolErrorResult("failed getting cardinality stats from VictoriaMetrics: " + err.Error()), nil, nil
+	}
+	return newToolTextResult(result), nil, nil
+}
+
+// Prometheus API call methods on ServerContainer
+
+// formatTruncatedQueryAPIResponse applies line or byte based truncation to a result string, adds
+// a warning if truncated, wraps it in a queryAPIResponse with optional
+// warnings, and formats the output.
+func (s *ServerContainer) formatTruncatedQueryAPIResponse(resultString string, warnings promv1.Warnings, truncationLimit int) (string, error) {
+	truncatedResult, truncated := s.truncateResult(resultString, truncationLimit)
+	if truncated {
+		resultString = truncatedResult + s.resultTruncationWarning(resultString, truncationLimit)
+	}
+	return s.FormatOutput(queryAPIResponse{
+		Result:   resultString,
+		Warnings: warnings,
+	})
+}
+
+const (
+	// defaultPageSize is the page size used when only a page is requested.
+	defaultPageSize = 500
+	// maxPageSize bounds the page size, so pagination can't be used to get
+	// around truncation of huge results.
+	maxPageSize = 10000
+)
+
+// paginatedQueryAPIResponse is the response structure for a page of a list
+// API result.
+type paginatedQueryAPIResponse struct {
+	Result       string          `json:"result"`
+	Warnings     promv1.Warnings `json:"warnings"`
+	Page         int             `json:"page"`
+	PageSize     int             `json:"page_size"`
+	TotalPages   int             `json:"total_pages"`
+	TotalEntries int             `json:"total_entries"`
+	NextPage     int             `json:"next_page,omitempty"`
+}
+
+// paginated reports whether pagination was requested.
+func (pi PaginationInput) paginated() bool {
+	return pi.Page != 0 || pi.PageSize != 0
+}
+
+// pageBounds validates the pagination input and returns the page and page
+// size to use, filling in defaults.
+func (pi PaginationInput) pageBounds() (int, int, error) {
+	page, pageSize := pi.Page, pi.PageSize
+	if page < 0 {
+		return 0, 0, errors.New("page must not be negative")
+	}
+	if pageSize < 0 {
+		return 0, 0, errors.New("page_size must not be negative")
+	}
+	if pageSize > maxPageSize {
+		return 0, 0, fmt.Errorf("page_size must be at most %d", maxPageSize)
+	}
+	if page == 0 {
+		page = 1
+	}
+	if pageSize == 0 {
+		pageSize = defaultPageSize
+	}
+	return page, pageSize, nil
+}
+
+// formatPaginatedQueryAPIResponse formats the given page of entries, and
+// returns the number of the next page, or 0 if it is the last page.
+func (s *ServerContainer) formatPaginatedQueryAPIResponse(entries []string, warnings promv1.Warnings, page, pageSize int) (string, int, error) {
+	totalPages := (len(entries) + pageSize - 1) / pageSize
+	if page > max(totalPages, 1) {
+		return "", 0, fmt.Errorf("page %d is out of range, there are %d pages of %d entries", page, totalPages, pageSize)
+	}
+
+	start := (page - 1) * pageSize
+	end := min(start+pageSize, len(entries))
+	nextPage := 0
+	if end < len(entries) {
+		nextPage = page + 1
+	}
+
+	result, err := s.FormatOutput(paginatedQueryAPIResponse{
+		Result:       strings.Join(entries[start:end], "\n"),
+		Warnings:     warnings,
+		Page:         page,
+		PageSize:     pageSize,
+		TotalPages:   totalPages,
+		TotalEntries: len(entries),
+		NextPage:     nextPage,
+	})
+	return result, nextPage, err
+}
+
+// newPaginatedToolResult returns a page of results, followed by a separate
+// content block telling the LLM how to get the next page, if there is one.
+func newPaginatedToolResult(result string, nextPage int) *mcp.CallToolResult {
+	toolResult := newToolTextResult(result)
+	if nextPage > 0 {
+		toolResult.Content = append(toolResult.Content, &mcp.TextContent{
+			Text: fmt.Sprintf("More results are available, call the tool again with the same arguments and page=%d to get the next page.", nextPage),
+		})
+	}
+	return toolResult
+}
+
+// resultSort describes how to sort the series of a query result. The zero
+// value keeps the backend's order.
+type resultSort struct {
+	byValue bool
+	label   model.LabelName
+	desc    bool
+}
+
+// parseResultSort validates the sort arguments of the query tools.
+func parseResultSort(input SortInput) (resultSort, error) {
+	var rs resultSort
+	switch {
+	case input.SortBy == "":
+		if input.SortOrder != "" {
+			return rs, errors.New("sort_order requires sort_by to be set")
+		}
+		return rs, nil
+	case input.SortBy == "value":
+		rs.byValue, rs.desc = true, true
+	case strings.HasPrefix(input.SortBy, "label:") && len(input.SortBy) > len("label:"):
+		rs.label = model.LabelName(strings.TrimPrefix(input.SortBy, "label:"))
+	default:
+		return rs, errors.New("sort_by must be 'value' or 'label:<name>'")
+	}
+
+	switch strings.ToLower(input.SortOrder) {
+	case "":
+	case "asc":
+		rs.desc = false
+	case "desc":
+		rs.desc = true
+	default:
+		return rs, errors.New("sort_order must be one of 'asc' or 'desc'")
+	}
+	return rs, nil
+}
+
+// sortQueryResult sorts the series of a query result in place. Range query
+// series are sorted by their latest sample. Series without a sortable value,
+// i.e. NaN samples or a missing label, always sort last. Ties keep the
+// backend's order. Without a requested sort, range query series are sorted by
+// their labels, like Matrix.String does. Other result types are left as is.
+func sortQueryResult(result model.Value, rs resultSort) {
+	sorted := rs.byValue || rs.label != ""
+
+	switch v := result.(type) {
+	case model.Vector:
+		if !sorted {
+			return
+		}
+		keys := make([]resultSortKey, len(v))
+		for i, sample := range v {
+			keys[i] = newResultSortKey(rs, sample.Metric, sampleSortValue(sample.Value, sample.Histogram))
+		}
+		sort.Stable(resultSorter{keys: keys, desc: rs.desc, swap: func(i, j int) { v[i], v[j] = v[j], v[i] }})
+	case model.Matrix:
+		if !sorted {
+			sort.Sort(v)
+			return
+		}
+		keys := make([]resultSortKey, len(v))
+		for i, stream := range v {
+			value := math.NaN()
+			if n := len(stream.Values); n > 0 {
+				value = float64(stream.Values[n-1].Value)
+			} else if n := len(stream.Histograms); n > 0 {
+				value = sampleSortValue(0, stream.Histograms[n-1].Histogram)
+			}
+			keys[i] = newResultSortKey(rs, stream.Metric, value)
+		}
+		sort.Stable(resultSorter{keys: keys, desc: rs.desc, swap: func(i, j int) { v[i], v[j] = v[j], v[i] }})
+	}
+}
+
+// Orders of the sort argument of list tools.
+const (
+	listSortAsc  = "asc"
+	listSortDesc = "desc"
+	listSortNone = "none"
+)
+
+// parseListSort validates the sort argument of list tools, defaulting to
+// ascending order.
+func parseListSort(order string) (string, error) {
+	switch order = strings.ToLower(order); order {
+	case "":
+		return listSortAsc, nil
+	case listSortAsc, listSortDesc, listSortNone:
+		return order, nil
+	default:
+		return "", errors.New("sort must be one of 'asc', 'desc', or 'none'")
+	}
+}
+
+// sortList sorts the values of a list result in place in the given order.
+func sortList(values []string, order string) {
+	switch order {
+	case listSortAsc:
+		slices.Sort(values)
+	case listSortDesc:
+		slices.SortFunc(values, func(a, b string) int { return strings.Compare(b, a) })
+	}
+}
+
+// queryResultEntries renders a query result as one entry per series, in
+// result order. Joined by newlines, the entries match the result's String
+// output, except for native histogram samples which are rendered with
+// formatSampleHistogram. Other result types are rendered as a single entry.
+func queryResultEntries(result model.Value) []string {
+	switch v := result.(type) {
+	case model.Vector:
+		entries := make([]string, len(v))
+		for i, sample := range v {
+			if sample.Histogram != nil {
+				entries[i] = fmt.Sprintf("%s => %s", sample.Metric, histogramPairString(sample.Timestamp, sample.Histogram))
+				continue
+			}
+			entries[i] = sample.String()
+		}
+		return entries
+	case model.Matrix:
+		// Matrix.String sorts the series by their labels, so render the
+		// series one by one to keep the result order.
+		entries := make([]string, len(v))
+		for i, stream := range v {
+			if len(stream.Histograms) == 0 {
+				entries[i] = stream.String()
+				continue
+			}
+			values := make([]string, 0, len(stream.Values)+len(stream.Histograms))
+			for _, pair := range stream.Values {
+				values = append(values, pair.String())
+			}
+			for _, pair := range stream.Histograms {
+				values = append(values, histogramPairString(pair.Timestamp, pair.Histogram))
+			}
+			entries[i] = fmt.Sprintf("%s =>\n%s", stream.Metric, strings.Join(values, "\n"))
+		}
+		return entries
+	default:
+		return []string{result.String()}
+	}
+}
+
+// formatSampleHistogram renders a native histogram sample with its count,
+// sum, and populated buckets, e.g.
+// `{count: 10, sum: 33.5, buckets: [(0.5,1]:2, (1,2]:8]}`. The bucket
+// boundaries use interval notation, with `[`/`]` for inclusive and `(`/`)`
+// for exclusive bounds. model.SampleHistogram.String renders the count and sum
+// with a fixed six decimals instead.
+func formatSampleHistogram(h *model.SampleHistogram) string {
+	buckets := make([]string, len(h.Buckets))
+	for i, bucket := range h.Buckets {
+		buckets[i] = bucket.String()
+	}
+	return fmt.Sprintf("{count: %s, sum: %s, buckets: [%s]}", h.Count, h.Sum, strings.Join(buckets, ", "))
+}
+
+// histogramPairString renders a native histogram sample like
+// model.SampleHistogramPair.String, using formatSampleHistogram.
+func histogramPairString(ts model.Time, h *model.SampleHistogram) string {
+	return fmt.Sprintf("%s @[%s]", formatSampleHistogram(h), ts)
+}
+
+// countShownEntries returns how many of the entries, joined by newlines, are
+// shown in full within the first n bytes.
+func countShownEntries(entries []string, n int) int {
+	end := 0
+	for i, entry := range entries {
+		if i > 0 {
+			end++
+		}
+		end += len(entry)
+		if end > n {
+			return i
+		}
+	}
+	return len(entries)
+}
+
+// queryResultCSVEntries renders the series of a query result as CSV, with a
+// column per label name followed by timestamp and value columns. It returns
+// the header row and one entry per series with a row per sample.
+func queryResultCSVEntries(series []QuerySeries) (string, []string, error) {
+	var labelNames []string
+	for _, s := range series {
+		for name := range s.Metric {
+			if !slices.Contains(labelNames, name) {
+				labelNames = append(labelNames, name)
+			}
+		}
+	}
+	sort.Strings(labelNames)
+
+	var buf strings.Builder
+	w := csv.NewWriter(&buf)
+	// render writes the records and returns them without the trailing newline.
+	render := func(records [][]string) (string, error) {
+		buf.Reset()
+		if err := w.WriteAll(records); err != nil {
+			return "", fmt.Errorf("failed to write CSV: %w", err)
+		}
+		return strings.TrimSuffix(buf.String(), "\n"), nil
+	}
+
+	header, err := render([][]string{append(slices.Clone(labelNames), "timestamp", "value")})
+	if err != nil {
+		return "", nil, err
+	}
+
+	entries := make([]string, len(series))
+	for i, s := range series {
+		records := make([][]string, len(s.Values))
+		for j, sample := range s.Values {
+			record := make([]string, 0, len(labelNames)+2)
+			for _, name := range labelNames {
+				record = append(record, s.Metric[name])
+			}
+			value := sample.Value
+			if sample.Histogram != "" {
+				value = sample.Histogram
+			}
+			records[j] = append(record, strconv.FormatFloat(sample.Timestamp, 'f', -1, 64), value)
+		}
+		entries[i], err = render(records)
+		if err != nil {
+			return "", nil, err
+		}
+	}
+	return header, entries, nil
+}
+
+// newQueryResultOutput converts a query result into the structured output of
+// the query tools.
+func newQueryResultOutput(result model.Value, warnings promv1.Warnings) *QueryResultOutput {
+	output := &QueryResultOutput{
+		ResultType: result.Type().String(),
+		Warnings:   warnings,
+	}
+
+	switch v := result.(type) {
+	case model.Vector:
+		output.Series = make([]QuerySeries, len(v))
+		for i, sample := range v {
+			output.Series[i] = QuerySeries{
+				Metric: queryOutputMetric(sample.Metric),
+				Value:  ptr(newQuerySample(sample.Timestamp, sample.Value, sample.Histogram)),
+			}
+		}
+	case model.Matrix:
+		output.Series = make([]QuerySeries, len(v))
+		for i, stream := range v {
+			values := make([]QuerySample, 0, len(stream.Values)+len(stream.Histograms))
+			for _, pair := range stream.Values {
+				values = append(values, newQuerySample(pair.Timestamp, pair.Value, nil))
+			}
+			for _, pair := range stream.Histograms {
+				values = append(values, newQuerySample(pair.Timestamp, 0, pair.Histogram))
+			}
+			// Series mixing float and histogram samples need the two merged
+			// back into timestamp order.
+			sort.SliceStable(values, func(i, j int) bool { return values[i].Timestamp < values[j].Timestamp })
+			output.Series[i] = QuerySeries{
+				Metric: queryOutputMetric(stream.Metric),
+				Values: values,
+			}
+		}
+	case *model.Scalar:
+		output.Sample = ptr(newQuerySample(v.Timestamp, v.Value, nil))
+	case *model.String:
+		output.Sample = &QuerySample{Timestamp: float64(v.Timestamp) / 1000, Value: v.Value}
+	}
+
+	return output
+}
+
+// newQuerySample converts a float or native histogram sample into the
+// structured output of the query tools.
+func newQuerySample(ts model.Time, value model.SampleValue, histogram *model.SampleHistogram) QuerySample {
+	sample := QuerySample{Timestamp: float64(ts) / 1000}
+	if histogram != nil {
+		sample.Histogram = formatSampleHistogram(histogram)
+	} else {
+		sample.Value = value.String()
+	}
+	return sample
+}
+
+// queryOutputMetric returns the labels of a series as a non-nil map, so
+// series without labels have an empty object as metric in the output.
+func queryOutputMetric(metric model.Metric) map[string]string {
+	labels := make(map[string]string, len(metric))
+	for name, value := range metric {
+		labels[string(name)] = string(value)
+	}
+	return labels
+}
+
+// Text formats of query results accepted by the range query tool.
+const (
+	queryResultFormatDefault = "default"
+	queryResultFormatCSV     = "csv"
+)
+
+// formatQueryResult formats a sorted query result for the text output of the
+// query tools, truncated to the limit, and its structured output with the
+// series shown in full in the text output. Matrix results can be formatted
+// as CSV instead of the Prometheus string format. Advisory notes about the
+// query are included in both outputs.
+func (s *ServerContainer) formatQueryResult(result model.Value, warnings promv1.Warnings, notes []string, queryRange *QueryRange, sortOpts resultSort, format string, truncationLimit int) (string, *QueryResultOutput, error) {
+	sortQueryResult(result, sortOpts)
+	output := newQueryResultOutput(result, warnings)
+	output.Notes = notes
+	output.Range = queryRange
+
+	var (
+		header  string
+		entries []string
+	)
+	if _, ok := result.(model.Matrix); ok && format == queryResultFormatCSV {
+		var err error
+		header, entries, err = queryResultCSVEntries(output.Series)
+		if err != nil {
+			return "", nil, err
+		}
+	} else {
+		entries = queryResultEntries(result)
+		if s.displayLocation != nil {
+			for i, entry := range entries {
+				entries[i] = appendDisplayTimes(entry, s.displayLocation)
+			}
+		}
+	}
+	resultString := strings.Join(entries, "\n")
+	headerLen := 0
+	if header != "" {
+		resultString = header + "\n" + resultString
+		headerLen = len(header) + 1
+	}
+
+	truncatedResult, truncated := s.truncateResult(resultString, truncationLimit)
+	if truncated {
+		if output.Series != nil {
+			output.Series = output.Series[:countShownEntries(entries, len(truncatedResult)-headerLen)]
+		}
+		output.Truncated = true
+		resultString = truncatedResult + s.resultTruncationWarning(resultString, truncationLimit)
+	}
+
+	text, err := s.FormatOutput(queryAPIResponse{
+		Result:   resultString,
+		Warnings: warnings,
+		Notes:    notes,
+		Range:    queryRange,
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	return text, output, nil
+}
+
+// sampleTimestampRe matches the `@[<unix seconds>]` timestamp that ends each
+// sample line in the String output of query results.
+var sampleTimestampRe = regexp.MustCompile(`(?m)@\[(-?[0-9]+(?:\.[0-9]+)?)\]$`)
+
+// appendDisplayTimes appends the time of each sample timestamp in a rendered
+// query result, formatted in the given location, e.g.
+// `1 @[1700000000] (2023-11-14T23:13:20+01:00)`.
+func appendDisplayTimes(rendered string, loc *time.Location) string {
+	return sampleTimestampRe.ReplaceAllStringFunc(rendered, func(match string) string {
+		secs, err := strconv.ParseFloat(match[2:len(match)-1], 64)
+		if err != nil {
+			return match
+		}
+		t := time.UnixMilli(int64(math.Round(secs * 1e3))).In(loc)
+		return match + " (" + t.Format("2006-01-02T15:04:05.999Z07:00") + ")"
+	})
+}
+
+// displayTimezone returns the name of the time zone query results are
+// displayed in, or an empty string if it isn't set.
+func (s *ServerContainer) displayTimezone() string {
+	if s.displayLocation == nil {
+		return ""
+	}
+	return s.displayLocation.String()
+}
+
+// resultSortKey is the value a series is sorted by.
+type resultSortKey struct {
+	missing bool
+	value   float64
+	label   string
+}
+
+func newResultSortKey(rs resultSort, metric model.Metric, value float64) resultSortKey {
+	if rs.byValue {
+		return resultSortKey{value: value, missing: math.IsNaN(value)}
+	}
+	label, ok := metric[rs.label]
+	return resultSortKey{label: string(label), missing: !ok}
+}
+
+// sampleSortValue returns the value of a vector sample to sort by, using
+// the observation count for native histograms.
+func sampleSortValue(value model.SampleValue, histogram *model.SampleHistogram) float64 {
+	if histogram != nil {
+		return float64(histogram.Count)
+	}
+	return float64(value)
+}
+
+// resultSorter sorts the keys along with the result series they belong to.
+type resultSorter struct {
+	keys []resultSortKey
+	desc bool
+	swap func(i, j int)
+}
+
+func (rs resultSorter) Len() int { return len(rs.keys) }
+
+func (rs resultSorter) Less(i, j int) bool {
+	a, b := rs.keys[i], rs.keys[j]
+	if a.missing || b.missing {
+		return !a.missing && b.missing
+	}
+	if a.value != b.value {
+		return (a.value < b.value) != rs.desc
+	}
+	if a.label != b.label {
+		return (a.label < b.label) != rs.desc
+	}
+	return false
+}
+
+func (rs resultSorter) Swap(i, j int) {
+	rs.keys[i], rs.keys[j] = rs.keys[j], rs.keys[i]
+	rs.swap(i, j)
+}
+
+// seriesLimitOptions returns the API options needed to request a server-side
+// series limit. A limit of 0 means unlimited and produces no options.
+func seriesLimitOptions(seriesLimit uint64) []promv1.Option {
+	if seriesLimit == 0 {
+		return nil
+	}
+	return []promv1.Option{promv1.WithLimit(seriesLimit)}
+}
+
+// parseTimeoutArgument parses the optional timeout argument of a tool call,
+// returning 0 if it is empty.
+func parseTimeoutArgument(timeout string) (time.Duration, error) {
+	if timeout == "" {
+		return 0, nil
+	}
+	parsed, err := model.ParseDuration(timeout)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse timeout: %w", err)
+	}
+	if parsed <= 0 {
+		return 0, errors.New("timeout must be a positive duration (e.g. '10s', '1m')")
+	}
+	return time.Duration(parsed), nil
+}
+
+// queryTimeoutOptions returns the API options needed to set a query
+// evaluation timeout. A timeout of 0 uses the backend's default and produces
+// no options.
+func queryTimeoutOptions(timeout time.Duration) []promv1.Option {
+	if timeout == 0 {
+		return nil
+	}
+	return []promv1.Option{promv1.WithTimeout(timeout)}
+}
+
+// enforceSeriesLimit checks whether the backend honored the requested series
+// limit. Backends that predate the `limit` parameter silently ignore it, so if
+// more series than requested come back, the result is cut down to the limit
+// on the MCP side and a warning is added to explain why.
+func (s *ServerContainer) enforceSeriesLimit(result model.Value, warnings promv1.Warnings, seriesLimit uint64) (model.Value, promv1.Warnings) {
+	if seriesLimit == 0 {
+		return result, warnings
+	}
+
+	limited := false
+	switch v := result.(type) {
+	case model.Vector:
+		if uint64(len(v)) > seriesLimit {
+			result, limited = v[:seriesLimit], true
+		}
+	case model.Matrix:
+		if uint64(len(v)) > seriesLimit {
+			result, limited = v[:seriesLimit], true
+		}
+	}
+
+	if limited {
+		s.logger.Warn("Prometheus backend ignored the series limit, applying it on the MCP server instead", "series_limit", seriesLimit)
+		warnings = append(warnings, fmt.Sprintf(seriesLimitUnsupportedWarningTemplate, seriesLimit))
+	}
+
+	return result, warnings
+}
+
+const seriesLimitUnsupportedWarningTemplate = "The Prometheus backend does not appear to support the 'limit' query parameter (requires Prometheus v3.x+)," +
+	" so the result was limited to %d series by the MCP server after the full result was transferred."
+
+func (s *ServerContainer) queryAPICall(ctx context.Context, query string, ts time.Time, timeout time.Duration, seriesLimit uint64, sortOpts resultSort, truncationLimit int) (string, *QueryResultOutput, error) {
+	opts := append(seriesLimitOptions(seriesLimit), queryTimeoutOptions(timeout)...)
+	var warnings promv1.Warnings
+	v, err := s.doAPICall(ctx, "/api/v1/query", "failed to execute instant query",
+		func(ctx context.Context, client promv1.API) (any, error) {
+			v, w, err := client.Query(ctx, query, ts, opts...)
+			warnings = w
+			return v, err
+		})
+	if err != nil {
+		return "", nil, err
+	}
+	result, ok := v.(model.Value)
+	if !ok {
+		return "", nil, fmt.Errorf("unexpected query result type %T", v)
+	}
+
+	result, warnings = s.enforceSeriesLimit(result, warnings, seriesLimit)
+	return s.formatQueryResult(result, warnings, queryModifierNotes(query, false), nil, sortOpts, queryResultFormatDefault, truncationLimit)
+}
+
+func (s *ServerContainer) rangeQueryAPICall(ctx context.Context, query string, start, end time.Time, step, timeout time.Duration, seriesLimit uint64, sortOpts resultSort, format string, truncationLimit int) (string, *QueryResultOutput, error) {
+	opts := append(seriesLimitOptions(seriesLimit), queryTimeoutOptions(timeout)...)
+	var warnings promv1.Warnings
+	v, err := s.doAPICall(ctx, "/api/v1/query_range", "failed to execute range query",
+		func(ctx context.Context, client promv1.API) (any, error) {
+			v, w, err := client.QueryRange(ctx, query, promv1.Range{Start: start, End: end, Step: step}, opts...)
+			warnings = w
+			return v, err
+		})
+	if err != nil {
+		return "", nil, err
+	}
+	result, ok := v.(model.Value)
+	if !ok {
+		return "", nil, fmt.Errorf("unexpected range query result type %T", v)
+	}
+
+	result, warnings = s.enforceSeriesLimit(result, warnings, seriesLimit)
+	return s.formatQueryResult(result, warnings, queryModifierNotes(query, true), newQueryRange(start, end, step), sortOpts, format, truncationLimit)
+}
+
+// newQueryRange computes the evaluation steps Prometheus uses for a range
+// query. Timestamps and the step are handled at millisecond precision like in
+// Prometheus, and the start time is used as is, so only the end time is
+// aligned to the step.
+func newQueryRange(start, end time.Time, step time.Duration) *QueryRange {
+	startMs, endMs, stepMs := start.UnixMilli(), end.UnixMilli(), step.Milliseconds()
+	if stepMs <= 0 || endMs < startMs {
+		return nil
+	}
+	steps := (endMs-startMs)/stepMs + 1
+	return &QueryRange{
+		Start:       float64(startMs) / 1e3,
+		End:         float64(startMs+(steps-1)*stepMs) / 1e3,
+		Step:        model.Duration(time.Duration(stepMs) * time.Millisecond).String(),
+		StepSeconds: float64(stepMs) / 1e3,
+		Steps:       steps,
+	}
+}
+
+// Formats of the exemplar query tool's result.
+const (
+	exemplarFormatDefault = "default"
+	exemplarFormatJSONL   = "jsonl"
+)
+
+// exemplarJSONLTruncation is the last line of a truncated exemplar result in
+// the jsonl format, so the result stays valid JSON Lines.
+type exemplarJSONLTruncation struct {
+	Truncated bool   `json:"truncated"`
+	Warning   string `json:"warning"`
+}
+
+func (s *ServerContainer) exemplarQueryAPICall(ctx context.Context, query string, start, end time.Time, format string, truncationLimit int) (string, error) {
+	res, err := s.fetchExemplars(ctx, query, start, end)
+	if err != nil {
+		return "", err
+	}
+
+	var resultSB strings.Builder
+	for _, r := range res {
+		b, err := json.Marshal(r)
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal exemplar: %w", err)
+		}
+		resultSB.Write(b)
+		resultSB.WriteString("\n")
+	}
+	if format == exemplarFormatJSONL {
+		return s.truncateJSONL(resultSB.String(), truncationLimit)
+	}
+	return s.formatTruncatedQueryAPIResponse(resultSB.String(), nil, truncationLimit)
+}
+
+// truncateJSONL truncates a JSON Lines result to the limit. Only whole lines
+// are kept, a line cut by the byte or token truncation modes is dropped, and
+// the truncation warning is appended as a JSON line of its own.
+func (s *ServerContainer) truncateJSONL(result string, truncationLimit int) (string, error) {
+	truncated, ok := s.truncateResult(result, truncationLimit)
+	if !ok {
+		return result, nil
+	}
+
+	lines := strings.Split(result, "\n")
+	kept := strings.Split(truncated, "\n")
+	n := len(kept)
+	if kept[n-1] != lines[n-1] {
+		n--
+	}
+
+	var sb strings.Builder
+	for _, line := range lines[:n] {
+		if line == "" {
+			continue
+		}
+		sb.WriteString(line)
+		sb.WriteString("\n")
+	}
+	b, err := json.Marshal(exemplarJSONLTruncation{
+		Truncated: true,
+		Warning:   strings.TrimSpace(s.resultTruncationWarning(result, truncationLimit)),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal truncation warning: %w", err)
+	}
+	sb.Write(b)
+	sb.WriteString("\n")
+	return sb.String(), nil
+}
+
+// fetchExemplars calls the exemplars API, recording API call telemetry.
+func (s *ServerContainer) fetchExemplars(ctx context.Context, query string, start, end time.Time) ([]promv1.ExemplarQueryResult, error) {
+	v, err := s.doAPICall(ctx, "/api/v1/query_exemplars", "failed to execute exemplar query",
+		func(ctx context.Context, client promv1.API) (any, error) {
+			return client.QueryExemplars(ctx, query, start, end)
+		})
+	if err != nil {
+		return nil, err
+	}
+	res, ok := v.([]promv1.ExemplarQueryResult)
+	if !ok {
+		return nil, fmt.Errorf("unexpected exemplar query result type %T", v)
+	}
+
+	return res, nil
+}
+
+func (s *ServerContainer) exemplarCoverageAPICall(ctx context.Context, selector string, start, end time.Time, traceIDSamples, truncationLimit int) (string, error) {
+	res, err := s.fetchExemplars(ctx, selector, start, end)
+	if err != nil {
+		return "", err
+	}
+
+	resp := exemplarCoverageResponse{
+		Selector:       selector,
+		StartTime:      start.UTC().Format(time.RFC3339),
+		EndTime:        end.UTC().Format(time.RFC3339),
+		SampleTraceIDs: []string{},
+		Series:         []exemplarCoverageSeries{},
+	}
+
+	seenTraceIDs := make(map[string]struct{})
+	for _, r := range res {
+		if len(r.Exemplars) == 0 {
+			continue
+		}
+
+		resp.SeriesWithExemplars++
+		resp.TotalExemplars += len(r.Exemplars)
+		resp.Series = append(resp.Series, exemplarCoverageSeries{
+			Series:    r.SeriesLabels.String(),
+			Exemplars: len(r.Exemplars),
+		})
+
+		for _, e := range r.Exemplars {
+			if len(resp.SampleTraceIDs) >= traceIDSamples {
+				break
+			}
+			traceID := exemplarTraceID(e.Labels)
+			if traceID == "" {
+				continue
+			}
+			if _, ok := seenTraceIDs[traceID]; ok {
+				continue
+			}
+			seenTraceIDs[traceID] = struct{}{}
+			resp.SampleTraceIDs = append(resp.SampleTraceIDs, traceID)
+		}
+	}
+
+	switch {
+	case resp.SeriesWithExemplars == 0:
+		resp.Message = "No exemplars found for this selector in the time window. Trace correlation is not possible unless exemplar storage is enabled on the server and the instrumentation attaches exemplars to these series."
+	case len(resp.SampleTraceIDs) == 0:
+		resp.Message = fmt.Sprintf("Exemplars were found, but none carry a trace ID label (%s).", strings.Join(exemplarTraceIDLabels, ", "))
+	}
+
+	sort.SliceStable(resp.Series, func(i, j int) bool {
+		return resp.Series[i].Exemplars > resp.Series[j].Exemplars
+	})
+	if truncationLimit > 0 && len(resp.Series) > truncationLimit {
+		resp.Series = resp.Series[:truncationLimit]
+		resp.Truncated = strings.TrimSpace(displayTruncationWarning(truncationLimit))
+	}
+
+	return s.FormatOutput(resp)
+}
+
+// exemplarTraceID returns the trace ID carried by an exemplar's labels, or an
+// empty string if it has none.
+func exemplarTraceID(ls model.LabelSet) string {
+	for _, name := range exemplarTraceIDLabels {
+		if v, ok := ls[model.LabelName(name)]; ok && v != "" {
+			return string(v)
+		}
+	}
+	return ""
+}
+
+func (s *ServerContainer) seriesAPICall(ctx context.Context, matches []string, start, end time.Time, order string, truncationLimit int) (string, error) {
+	lsets, warnings, err := s.fetchSeries(ctx, matches, start, end)
+	if err != nil {
+		return "", err
+	}
+	sortList(lsets, order)
+	if len(matches) == 0 {
+		warnings = append(warnings, emptyMatchersWarning)
+	}
+
+	return s.formatTruncatedQueryAPIResponse(strings.Join(lsets, "\n"), warnings, truncationLimit)
+}
+
+// seriesCountAPICall returns the number of series matching the matchers,
+// without their label sets.
+func (s *ServerContainer) seriesCountAPICall(ctx context.Context, matches []string, start, end time.Time) (string, error) {
+	lsets, warnings, err := s.fetchSeries(ctx, matches, start, end)
+	if err != nil {
+		return "", err
+	}
+	if len(matches) == 0 {
+		warnings = append(warnings, emptyMatchersWarning)
+	}
+
+	return s.FormatOutput(seriesCountResponse{
+		Count:    len(lsets),
+		Warnings: warnings,
+	})
+}
+
+// labelsCardinalityAPICall counts the distinct values of each label of the
+// series matching the selector, and returns the topN labels with the most
+// values.
+func (s *ServerContainer) labelsCardinalityAPICall(ctx context.Context, selector string, topN int, start, end time.Time) (string, error) {
+	lsets, warnings, err := s.fetchSeriesLabelSets(ctx, []string{selector}, start, end)
+	if err != nil {
+		return "", err
+	}
+
+	values := make(map[model.LabelName]map[model.LabelValue]struct{})
+	series := make(map[model.LabelName]int)
+	for _, lset := range lsets {
+		for name, value := range lset {
+			if values[name] == nil {
+				values[name] = make(map[model.LabelValue]struct{})
+			}
+			values[name][value] = struct{}{}
+			series[name]++
+		}
+	}
+
+	labels := make([]labelCardinality, 0, len(values))
+	for name, vals := range values {
+		labels = append(labels, labelCardinality{
+			Label:  string(name),
+			Values: len(vals),
+			Series: series[name],
+		})
+	}
+	sort.Slice(labels, func(i, j int) bool {
+		if labels[i].Values != labels[j].Values {
+			return labels[i].Values > labels[j].Values
+		}
+		return labels[i].Label < labels[j].Label
+	})
+
+	return s.FormatOutput(labelsCardinalityResponse{
+		Selector:    selector,
+		SeriesCount: len(lsets),
+		TotalLabels: len(labels),
+		Labels:      labels[:min(len(labels), topN)],
+		Warnings:    warnings,
+	})
+}
+
+// seriesPageAPICall returns one page of the series matching the matchers,
+// and the number of the next page, or 0 if this is the last page.
+func (s *ServerContainer) seriesPageAPICall(ctx context.Context, matches []string, start, end time.Time, order string, page, pageSize int) (string, int, error) {
+	lsets, warnings, err := s.fetchSeries(ctx, matches, start, end)
+	if err != nil {
+		return "", 0, err
+	}
+	sortList(lsets, order)
+	if len(matches) == 0 {
+		warnings = append(warnings, emptyMatchersWarning)
+	}
+
+	return s.formatPaginatedQueryAPIResponse(lsets, warnings, page, pageSize)
+}
+
+// fetchSeries calls the series API and returns the label sets as strings,
+// recording API call telemetry.
+func (s *ServerContainer) fetchSeries(ctx context.Context, matches []string, start, end time.Time) ([]string, promv1.Warnings, error) {
+	result, warnings, err := s.fetchSeriesLabelSets(ctx, matches, start, end)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	lsets := make([]string, len(result))
+	for i, lset := range result {
+		lsets[i] = lset.String()
+	}
+
+	return lsets, warnings, nil
+}
+
+// fetchSeriesLabelSets calls the series API and returns the label sets,
+// recording API call telemetry.
+func (s *ServerContainer) fetchSeriesLabelSets(ctx context.Context, matches []string, start, end time.Time) ([]model.LabelSet, promv1.Warnings, error) {
+	var warnings promv1.Warnings
+	v, err := s.doAPICall(ctx, "/api/v1/series", "failed to get series",
+		func(ctx context.Context, client promv1.API) (any, error) {
+			v, w, err := client.Series(ctx, matches, start, end)
+			warnings = w
+			return v, err
+		})
+	if err != nil {
+		return nil, nil, err
+	}
+	result, ok := v.([]model.LabelSet)
+	if !ok {
+		return nil, nil, fmt.Errorf("unexpected series result type %T", v)
+	}
+
+	return result, warnings, nil
+}
+
+func (s *ServerContainer) seriesByLabelRegexAPICall(ctx context.Context, selector string, start, end time.Time, truncationLimit int) (string, error) {
+	lsets, warnings, err := s.fetchSeries(ctx, []string{selector}, start, end)
+	if err != nil {
+		return "", err
+	}
+
+	resultString := strings.Join(lsets, "\n")
+	truncatedResult, truncated := s.truncateResult(resultString, truncationLimit)
+	if truncated {
+		resultString = truncatedResult + s.resultTruncationWarning(resultString, truncationLimit)
+	}
+
+	return s.FormatOutput(seriesByLabelRegexResponse{
+		Selector: selector,
+		Result:   resultString,
+		Warnings: warnings,
+	})
+}
+
+func (s *ServerContainer) findMetricsAPICall(ctx context.Context, re *regexp.Regexp, limit int) (string, error) {
+	names, warnings, err := s.fetchLabelValues(ctx, model.MetricNameLabel, nil, time.Time{}, time.Time{})
+	if err != nil {
+		return "", err
+	}
+
+	matches := []string{}
+	for _, name := range names {
+		if re.MatchString(name) {
+			matches = append(matches, name)
+		}
+	}
+	slices.Sort(matches)
+
+	resp := findMetricsResponse{
+		Pattern:      re.String(),
+		Metrics:      matches,
+		TotalMatches: len(matches),
+		Warnings:     warnings,
+	}
+	switch {
+	case len(matches) == 0:
+		resp.Message = "No metric names match the pattern."
+	case len(matches) > limit:
+		resp.Metrics = matches[:limit]
+		resp.Message = fmt.Sprintf("Showing the first %d of %d matching metric names. Refine the pattern or raise the limit to see more.", limit, len(matches))
+	}
+
+	return s.FormatOutput(resp)
+}
+
+// labelNamesAPICall returns the label names for the matchers and the time
+// range parsed from timeRange. The unparsed time range is part of the cache
+// key, as relative and default times resolve to a new time on every call.
+func (s *ServerContainer) labelNamesAPICall(ctx context.Context, matches []string, timeRange TimeRangeInput, start, end time.Time, truncationLimit int) (string, error) {
+	args := []any{slices.Sorted(slices.Values(matches)), timeRange.StartTime, timeRange.EndTime, truncationLimit}
+	return s.cachedAPICall(ctx, "label_names", args, func() (string, error) {
+		var warnings promv1.Warnings
+		v, err := s.doAPICall(ctx, "/api/v1/labels", "failed to get label names",
+			func(ctx context.Context, client promv1.API) (any, error) {
+				v, w, err := client.LabelNames(ctx, matches, start, end)
+				warnings = w
+				return v, err
+			})
+		if err != nil {
+			return "", err
+		}
+		result, ok := v.(model.LabelNames)
+		if !ok {
+			return "", fmt.Errorf("unexpected label names result type %T", v)
+		}
+
+		lnames := make([]string, len(result))
+		for i, lname := range result {
+			lnames[i] = string(lname)
+		}
+
+		return s.formatTruncatedQueryAPIResponse(strings.Join(lnames, "\n"), warnings, truncationLimit)
+	})
+}
+
+// fetchLabelValues calls the label values API and returns the values as
+// strings, recording API call telemetry.
+func (s *ServerContainer) fetchLabelValues(ctx context.Context, label string, matches []string, start, end time.Time) ([]string, promv1.Warnings, error) {
+	var warnings promv1.Warnings
+	v, err := s.doAPICall(ctx, "/api/v1/label/:name/values", "failed to get label values",
+		func(ctx context.Context, client promv1.API) (any, error) {
+			v, w, err := client.LabelValues(ctx, label, matches, start, end)
+			warnings = w
+			return v, err
+		})
+	if err != nil {
+		return nil, nil, err
+	}
+	result, ok := v.(model.LabelValues)
+	if !ok {
+		return nil, nil, fmt.Errorf("unexpected label values result type %T", v)
+	}
+
+	lvals := make([]string, len(result))
+	for i, lval := range result {
+		lvals[i] = string(lval)
+	}
+
+	return lvals, warnings, nil
+}
+
+// labelValuesAPICall returns the values of the label for the matchers and the
+// time range parsed from timeRange, which is cached like in labelNamesAPICall.
+func (s *ServerContainer) labelValuesAPICall(ctx context.Context, label string, matches []string, timeRange TimeRangeInput, start, end time.Time, order string, truncationLimit int) (string, error) {
+	args := []any{label, slices.Sorted(slices.Values(matches)), timeRange.StartTime, timeRange.EndTime, order, truncationLimit}
+	return s.cachedAPICall(ctx, "label_values", args, func() (string, error) {
+		lvals, warnings, err := s.fetchLabelValues(ctx, label, matches, start, end)
+		if err != nil {
+			return "", err
+		}
+		sortList(lvals, order)
+
+		return s.formatTruncatedQueryAPIResponse(strings.Join(lvals, "\n"), warnings, truncationLimit)
+	})
+}
+
+// labelValuesPageAPICall returns one page of the values of the label, and the
+// number of the next page, or 0 if this is the last page.
+func (s *ServerContainer) labelValuesPageAPICall(ctx context.Context, label string, matches []string, start, end time.Time, order string, page, pageSize int) (string, int, error) {
+	lvals, warnings, err := s.fetchLabelValues(ctx, label, matches, start, end)
+	if err != nil {
+		return "", 0, err
+	}
+	sortList(lvals, order)
+
+	return s.formatPaginatedQueryAPIResponse(lvals, warnings, page, pageSize)
+}
+
+// labelExplosionResponse is the response structure for the label explosion tool.
+type labelExplosionResponse struct {
+	Label        string          `json:"label"`
+	ValueCount   int             `json:"value_count"`
+	Threshold    int             `json:"threshold"`
+	Exploding    bool            `json:"exploding"`
+	SampleValues []string        `json:"sample_values"`
+	Warnings     promv1.Warnings `json:"warnings"`
+}
+
+func (s *ServerContainer) labelExplosionAPICall(ctx context.Context, label string, matches []string, start, end time.Time, threshold int) (string, error) {
+	lvals, warnings, err := s.fetchLabelValues(ctx, label, matches, start, end)
+	if err != nil {
+		return "", err
+	}
+
+	sample := lvals
+	if len(sample) > labelExplosionSampleSize {
+		sample = sample[:labelExplosionSampleSize]
+	}
+
+	return s.FormatOutput(labelExplosionResponse{
+		Label:        label,
+		ValueCount:   len(lvals),
+		Threshold:    threshold,
+		Exploding:    len(lvals) > threshold,
+		SampleValues: sample,
+		Warnings:     warnings,
+	})
+}
+
+func (s *ServerContainer) metricsWithHelpAPICall(ctx context.Context, prefix string, truncationLimit int) (string, error) {
+	return s.cachedAPICall(ctx, "metrics_with_help", []any{prefix, truncationLimit}, func() (string, error) {
+		result, err := s.doAPICall(ctx, "/api/v1/metadata", "failed to get metric metadata from Prometheus",
+			func(ctx context.Context, client promv1.API) (any, error) {
+				return client.Metadata(ctx, "", "")
+			})
+		if err != nil {
+			return "", err
+		}
+
+		metadata, ok := result.(map[string][]promv1.Metadata)
+		if !ok {
+			return "", fmt.Errorf("unexpected metadata result type %T", result)
+		}
+
+		listing := formatMetricsWithHelp(metadata, prefix)
+		if listing == "" {
+			if prefix != "" {
+				return fmt.Sprintf("No metrics with metadata found with prefix %q.", prefix), nil
+			}
+			return "No metrics with metadata found.", nil
+		}
+		if truncated, ok := s.truncateResult(listing, truncationLimit); ok {
+			listing = truncated + s.resultTruncationWarning(listing, truncationLimit)
+		}
+		return listing, nil
+	})
+}
+
+// formatMetricsWithHelp lists the metrics whose name starts with prefix as
+// `name: help (type)` lines, sorted by name. Metrics exposed with different
+// metadata by different targets are listed with their first metadata entry.
+func formatMetricsWithHelp(metadata map[string][]promv1.Metadata, prefix string) string {
+	names := make([]string, 0, len(metadata))
+	for name, entries := range metadata {
+		if len(entries) > 0 && strings.HasPrefix(name, prefix) {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		m := metadata[name][0]
+		metricType := string(m.Type)
+		if metricType == "" {
+			metricType = string(promv1.MetricTypeUnknown)
+		}
+		help := strings.Join(strings.Fields(m.Help), " ")
+		if help == "" {
+			fmt.Fprintf(&b, "%s (%s)\n", name, metricType)
+		} else {
+			fmt.Fprintf(&b, "%s: %s (%s)\n", name, help, metricType)
+		}
+	}
+	return b.String()
+}
+
+func (s *ServerContainer) metricMetadataAPICall(ctx context.Context, metric, limit string, stripHelp bool) (string, error) {
+	// The global truncation limit doubles as the API's entry limit, which
+	// only makes sense when truncating by lines/entries.
+	truncationLimit := s.truncationLimit
+	if limit == "" && truncationLimit != 0 && s.truncationMode != TruncationModeBytes && s.truncationMode != TruncationModeTokens {
+		limit = strconv.Itoa(truncationLimit)
+	}
+
+	limitInt := 0
+	if limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil {
+			return "", fmt.Errorf("failed to convert limit to int: %w", err)
+		}
+		if n < 0 {
+			// In the `truncation_limit` field of other tools, `-1`
+			// means unlimited. If we're given a negative number
+			// here, normalize it to 0 for the prom API call to
+			// treat it as unlimited.
+			n = 0
+		}
+		limitInt = n
+	}
+
+	if limitInt == 0 {
+		limit = ""
+	}
+
+	return s.cachedAPICall(ctx, "metric_metadata", []any{metric, limitInt, stripHelp}, func() (string, error) {
+		v, err := s.doAPICall(ctx, "/api/v1/metadata", "failed to get metric metadata from Prometheus",
+			func(ctx context.Context, client promv1.API) (any, error) {
+				return client.Metadata(ctx, metric, limit)
+			})
+		if err != nil {
+			return "", err
+		}
+		mm, ok := v.(map[string][]promv1.Metadata)
+		if !ok {
+			return "", fmt.Errorf("unexpected metric metadata result type %T", v)
+		}
+
+		if stripHelp {
+			for _, entries := range mm {
+				for i := range entries {
+					entries[i].Help = ""
+				}
+			}
+		}
+
+		encodedData, err := s.FormatOutput(mm)
+		if err != nil {
+			return "", fmt.Errorf("failed to encode metric metadata: %w", err)
+		}
+
+		if limitInt != 0 {
+			encodedData += displayTruncationWarning(limitInt)
+		}
+
+		return encodedData, nil
+	})
+}
+
+func (s *ServerContainer) targetsMetadataAPICall(ctx context.Context, matchTarget, metric, limit string, stripHelp bool) (string, error) {
+	// The global truncation limit doubles as the API's entry limit, which
+	// only makes sense when truncating by lines/entries.
+	truncationLimit := s.truncationLimit
+	if limit == "" && truncationLimit != 0 && s.truncationMode != TruncationModeBytes && s.truncationMode != TruncationModeTokens {
+		limit = strconv.Itoa(truncationLimit)
+	}
+
+	limitInt := 0
+	if limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil {
+			return "", fmt.Errorf("failed to convert limit to int: %w", err)
+		}
+		if n < 0 {
+			// In the `truncation_limit` field of other tools, `-1`
+			// means unlimited. If we're given a negative number
+			// here, normalize it to 0 for the prom API call to
+			// treat it as unlimited.
+			n = 0
+		}
+		limitInt = n
+	}
+
+	if limitInt == 0 {
+		limit = ""
+	}
+
+	v, err := s.doAPICall(ctx, "/api/v1/targets/metadata", "failed to get target metadata from Prometheus",
+		func(ctx context.Context, client promv1.API) (any, error) {
+			return client.TargetsMetadata(ctx, matchTarget, metric, limit)
+		})
+	if err != nil {
+		return "", err
+	}
+	tm, ok := v.([]promv1.MetricMetadata)
+	if !ok {
+		return "", fmt.Errorf("unexpected target metadata result type %T", v)
+	}
+
+	if stripHelp {
+		for i := range tm {
+			tm[i].Help = ""
+		}
+	}
+
+	encodedData, err := s.FormatOutput(tm)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode target metadata: %w", err)
+	}
+
+	if limitInt != 0 {
+		encodedData += displayTruncationWarning(limitInt)
+	}
+
+	return encodedData, nil
+}
+
+// doSimpleAPICall encapsulates the common pattern for Prometheus API calls that
+// take no parameters beyond context: get client, set timeout, record metrics,
+// call the API, and format the result.
+func (s *ServerContainer) doSimpleAPICall(ctx context.Context, path, errMsg string, call func(context.Context, promv1.API) (any, error)) (string, error) {
+	result, err := s.doAPICall(ctx, path, errMsg, call)
+	if err != nil {
+		return "", err
+	}
+
+	return s.FormatOutput(result)
+}
+
+// doAPICall executes an API call with the configured timeout and telemetry,
+// returning the unformatted result for callers that need to process it.
+// Transient errors are retried, so call must not change state: admin calls
+// such as deleting series use the client directly.
+func (s *ServerContainer) doAPICall(ctx context.Context, path, errMsg string, call func(context.Context, promv1.API) (any, error)) (any, error) {
+	client, _ := s.GetAPIClient(ctx)
+	ctx, cancel := context.WithTimeout(ctx, s.getAPITimeout(ctx))
+	defer cancel()
+
+	var result any
+	err := s.retryAPICall(ctx, path, func() error {
+		var err error
+		startTs := time.Now()
+		result, err = call(ctx, client)
+		metricAPICallDuration.With(prometheus.Labels{"target_path": path}).Observe(time.Since(startTs).Seconds())
+		if err != nil {
+			metricAPICallsFailed.With(prometheus.Labels{"target_path": path}).Inc()
+		}
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", errMsg, s.withUnsupportedBackend(wrapClientError(err, path)))
+	}
+	s.recordAPICallSuccess(ctx)
+
+	return result, nil
+}
+
+// retryAPICall calls fn, retrying it on transient errors up to the configured
+// number of retries with a linearly growing backoff. The context deadline,
+// normally the API timeout, bounds the total time spent: no retry is
+// attempted if its backoff would end past the deadline.
+func (s *ServerContainer) retryAPICall(ctx context.Context, path string, fn func() error) error {
+	for attempt := 0; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= s.apiRetries || !isRetryableAPIError(err) {
+			return err
+		}
+
+		backoff := s.apiRetryBackoff * time.Duration(attempt+1)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < backoff {
+			return err
+		}
+
+		metrics.RecordAPICallRetry(path)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+	}
+}
+
+// isRetryableAPIError reports whether err is a transient error worth
+// retrying: a 5xx response or a connection error. Client errors and context
+// cancellation are not retried, as they would fail the same way again.
+func isRetryableAPIError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var apiErr *promv1.Error
+	if errors.As(err, &apiErr) {
+		return apiErr.Type == promv1.ErrServer
+	}
+
+	var statusErr *httpStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode >= http.StatusInternalServerError
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// httpStatusError is returned for non-ok HTTP responses to raw HTTP requests.
+type httpStatusError struct {
+	StatusCode int
+}
+
+func (e *httpStatusError) Error() string {
+	return fmt.Sprintf("received non-ok HTTP status code: %d", e.StatusCode)
+}
+
+// withUnsupportedBackend records the configured backend on
+// ErrEndpointNotSupported errors, so a 404 from a backend other than
+// Prometheus explains that the backend lacks the endpoint instead of
+// suggesting a Prometheus upgrade.
+func (s *ServerContainer) withUnsupportedBackend(err error) error {
+	backend := strings.ToLower(s.prometheusBackend)
+	var notSupported *ErrEndpointNotSupported
+	if backend != "" && backend != "prometheus" && errors.As(err, &notSupported) {
+		notSupported.Backend = backend
+	}
+	return err
+}
+
+// recordAPICallSuccess records a successful API call to the Prometheus
+// backend selected by the context.
+func (s *ServerContainer) recordAPICallSuccess(ctx context.Context) {
+	metrics.RecordSuccessfulAPICall(redactURL(s.getPrometheusURL(ctx)))
+}
+
+func (s *ServerContainer) alertmanagersAPICall(ctx context.Context) (string, error) {
+	return s.doSimpleAPICall(ctx, "/api/v1/alertmanagers", "failed to get alertmanager status from Prometheus",
+		func(ctx context.Context, client promv1.API) (any, error) {
+			return client.AlertManagers(ctx)
+		})
+}
+
+func (s *ServerContainer) flagsAPICall(ctx context.Context) (string, error) {
+	return s.doSimpleAPICall(ctx, "/api/v1/status/flags", "failed to get runtime flags from Prometheus",
+		func(ctx context.Context, client promv1.API) (any, error) {
+			return client.Flags(ctx)
+		})
+}
+
+func (s *ServerContainer) listAlertsAPICall(ctx context.Context, state promv1.AlertState, matchers []*labels.Matcher) (string, error) {
+	result, err := s.doAPICall(ctx, "/api/v1/alerts", "failed to get alerts from Prometheus",
+		func(ctx context.Context, client promv1.API) (any, error) {
+			return client.Alerts(ctx)
+		})
+	if err != nil {
+		return "", err
+	}
+
+	alertsResult, ok := result.(promv1.AlertsResult)
+	if !ok {
+		return "", fmt.Errorf("unexpected alerts result type %T", result)
+	}
+
+	return s.FormatOutput(filterAlerts(alertsResult, state, matchers))
+}
+
+// filterAlerts returns the alerts in the given state that match all of the
+// label matchers. An empty state matches alerts in any state.
+func filterAlerts(alerts promv1.AlertsResult, state promv1.AlertState, matchers []*labels.Matcher) promv1.AlertsResult {
+	if state == "" && len(matchers) == 0 {
+		return alerts
+	}
+
+	filtered := promv1.AlertsResult{Alerts: []promv1.Alert{}}
+	for _, a := range alerts.Alerts {
+		if state != "" && a.State != state {
+			continue
+		}
+		if !slices.ContainsFunc(matchers, func(m *labels.Matcher) bool {
+			return !m.Matches(string(a.Labels[model.LabelName(m.Name)]))
+		}) {
+			filtered.Alerts = append(filtered.Alerts, a)
+		}
+	}
+	return filtered
+}
+
+func (s *ServerContainer) activeAlertsDetailAPICall(ctx context.Context, state promv1.AlertState, now time.Time, truncationLimit int) (string, error) {
+	result, err := s.doAPICall(ctx, "/api/v1/alerts", "failed to get alerts from Prometheus",
+		func(ctx context.Context, client promv1.API) (any, error) {
+			return client.Alerts(ctx)
+		})
+	if err != nil {
+		return "", err
+	}
+
+	alertsResult, ok := result.(promv1.AlertsResult)
+	if !ok {
+		return "", fmt.Errorf("unexpected alerts result type %T", result)
+	}
+
+	alerts := make([]activeAlertDetail, 0, len(alertsResult.Alerts))
+	for _, a := range alertsResult.Alerts {
+		if a.State == promv1.AlertStateInactive || (state != "" && a.State != state) {
+			continue
+		}
+
+		activeFor := max(now.Sub(a.ActiveAt), 0).Truncate(time.Second)
+		alerts = append(alerts, activeAlertDetail{
+			AlertName:        string(a.Labels[model.AlertNameLabel]),
+			State:            string(a.State),
+			ActiveAt:         a.ActiveAt,
+			ActiveFor:        model.Duration(activeFor).String(),
+			ActiveForSeconds: activeFor.Seconds(),
+			Value:            a.Value,
+			Labels:           a.Labels,
+			Annotations:      a.Annotations,
+		})
+	}
+
+	// Longest-active first.
+	sort.SliceStable(alerts, func(i, j int) bool {
+		return alerts[i].ActiveAt.Before(alerts[j].ActiveAt)
+	})
+
+	resp := activeAlertsDetailResponse{Alerts: alerts}
+	if truncationLimit > 0 && len(alerts) > truncationLimit {
+		resp.Alerts = alerts[:truncationLimit]
+		resp.Truncated = strings.TrimSpace(displayTruncationWarning(truncationLimit))
+	}
+
+	return s.FormatOutput(resp)
+}
+
+func (s *ServerContainer) tsdbStatsAPICall(ctx context.Context) (string, error) {
+	return s.doSimpleAPICall(ctx, "/api/v1/status/tsdb", "failed to get tsdb stats from Prometheus",
+		func(ctx context.Context, client promv1.API) (any, error) {
+			return client.TSDB(ctx)
+		})
+}
+
+func (s *ServerContainer) buildinfoAPICall(ctx context.Context) (string, error) {
+	return s.doSimpleAPICall(ctx, "/api/v1/status/buildinfo", "failed to get build info from Prometheus",
+		func(ctx context.Context, client promv1.API) (any, error) {
+			return client.Buildinfo(ctx)
+		})
+}
+
+func (s *ServerContainer) configAPICall(ctx context.Context) (string, error) {
+	return s.doSimpleAPICall(ctx, "/api/v1/status/config", "failed to get configuration from Prometheus",
+		func(ctx context.Context, client promv1.API) (any, error) {
+			return client.Config(ctx)
+		})
+}
+
+// loadedConfig gets the configuration currently loaded by Prometheus.
+func (s *ServerContainer) loadedConfig(ctx context.Context) (promv1.ConfigResult, error) {
+	result, err := s.doAPICall(ctx, "/api/v1/status/config", "failed to get configuration from Prometheus",
+		func(ctx context.Context, client promv1.API) (any, error) {
+			return client.Config(ctx)
+		})
+	if err != nil {
+		return promv1.ConfigResult{}, err
+	}
+
+	loaded, ok := result.(promv1.ConfigResult)
+	if !ok {
+		return promv1.ConfigResult{}, fmt.Errorf("unexpected config result type %T", result)
+	}
+	return loaded, nil
+}
+
+func (s *ServerContainer) jobConfigAPICall(ctx context.Context, jobName string) (string, error) {
+	loaded, err := s.loadedConfig(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	job, err := findScrapeJobConfig(loaded.YAML, jobName)
+	if err != nil {
+		return "", err
+	}
+	redactConfigSecrets(job, "")
+
+	encoded, err := yaml.Marshal(job)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode job config: %w", err)
+	}
+
+	return s.FormatOutput(jobConfigResponse{
+		JobName: jobName,
+		Config:  string(encoded),
+	})
+}
+
+func (s *ServerContainer) scrapeConfigsAPICall(ctx context.Context, jobName string) (string, error) {
+	loaded, err := s.loadedConfig(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	cfg, err := parseScrapeConfigs(loaded.YAML)
+	if err != nil {
+		return "", err
+	}
+
+	nodes := make([]*yaml.Node, 0, len(cfg.ScrapeConfigs))
+	if jobName != "" {
+		job, err := cfg.findJob(jobName)
+		if err != nil {
+			return "", err
+		}
+		nodes = append(nodes, job)
+	} else {
+		for i := range cfg.ScrapeConfigs {
+			nodes = append(nodes, &cfg.ScrapeConfigs[i])
+		}
+	}
+
+	resp := scrapeConfigsResponse{
+		ScrapeConfigs:     make([]map[string]any, 0, len(nodes)),
+		ScrapeConfigFiles: cfg.ScrapeConfigFiles,
+	}
+	for _, node := range nodes {
+		redactConfigSecrets(node, "")
+		var sc map[string]any
+		if err := node.Decode(&sc); err != nil {
+			return "", fmt.Errorf("failed to parse scrape config: %w", err)
+		}
+		resp.ScrapeConfigs = append(resp.ScrapeConfigs, sc)
+	}
+	if len(cfg.ScrapeConfigFiles) > 0 {
+		resp.Message = "Jobs from scrape_config_files are not included in the config served by the API."
+	}
+
+	return s.FormatOutput(resp)
+}
+
+// fetchOnDiskAndLoadedConfigs returns the Prometheus config file set with
+// --prometheus.config-path and the config loaded by Prometheus.
+func (s *ServerContainer) fetchOnDiskAndLoadedConfigs(ctx context.Context) (onDisk, loaded string, err error) {
+	b, err := os.ReadFile(s.prometheusConfigPath)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read config file from disk: %w", err)
+	}
+
+	result, err := s.doAPICall(ctx, "/api/v1/status/config", "failed to get configuration from Prometheus",
+		func(ctx context.Context, client promv1.API) (any, error) {
+			return client.Config(ctx)
+		})
+	if err != nil {
+		return "", "", err
+	}
+
+	cfg, ok := result.(promv1.ConfigResult)
+	if !ok {
+		return "", "", fmt.Errorf("unexpected config result type %T", result)
+	}
+	return string(b), cfg.YAML, nil
+}
+
+func (s *ServerContainer) configPendingChangesAPICall(ctx context.Context) (string, error) {
+	onDisk, loaded, err := s.fetchOnDiskAndLoadedConfigs(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	changes, err := diffPrometheusConfigs(onDisk, loaded)
+	if err != nil {
+		return "", err
+	}
+
+	resp := configPendingChangesResponse{
+		ConfigPath:   s.prometheusConfigPath,
+		Status:       "in sync",
+		ReloadNeeded: len(changes) > 0,
+		Changes:      changes,
+	}
+	if resp.ReloadNeeded {
+		resp.Status = "pending changes"
+	}
+
+	return s.FormatOutput(resp)
+}
+
+func (s *ServerContainer) configDiffAPICall(ctx context.Context) (string, error) {
+	onDisk, loaded, err := s.fetchOnDiskAndLoadedConfigs(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	normalizedDisk, err := normalizeConfigYAML(onDisk)
+	if err != nil {
+		return "", fmt.Errorf("failed to normalize config file %q: %w", s.prometheusConfigPath, err)
+	}
+	normalizedLoaded, err := normalizeConfigYAML(loaded)
+	if err != nil {
+		return "", fmt.Errorf("failed to normalize loaded config: %w", err)
+	}
+
+	diff := unifiedDiff(s.prometheusConfigPath, "loaded", normalizedDisk, normalizedLoaded, configDiffContextLines)
+	if diff == "" {
+		return "no differences", nil
+	}
+	return diff, nil
+}
+
+// Statuses of a status overview section.
+const (
+	statusOverviewOK    = "ok"
+	statusOverviewError = "error"
+)
+
+// statusOverviewSection is the outcome of one of the calls aggregated by the
+// status overview tool. A failed call is reported in its own section instead
+// of failing the whole overview.
+type statusOverviewSection struct {
+	Status string `json:"status"`
+	Result any    `json:"result,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+func newStatusOverviewSection(result any, err error) statusOverviewSection {
+	if err != nil {
+		return statusOverviewSection{Status: statusOverviewError, Error: err.Error()}
+	}
+	return statusOverviewSection{Status: statusOverviewOK, Result: result}
+}
+
+type statusOverviewResponse struct {
+	Healthy     statusOverviewSection `json:"healthy"`
+	Ready       statusOverviewSection `json:"ready"`
+	BuildInfo   statusOverviewSection `json:"build_info"`
+	RuntimeInfo statusOverviewSection `json:"runtime_info"`
+	TSDBStats   statusOverviewSection `json:"tsdb_stats"`
+}
+
+func (s *ServerContainer) statusOverviewAPICall(ctx context.Context) (string, error) {
+	var resp statusOverviewResponse
+
+	resp.Healthy = newStatusOverviewSection(s.doManagementAPICall(ctx, http.MethodGet, mgmtAPIHealthyEndpoint))
+	resp.Ready = newStatusOverviewSection(s.doManagementAPICall(ctx, http.MethodGet, mgmtAPIReadyEndpoint))
+	resp.BuildInfo = newStatusOverviewSection(s.doAPICall(ctx, "/api/v1/status/buildinfo", "failed to get build info from Prometheus",
+		func(ctx context.Context, client promv1.API) (any, error) {
+			return client.Buildinfo(ctx)
+		}))
+	resp.RuntimeInfo = newStatusOverviewSection(s.doAPICall(ctx, "/api/v1/status/runtimeinfo", "failed to get runtime info from Prometheus",
+		func(ctx context.Context, client promv1.API) (any, error) {
+			return client.Runtimeinfo(ctx)
+		}))
+	resp.TSDBStats = newStatusOverviewSection(s.doAPICall(ctx, "/api/v1/status/tsdb", "failed to get tsdb stats from Prometheus",
+		func(ctx context.Context, client promv1.API) (any, error) {
+			return client.TSDB(ctx)
+		}))
+
+	return s.FormatOutput(resp)
+}
+
+func (s *ServerContainer) runtimeinfoAPICall(ctx context.Context) (string, error) {
+	return s.doSimpleAPICall(ctx, "/api/v1/status/runtimeinfo", "failed to get runtime info from Prometheus",
+		func(ctx context.Context, client promv1.API) (any, error) {
+			return client.Runtimeinfo(ctx)
+		})
+}
+
+// Rule types accepted by the list rules tool.
+const (
+	ruleTypeAny       = "any"
+	ruleTypeAlerting  = "alerting"
+	ruleTypeRecording = "recording"
+)
+
+func (s *ServerContainer) rulesAPICall(ctx context.Context, ruleType, ruleGroup, file string, excludeAlerts bool) (string, error) {
+	if excludeAlerts {
+		rules, err := s.rulesWithoutAlerts(ctx)
+		if err != nil {
+			return "", err
+		}
+		return s.FormatOutput(filterRules(rules, ruleType, ruleGroup, file))
+	}
+
+	result, err := s.doAPICall(ctx, "/api/v1/rules", "failed to get rules from Prometheus",
+		func(ctx context.Context, client promv1.API) (any, error) {
+			return client.Rules(ctx, nil)
+		})
+	if err != nil {
+		return "", err
+	}
+
+	rules, ok := result.(promv1.RulesResult)
+	if !ok {
+		return "", fmt.Errorf("unexpected rules result type %T", result)
+	}
+
+	return s.FormatOutput(filterRules(rules, ruleType, ruleGroup, file))
+}
+
+// rulesWithoutAlerts fetches the rules with the exclude_alerts parameter set.
+// The client library's Rules call has no way to pass it, so the request is
+// made directly. Backends that don't know the parameter ignore it and return
+// the alerts as usual.
+func (s *ServerContainer) rulesWithoutAlerts(ctx context.Context) (promv1.RulesResult, error) {
+	_, rt := s.GetAPIClient(ctx)
+	ctx, cancel := context.WithTimeout(ctx, s.getAPITimeout(ctx))
+	defer cancel()
+
+	path := "/api/v1/rules"
+	fullPath, err := url.JoinPath(s.getPrometheusURL(ctx), path)
+	if err != nil {
+		return promv1.RulesResult{}, fmt.Errorf("failed to construct URL for request: %w", err)
+	}
+	query := url.Values{"exclude_alerts": []string{"true"}}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fullPath+"?"+query.Encode(), nil)
+	if err != nil {
+		return promv1.RulesResult{}, fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	body, err := s.fetchHTTPResponseBody(req, rt, s.getPrometheusURL(ctx), path)
+	if err != nil {
+		return promv1.RulesResult{}, s.withUnsupportedBackend(err)
+	}
+
+	var rules struct {
+		Data promv1.RulesResult `json:"data"`
+	}
+	if err := json.Unmarshal(body, &rules); err != nil {
+		return promv1.RulesResult{}, fmt.Errorf("failed to unmarshal JSON response: %w", err)
+	}
+
+	return rules.Data, nil
+}
+
+// filterRules returns the rule groups matching the given group name and file,
+// with only the rules of the given type. Empty filters match everything.
+// Groups left without rules by the type filter are dropped.
+func filterRules(rules promv1.RulesResult, ruleType, ruleGroup, file string) promv1.RulesResult {
+	if ruleGroup == "" && file == "" && (ruleType == "" || ruleType == ruleTypeAny) {
+		return rules
+	}
+
+	filtered := promv1.RulesResult{Groups: []promv1.RuleGroup{}}
+	for _, group := range rules.Groups {
+		if ruleGroup != "" && group.Name != ruleGroup {
+			continue
+		}
+		if file != "" && group.File != file && filepath.Base(group.File) != file {
+			continue
+		}
+
+		if ruleType == "" || ruleType == ruleTypeAny {
+			filtered.Groups = append(filtered.Groups, group)
+			continue
+		}
+
+		var groupRules []any
+		for _, rule := range group.Rules {
+			switch rule.(type) {
+			case promv1.AlertingRule:
+				if ruleType == ruleTypeAlerting {
+					groupRules = append(groupRules, rule)
+				}
+			case promv1.RecordingRule:
+				if ruleType == ruleTypeRecording {
+					groupRules = append(groupRules, rule)
+				}
+			}
+		}
+		if len(groupRules) > 0 {
+			group.Rules = groupRules
+			filtered.Groups = append(filtered.Groups, group)
+		}
+	}
+	return filtered
+}
+
+// Target states accepted by the list targets tool.
+const (
+	targetStateAny     = "any"
+	targetStateActive  = "active"
+	targetStateDropped = "dropped"
+)
+
+func (s *ServerContainer) targetsAPICall(ctx context.Context, scrapePool, state string, health promv1.HealthStatus) (string, error) {
+	result, err := s.doAPICall(ctx, "/api/v1/targets", "failed to get targets from Prometheus",
+		func(ctx context.Context, client promv1.API) (any, error) {
+			return client.Targets(ctx)
+		})
+	if err != nil {
+		return "", err
+	}
+
+	targets, ok := result.(promv1.TargetsResult)
+	if !ok {
+		return "", fmt.Errorf("unexpected targets result type %T", result)
+	}
+
+	return s.FormatOutput(filterTargets(targets, scrapePool, state, health))
+}
+
+// filterTargets returns the targets matching all of the given filters. Empty
+// filters match everything. Dropped targets have no scrape pool or health,
+// so they are matched on their discovered `job` label, and excluded entirely
+// when filtering on health.
+func filterTargets(targets promv1.TargetsResult, scrapePool, state string, health promv1.HealthStatus) promv1.TargetsResult {
+	if scrapePool == "" && health == "" && (state == "" || state == targetStateAny) {
+		return targets
+	}
+
+	filtered := promv1.TargetsResult{
+		Active:  []promv1.ActiveTarget{},
+		Dropped: []promv1.DroppedTarget{},
+	}
+	if state != targetStateDropped {
+		for _, t := range targets.Active {
+			if (scrapePool == "" || t.ScrapePool == scrapePool) && (health == "" || t.Health == health) {
+				filtered.Active = append(filtered.Active, t)
+			}
+		}
+	}
+	if state != targetStateActive && health == "" {
+		for _, t := range targets.Dropped {
+			if scrapePool == "" || t.DiscoveredLabels[model.JobLabel] == scrapePool {
+				filtered.Dropped = append(filtered.Dropped, t)
+			}
+		}
+	}
+	return filtered
+}
+
+func (s *ServerContainer) targetChurnAPICall(ctx context.Context, truncationLimit int) (string, error) {
+	result, err := s.doAPICall(ctx, "/api/v1/targets", "failed to get targets from Prometheus",
+		func(ctx context.Context, client promv1.API) (any, error) {
+			return client.Targets(ctx)
+		})
+	if err != nil {
+		return "", err
+	}
+
+	targetsResult, ok := result.(promv1.TargetsResult)
+	if !ok {
+		return "", fmt.Errorf("unexpected targets result type %T", result)
+	}
+
+	current := make(map[string]targetChurnEntry, len(targetsResult.Active))
+	for _, t := range targetsResult.Active {
+		current[targetChurnID(t)] = targetChurnEntry{
+			ScrapePool: t.ScrapePool,
+			ScrapeURL:  t.ScrapeURL,
+			Labels:     t.Labels,
+		}
+	}
+
+	backendURL := s.getPrometheusURL(ctx)
+	s.targetChurnMu.Lock()
+	previous, seen := s.targetChurnBaselines[backendURL]
+	if s.targetChurnBaselines == nil {
+		s.targetChurnBaselines = make(map[string]map[string]targetChurnEntry)
+	}
+	s.targetChurnBaselines[backendURL] = current
+	s.targetChurnMu.Unlock()
+
+	resp := targetChurnResponse{
+		BaselineSet:     !seen,
+		PreviousTargets: len(previous),
+		CurrentTargets:  len(current),
+		Appeared:        []targetChurnEntry{},
+		Disappeared:     []targetChurnEntry{},
+	}
+	if seen {
+		resp.Appeared = targetChurnDiff(current, previous)
+		resp.Disappeared = targetChurnDiff(previous, current)
+	}
+
+	if truncationLimit > 0 && (len(resp.Appeared) > truncationLimit || len(resp.Disappeared) > truncationLimit) {
+		resp.Appeared = resp.Appeared[:min(len(resp.Appeared), truncationLimit)]
+		resp.Disappeared = resp.Disappeared[:min(len(resp.Disappeared), truncationLimit)]
+		resp.Truncated = strings.TrimSpace(displayTruncationWarning(truncationLimit))
+	}
+
+	return s.FormatOutput(resp)
+}
+
+// targetChurnID returns a stable identity for an active target. The scrape
+// URL is unique within a scrape pool; the discovered labels are used as a
+// fallback for targets without one.
+func targetChurnID(t promv1.ActiveTarget) string {
+	if t.ScrapeURL != "" {
+		return t.ScrapePool + "|" + t.ScrapeURL
+	}
+	return t.ScrapePool + "|" + labels.FromMap(t.DiscoveredLabels).String()
+}
+
+// targetChurnDiff returns the entries in a that are not in b, ordered by
+// target identity.
+func targetChurnDiff(a, b map[string]targetChurnEntry) []targetChurnEntry {
+	ids := make([]string, 0, len(a))
+	for id := range a {
+		if _, ok := b[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	diff := make([]targetChurnEntry, 0, len(ids))
+	for _, id := range ids {
+		diff = append(diff, a[id])
+	}
+	return diff
+}
+
+// scrapeTargetResponse is the response structure for the scrape target tool.
+type scrapeTargetResponse struct {
+	URL     string `json:"url"`
+	Metrics string `json:"metrics"`
+}
+
+// scrapeTargetAcceptHeader asks targets for the Prometheus text exposition
+// format, which is the most readable for LLMs.
+const scrapeTargetAcceptHeader = "text/plain;version=0.0.4;q=0.9,*/*;q=0.1"
+
+func (s *ServerContainer) scrapeTargetAPICall(ctx context.Context, scrapeURL string, truncationLimit int) (string, error) {
+	result, err := s.doAPICall(ctx, "/api/v1/targets", "failed to get targets from Prometheus",
+		func(ctx context.Context, client promv1.API) (any, error) {
+			return client.Targets(ctx)
+		})
+	if err != nil {
+		return "", err
+	}
+
+	targets, ok := result.(promv1.TargetsResult)
+	if !ok {
+		return "", fmt.Errorf("unexpected targets result type %T", result)
+	}
+	if !slices.Contains(knownScrapeURLs(targets), scrapeURL) {
+		return "", fmt.Errorf("%q is not the scrape URL of a target known to Prometheus, use list_targets to find it", scrapeURL)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.getAPITimeout(ctx))
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, scrapeURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	req.Header.Set("Accept", scrapeTargetAcceptHeader)
+
+	// Targets are not Prometheus, so they are fetched without the
+	// --http.config client, which may carry credentials for Prometheus, and
+	// under a fixed metric path so target URLs don't become label values.
+	body, err := s.fetchHTTPResponseBody(req, http.DefaultTransport, "", "scrape_target")
+	if err != nil {
+		return "", err
+	}
+
+	metrics := string(body)
+	if truncated, ok := s.truncateResult(metrics, truncationLimit); ok {
+		metrics = truncated + s.resultTruncationWarning(metrics, truncationLimit)
+	}
+
+	return s.FormatOutput(scrapeTargetResponse{
+		URL:     scrapeURL,
+		Metrics: metrics,
+	})
+}
+
+// knownScrapeURLs returns the scrape URLs of all targets. Dropped targets
+// have no scrape URL, so theirs is built from their discovered scheme,
+// address, and metrics path.
+func knownScrapeURLs(targets promv1.TargetsResult) []string {
+	urls := make([]string, 0, len(targets.Active)+len(targets.Dropped))
+	for _, t := range targets.Active {
+		if t.ScrapeURL != "" {
+			urls = append(urls, t.ScrapeURL)
+		}
+	}
+	for _, t := range targets.Dropped {
+		scheme, address := t.DiscoveredLabels[model.SchemeLabel], t.DiscoveredLabels[model.AddressLabel]
+		if scheme == "" || address == "" {
+			continue
+		}
+		u := url.URL{Scheme: scheme, Host: address, Path: t.DiscoveredLabels[model.MetricsPathLabel]}
+		urls = append(urls, u.String())
+	}
+	return urls
+}
+
+func (s *ServerContainer) sampleLimitsAPICall(ctx context.Context, threshold float64, ts time.Time, truncationLimit int) (string, error) {
+	result, err := s.doAPICall(ctx, "/api/v1/status/config", "failed to get configuration from Prometheus",
+		func(ctx context.Context, client promv1.API) (any, error) {
+			return client.Config(ctx)
+		})
+	if err != nil {
+		return "", err
+	}
+
+	cfg, ok := result.(promv1.ConfigResult)
+	if !ok {
+		return "", fmt.Errorf("unexpected config result type %T", result)
+	}
+	jobLimits, globalLimit, err := parseScrapeSampleLimits(cfg.YAML)
+	if err != nil {
+		return "", err
+	}
+
+	scraped, err := s.instantVectorAPICall(ctx, "scrape_samples_scraped", ts)
+	if err != nil {
+		return "", err
+	}
+	postRelabeling, err := s.instantVectorAPICall(ctx, "scrape_samples_post_metric_relabeling", ts)
+	if err != nil {
+		return "", err
+	}
+
+	type targetKey struct{ job, instance string }
+	postByTarget := make(map[targetKey]float64, len(postRelabeling))
+	for _, sample := range postRelabeling {
+		key := targetKey{string(sample.Metric[model.JobLabel]), string(sample.Metric[model.InstanceLabel])}
+		postByTarget[key] = float64(sample.Value)
+	}
+
+	resp := sampleLimitsResponse{
+		Threshold: threshold,
+		Targets:   []sampleLimitTarget{},
+	}
+	for _, sample := range scraped {
+		key := targetKey{string(sample.Metric[model.JobLabel]), string(sample.Metric[model.InstanceLabel])}
+		limit, ok := jobLimits[key.job]
+		if !ok {
+			limit = globalLimit
+		}
+		if limit <= 0 {
+			resp.UnlimitedTargets++
+			continue
+		}
+
+		// The sample limit is enforced after metric relabeling.
+		post, ok := postByTarget[key]
+		if !ok {
+			post = float64(sample.Value)
+		}
+
+		target := sampleLimitTarget{
+			Job:                   key.job,
+			Instance:              key.instance,
+			SamplesScraped:        float64(sample.Value),
+			SamplesPostRelabeling: post,
+			SampleLimit:           limit,
+			Utilization:           post / float64(limit),
+		}
+		target.AtRisk = target.Utilization >= threshold
+		if target.AtRisk {
+			resp.AtRiskCount++
+		}
+		resp.Targets = append(resp.Targets, target)
+	}
+
+	// Closest to the limit first.
+	sort.SliceStable(resp.Targets, func(i, j int) bool {
+		return resp.Targets[i].Utilization > resp.Targets[j].Utilization
+	})
+
+	if truncationLimit > 0 && len(resp.Targets) > truncationLimit {
+		resp.Targets = resp.Targets[:truncationLimit]
+		resp.Truncated = strings.TrimSpace(displayTruncationWarning(truncationLimit))
+	}
+
+	return s.FormatOutput(resp)
+}
+
+func (s *ServerContainer) fleetHealthAPICall(ctx context.Context, groupBy string, threshold float64, ts time.Time, truncationLimit int) (string, error) {
+	up, err := s.instantVectorAPICall(ctx, "up", ts)
+	if err != nil {
+		return "", err
+	}
+
+	resp := summarizeFleetHealth(up, model.LabelName(groupBy), threshold)
+	if truncationLimit > 0 && len(resp.Groups) > truncationLimit {
+		resp.Groups = resp.Groups[:truncationLimit]
+		resp.Truncated = strings.TrimSpace(displayTruncationWarning(truncationLimit))
+	}
+
+	return s.FormatOutput(resp)
+}
+
+// summarizeFleetHealth groups the samples of the `up` metric by the given
+// label and computes the percentage of targets up in each group. Targets
+// without the label are collected into a separate group. Groups are sorted
+// least healthy first, so truncation keeps the ones needing attention.
+func summarizeFleetHealth(up model.Vector, groupBy model.LabelName, threshold float64) fleetHealthResponse {
+	type groupKey struct {
+		value   string
+		missing bool
+	}
+	groups := make(map[groupKey]*fleetHealthGroup)
+
+	resp := fleetHealthResponse{
+		GroupBy:   string(groupBy),
+		Threshold: threshold,
+		Groups:    []fleetHealthGroup{},
+	}
+	for _, sample := range up {
+		value, ok := sample.Metric[groupBy]
+		key := groupKey{value: string(value), missing: !ok}
+		group, exists := groups[key]
+		if !exists {
+			group = &fleetHealthGroup{Value: key.value, MissingLabel: key.missing}
+			groups[key] = group
+		}
+
+		group.TargetsTotal++
+		resp.TargetsTotal++
+		if sample.Value == 1 {
+			group.TargetsUp++
+			resp.TargetsUp++
+		}
+	}
+
+	for _, group := range groups {
+		group.PercentUp = 100 * float64(group.TargetsUp) / float64(group.TargetsTotal)
+		group.BelowThreshold = group.PercentUp < threshold
+		if group.BelowThreshold {
+			resp.GroupsBelowThreshold++
+		}
+		resp.Groups = append(resp.Groups, *group)
+	}
+	if resp.TargetsTotal > 0 {
+		resp.PercentUp = 100 * float64(resp.TargetsUp) / float64(resp.TargetsTotal)
+	}
+
+	sort.Slice(resp.Groups, func(i, j int) bool {
+		a, b := resp.Groups[i], resp.Groups[j]
+		if a.PercentUp != b.PercentUp {
+			return a.PercentUp < b.PercentUp
+		}
+		if a.MissingLabel != b.MissingLabel {
+			return a.MissingLabel
+		}
+		return a.Value < b.Value
+	})
+
+	return resp
+}
+
+func (s *ServerContainer) whyNoDataAPICall(ctx context.Context, selector string, matchers []*labels.Matcher, lookback time.Duration, now time.Time) (string, error) {
+	result, err := s.doAPICall(ctx, "/api/v1/series", "failed to get series from Prometheus",
+		func(ctx context.Context, client promv1.API) (any, error) {
+			series, _, err := client.Series(ctx, []string{selector}, now.Add(-lookback), now)
+			return series, err
+		})
+	if err != nil {
+		return "", err
+	}
+	series, ok := result.([]model.LabelSet)
+	if !ok {
+		return "", fmt.Errorf("unexpected series result type %T", result)
+	}
+
+	current, err := s.instantVectorAPICall(ctx, selector, now)
+	if err != nil {
+		return "", err
+	}
+
+	// The jobs to check are the ones of the series that existed, or the one
+	// the selector asks for if there were none.
+	jobSet := make(map[string]struct{})
+	for _, ls := range series {
+		if job, ok := ls[model.JobLabel]; ok {
+			jobSet[string(job)] = struct{}{}
+		}
+	}
+	if len(jobSet) == 0 {
+		for _, m := range matchers {
+			if m.Name == string(model.JobLabel) && m.Type == labels.MatchEqual && m.Value != "" {
+				jobSet[m.Value] = struct{}{}
+			}
+		}
+	}
+	jobNames := slices.Sorted(maps.Keys(jobSet))
+
+	var (
+		targets    promv1.TargetsResult
+		targetsErr error
+	)
+	if len(jobNames) > 0 {
+		var result any
+		result, targetsErr = s.doAPICall(ctx, "/api/v1/targets", "failed to get targets from Prometheus",
+			func(ctx context.Context, client promv1.API) (any, error) {
+				return client.Targets(ctx)
+			})
+		if targetsErr == nil {
+			if targets, ok = result.(promv1.TargetsResult); !ok {
+				targetsErr = fmt.Errorf("unexpected targets result type %T", result)
+			}
+		}
+	}
+
+	resp := diagnoseNoData(selector, lookback, len(series), len(current), jobNames, targets, targetsErr)
+	return s.FormatOutput(resp)
+}
+
+// diagnoseNoData builds the diagnosis of the why_no_data tool from the
+// number of series that matched the selector during the lookback window and
+// that have a sample now, and the targets of the jobs involved. A down
+// target of an involved job is the most likely cause, so it takes precedence
+// over staleness in the status.
+func diagnoseNoData(selector string, lookback time.Duration, seriesInLookback, currentSeries int, jobNames []string, targets promv1.TargetsResult, targetsErr error) whyNoDataResponse {
+	lookbackStr := model.Duration(lookback).String()
+	resp := whyNoDataResponse{
+		Selector:         selector,
+		Lookback:         lookbackStr,
+		SeriesInLookback: seriesInLookback,
+		CurrentSeries:    currentSeries,
+		Findings:         []string{},
+	}
+
+	switch {
+	case seriesInLookback == 0 && currentSeries == 0:
+		resp.Status = whyNoDataStatusNoSeries
+		resp.Diagnosis = fmt.Sprintf("no series match the selector in the last %s", lookbackStr)
+		resp.Findings = append(resp.Findings, "Check the metric name and label matchers for typos, e.g. with find_metrics, label_names, and label_values, or increase the lookback if the metric is only exposed occasionally.")
+	case currentSeries == 0:
+		resp.Status = whyNoDataStatusStale
+		resp.Diagnosis = fmt.Sprintf("%d series matched the selector in the last %s, but none has a current sample, the series are stale", seriesInLookback, lookbackStr)
+		resp.Findings = append(resp.Findings, "Series go stale when their target stops exposing them, the target is removed from service discovery, or its scrapes fail.")
+	default:
+		resp.Status = whyNoDataStatusHasData
+		resp.Diagnosis = fmt.Sprintf("%d series currently have samples, the selector returns data", currentSeries)
+		if seriesInLookback > currentSeries {
+			resp.Findings = append(resp.Findings, fmt.Sprintf("%d of the %d series that matched in the last %s are stale.", seriesInLookback-currentSeries, seriesInLookback, lookbackStr))
+		}
+		resp.Findings = append(resp.Findings, "If a larger query returns no data, check its other selectors, functions, and aggregations, e.g. with query_explain.")
+	}
+
+	switch {
+	case len(jobNames) == 0:
+		resp.Findings = append(resp.Findings, "No job could be determined from the series or the selector, so target health was not checked. Add a job matcher to the selector to check it.")
+		return resp
+	case targetsErr != nil:
+		resp.Findings = append(resp.Findings, "Target health could not be checked: "+targetsErr.Error())
+		return resp
+	}
+
+	jobs := make(map[string]*whyNoDataJob, len(jobNames))
+	for _, name := range jobNames {
+		jobs[name] = &whyNoDataJob{Job: name}
+	}
+	for _, t := range targets.Active {
+		job, ok := jobs[string(t.Labels[model.JobLabel])]
+		if !ok {
+			continue
+		}
+		switch t.Health {
+		case promv1.HealthGood:
+			job.TargetsUp++
+		case promv1.HealthBad:
+			job.TargetsDown++
+			if len(job.DownTargets) < whyNoDataMaxDownTargets {
+				job.DownTargets = append(job.DownTargets, whyNoDataTarget{
+					ScrapeURL:  t.ScrapeURL,
+					Health:     string(t.Health),
+					LastError:  t.LastError,
+					LastScrape: t.LastScrape.UTC(),
+				})
+			}
+		default:
+			job.TargetsUnknown++
+		}
+	}
+	for _, t := range targets.Dropped {
+		if job, ok := jobs[t.DiscoveredLabels[model.JobLabel]]; ok {
+			job.TargetsDropped++
+		}
+	}
+
+	for _, name := range jobNames {
+		job := jobs[name]
+		resp.Jobs = append(resp.Jobs, *job)
+
+		for _, t := range job.DownTargets {
+			finding := fmt.Sprintf("Target %s of job %q is down", t.ScrapeURL, name)
+			if t.LastError != "" {
+				finding += ": " + t.LastError
+			}
+			resp.Findings = append(resp.Findings, finding+".")
+		}
+		if job.TargetsDown > len(job.DownTargets) {
+			resp.Findings = append(resp.Findings, fmt.Sprintf("%d more targets of job %q are down.", job.TargetsDown-len(job.DownTargets), name))
+		}
+
+		if job.TargetsUp+job.TargetsDown+job.TargetsUnknown == 0 {
+			finding := fmt.Sprintf("Job %q has no active targets", name)
+			if job.TargetsDropped > 0 {
+				finding += fmt.Sprintf(", %d discovered targets were dropped by relabeling", job.TargetsDropped)
+			}
+			resp.Findings = append(resp.Findings, finding+". Check its service discovery and relabeling, e.g. with job_config and list_targets.")
+		}
+	}
+
+	if resp.Status != whyNoDataStatusHasData {
+		for _, job := range resp.Jobs {
+			if job.TargetsDown > 0 {
+				resp.Status = whyNoDataStatusTargetDown
+				resp.Diagnosis = fmt.Sprintf("%s; %d targets of job %q are down", resp.Diagnosis, job.TargetsDown, job.Job)
+				break
+			}
+		}
+	}
+
+	return resp
+}
+
+// detectGapsChunk is the longest range of raw samples read by a single query
+// of the detect gaps tool, bounding the size of each response.
+const detectGapsChunk = 6 * time.Hour
+
+func (s *ServerContainer) detectGapsAPICall(ctx context.Context, selector string, start, end time.Time, resolution time.Duration, truncationLimit int) (string, error) {
+	// Read the raw samples with range selectors instead of running a range
+	// query, which fills every step from the last sample within the lookback
+	// delta and so hides any gap shorter than it. Range selectors exclude
+	// their start, so the first chunk begins just before the start time.
+	streams := map[model.Fingerprint]*model.SampleStream{}
+	for from := start.Add(-time.Millisecond); from.Before(end); {
+		to := from.Add(detectGapsChunk)
+		if to.After(end) {
+			to = end
+		}
+		query := fmt.Sprintf("%s[%s]", selector, model.Duration(to.Sub(from)))
+		result, err := s.doAPICall(ctx, "/api/v1/query", "failed to execute instant query",
+			func(ctx context.Context, client promv1.API) (any, error) {
+				v, _, err := client.Query(ctx, query, to)
+				return v, err
+			})
+		if err != nil {
+			return "", err
+		}
+
+		matrix, ok := result.(model.Matrix)
+		if !ok {
+			return "", fmt.Errorf("unexpected result type %T for selector %q", result, selector)
+		}
+		for _, stream := range matrix {
+			fp := stream.Metric.Fingerprint()
+			if merged, ok := streams[fp]; ok {
+				merged.Values = append(merged.Values, stream.Values...)
+				merged.Histograms = append(merged.Histograms, stream.Histograms...)
+				continue
+			}
+			streams[fp] = stream
+		}
+		from = to
+	}
+
+	resp := summarizeGaps(slices.Collect(maps.Values(streams)), start, end, resolution)
+	resp.Selector = selector
+	if truncationLimit > 0 {
+		for i := range resp.Series {
+			if len(resp.Series[i].Gaps) > truncationLimit {
+				resp.Series[i].Gaps = resp.Series[i].Gaps[:truncationLimit]
+				resp.Series[i].Truncated = strings.TrimSpace(displayTruncationWarning(truncationLimit))
+			}
+		}
+		if len(resp.Series) > truncationLimit {
+			resp.Series = resp.Series[:truncationLimit]
+			resp.Truncated = strings.TrimSpace(displayTruncationWarning(truncationLimit))
+		}
+	}
+
+	return s.FormatOutput(resp)
+}
+
+// summarizeGaps finds the gaps in the raw samples of each series between
+// start and end. Only series with gaps are listed, the ones missing the most
+// samples first.
+func summarizeGaps(matrix model.Matrix, start, end time.Time, resolution time.Duration) detectGapsResponse {
+	resp := detectGapsResponse{
+		Resolution:  model.Duration(resolution).String(),
+		Start:       start.UTC(),
+		End:         end.UTC(),
+		SeriesCount: len(matrix),
+		Series:      []detectGapsSeries{},
+	}
+	first := model.TimeFromUnixNano(start.UnixNano())
+	last := model.TimeFromUnixNano(end.UnixNano())
+	for _, stream := range matrix {
+		timestamps := make([]model.Time, 0, len(stream.Values)+len(stream.Histograms))
+		for _, v := range stream.Values {
+			timestamps = append(timestamps, v.Timestamp)
+		}
+		for _, h := range stream.Histograms {
+			timestamps = append(timestamps, h.Timestamp)
+		}
+		slices.Sort(timestamps)
+		timestamps = slices.Compact(timestamps)
+
+		gaps := findGaps(timestamps, first, last, resolution)
+		if len(gaps) == 0 {
+			continue
+		}
+
+		series := detectGapsSeries{
+			Labels:   stream.Metric.String(),
+			Samples:  len(timestamps),
+			GapCount: len(gaps),
+			Gaps:     gaps,
+		}
+		for _, gap := range gaps {
+			series.MissingSamples += gap.MissingSamples
+		}
+		resp.Series = append(resp.Series, series)
+	}
+	resp.SeriesWithGaps = len(resp.Series)
+
+	switch {
+	case resp.SeriesCount == 0:
+		resp.Message = "no series matched the selector in the time range"
+	case resp.SeriesWithGaps == 0:
+		resp.Message = fmt.Sprintf("no gaps found, all %d series have a sample at every interval", resp.SeriesCount)
+	}
+
+	sort.Slice(resp.Series, func(i, j int) bool {
+		a, b := resp.Series[i], resp.Series[j]
+		if a.MissingSamples != b.MissingSamples {
+			return a.MissingSamples > b.MissingSamples
+		}
+		return a.Labels < b.Labels
+	})
+
+	return resp
+}
+
+// findGaps returns the runs of expected samples missing from the sorted
+// timestamps between first and last, given the expected interval between
+// samples. Consecutive samples are allowed to be up to half an interval late,
+// so scrape jitter isn't reported. Each gap spans from its first to its last
+// missing sample.
+func findGaps(timestamps []model.Time, first, last model.Time, interval time.Duration) []seriesGap {
+	if len(timestamps) == 0 {
+		return nil
+	}
+
+	var gaps []seriesGap
+	addGap := func(from model.Time, missing int) {
+		gaps = append(gaps, seriesGap{
+			Start:          from.Time().UTC(),
+			End:            from.Add(time.Duration(missing-1) * interval).Time().UTC(),
+			Duration:       model.Duration(time.Duration(missing) * interval).String(),
+			MissingSamples: missing,
+		})
+	}
+
+	if missing := int(timestamps[0].Sub(first) / interval); missing > 0 {
+		addGap(timestamps[0].Add(-time.Duration(missing)*interval), missing)
+	}
+	for i := 1; i < len(timestamps); i++ {
+		prev := timestamps[i-1]
+		missing := int(math.Round(float64(timestamps[i].Sub(prev))/float64(interval))) - 1
+		if missing > 0 {
+			addGap(prev.Add(interval), missing)
+		}
+	}
+	lastTs := timestamps[len(timestamps)-1]
+	if missing := int(last.Sub(lastTs) / interval); missing > 0 {
+		addGap(lastTs.Add(interval), missing)
+	}
+
+	return gaps
+}
+
+// instantVectorAPICall runs an instant query that is expected to return a
+// vector, for tools that compose their output from query results.
+func (s *ServerContainer) instantVectorAPICall(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+	result, err := s.doAPICall(ctx, "/api/v1/query", "failed to execute instant query",
+		func(ctx context.Context, client promv1.API) (any, error) {
+			v, _, err := client.Query(ctx, query, ts)
+			return v, err
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	vector, ok := result.(model.Vector)
+	if !ok {
+		return nil, fmt.Errorf("unexpected result type %T for query %q", result, query)
+	}
+	return vector, nil
+}
+
+// parseScrapeSampleLimits returns the sample_limit of each scrape config by
+// job name, along with the global sample_limit that applies to scrape configs
+// that don't set their own. A limit of 0 means no limit.
+func parseScrapeSampleLimits(configYAML string) (map[string]int, int, error) {
+	var cfg struct {
+		Global struct {
+			SampleLimit int `yaml:"sample_limit"`
+		} `yaml:"global"`
+		ScrapeConfigs []struct {
+			JobName     string `yaml:"job_name"`
+			SampleLimit *int   `yaml:"sample_limit"`
+		} `yaml:"scrape_configs"`
+	}
+	if err := yaml.Unmarshal([]byte(configYAML), &cfg); err != nil {
+		return nil, 0, fmt.Errorf("failed to parse loaded config: %w", err)
+	}
+
+	limits := make(map[string]int, len(cfg.ScrapeConfigs))
+	for _, sc := range cfg.ScrapeConfigs {
+		if sc.SampleLimit != nil {
+			limits[sc.JobName] = *sc.SampleLimit
+		}
+	}
+	return limits, cfg.Global.SampleLimit, nil
+}
+
+// syntheticMetricNames are series that Prometheus generates itself rather than
+// scraping, so they never have metadata.
+var syntheticMetricNames = map[string]struct{}{
+	"up":                                    {},
+	"scrape_duration_seconds":               {},
+	"scrape_samples_scraped":                {},
+	"scrape_samples_post_metric_relabeling": {},
+	"scrape_series_added":                   {},
+	"scrape_timeout_seconds":                {},
+	"scrape_sample_limit":                   {},
+	"scrape_body_size_bytes":                {},
+	"ALERTS":                                {},
+	"ALERTS_FOR_STATE":                      {},
+}
+
+// metadataFamilySuffixes are the suffixes of series names that belong to a
+// metric family whose metadata is registered under the name without the
+// suffix, e.g. histogram buckets or OpenMetrics counters.
+var metadataFamilySuffixes = []string{"_bucket", "_count", "_sum", "_total", "_created", "_gcount", "_gsum"}
+
+func (s *ServerContainer) metricsMissingMetadataAPICall(ctx context.Context, start, end time.Time, truncationLimit int) (string, error) {
+	names, _, err := s.fetchLabelValues(ctx, model.MetricNameLabel, nil, start, end)
+	if err != nil {
+		return "", err
+	}
+
+	result, err := s.doAPICall(ctx, "/api/v1/metadata", "failed to get metric metadata from Prometheus",
+		func(ctx context.Context, client promv1.API) (any, error) {
+			return client.Metadata(ctx, "", "")
+		})
+	if err != nil {
+		return "", err
+	}
+	metadata, ok := result.(map[string][]promv1.Metadata)
+	if !ok {
+		return "", fmt.Errorf("unexpected metadata result type %T", result)
+	}
+
+	resp := metricsMissingMetadataResponse{
+		MetricsChecked:  len(names),
+		MissingMetadata: []string{},
+	}
+
+	// Recording rule outputs have no metadata either. Rule names are only
+	// available from the rules API, so fall back to the level:metric:operation
+	// naming convention if it can't be queried.
+	recordingRules := make(map[string]struct{})
+	result, err = s.doAPICall(ctx, "/api/v1/rules", "failed to get rules from Prometheus",
+		func(ctx context.Context, client promv1.API) (any, error) {
+			return client.Rules(ctx, nil)
+		})
+	if err != nil {
+		resp.Notes = append(resp.Notes, "Recording rules could not be listed, only metric names containing a colon were excluded as recording rule outputs: "+err.Error())
+	} else if rules, ok := result.(promv1.RulesResult); ok {
+		for _, group := range rules.Groups {
+			for _, rule := range group.Rules {
+				if rr, ok := rule.(promv1.RecordingRule); ok {
+					recordingRules[rr.Name] = struct{}{}
+				}
+			}
+		}
+	}
+
+	for _, name := range names {
+		if _, ok := recordingRules[name]; ok || strings.Contains(name, ":") {
+			resp.ExcludedRecordingRules++
+			continue
+		}
+		if _, ok := syntheticMetricNames[name]; ok {
+			resp.ExcludedSyntheticMetrics++
+			continue
+		}
+		if !hasMetricMetadata(metadata, name) {
+			resp.MissingMetadata = append(resp.MissingMetadata, name)
+		}
+	}
+
+	sort.Strings(resp.MissingMetadata)
+	resp.MissingMetadataCount = len(resp.MissingMetadata)
+	if truncationLimit > 0 && len(resp.MissingMetadata) > truncationLimit {
+		resp.MissingMetadata = resp.MissingMetadata[:truncationLimit]
+		resp.Truncated = strings.TrimSpace(displayTruncationWarning(truncationLimit))
+	}
+
+	return s.FormatOutput(resp)
+}
+
+// hasMetricMetadata reports whether metadata is registered for the metric
+// name, either directly or for the metric family it belongs to.
+func hasMetricMetadata(metadata map[string][]promv1.Metadata, name string) bool {
+	if _, ok := metadata[name]; ok {
+		return true
+	}
+	for _, suffix := range metadataFamilySuffixes {
+		if family, ok := strings.CutSuffix(name, suffix); ok {
+			if _, ok := metadata[family]; ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (s *ServerContainer) walReplayAPICall(ctx context.Context) (string, error) {
+	return s.doSimpleAPICall(ctx, "/api/v1/status/walreplay", "failed to get WAL replay status from Prometheus",
+		func(ctx context.Context, client promv1.API) (any, error) {
+			return client.WalReplay(ctx)
+		})
+}
+
+// Defaults and bounds of the wait_ready tool's timeout and poll interval.
+const (
+	defaultWaitReadyTimeout      = time.Minute
+	maxWaitReadyTimeout          = 10 * time.Minute
+	defaultWaitReadyPollInterval = 2 * time.Second
+	minWaitReadyPollInterval     = 100 * time.Millisecond
+)
+
+type waitReadyResponse struct {
+	Ready          bool                    `json:"ready"`
+	Status         string                  `json:"status"`
+	Attempts       int                     `json:"attempts"`
+	Elapsed        string                  `json:"elapsed"`
+	WALReplay      *promv1.WalReplayStatus `json:"wal_replay,omitempty"`
+	WALReplayError string                  `json:"wal_replay_error,omitempty"`
+	Message        string                  `json:"message,omitempty"`
+}
+
+// waitReadyAPICall polls the readiness endpoint every interval until
+// Prometheus is ready or the timeout elapses. Not being ready in time is
+// reported in the response, not as an error. Errors that waiting won't fix,
+// like a missing endpoint or rejected credentials, end the wait early.
+func (s *ServerContainer) waitReadyAPICall(ctx context.Context, timeout, interval time.Duration, includeWALReplay bool) (string, error) {
+	start := time.Now()
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var resp waitReadyResponse
+poll:
+	for {
+		resp.Attempts++
+		status, err := s.doManagementAPICall(waitCtx, http.MethodGet, mgmtAPIReadyEndpoint)
+		switch {
+		case ctx.Err() != nil:
+			return "", fmt.Errorf("wait cancelled: %w", ctx.Err())
+		case err == nil:
+			resp.Ready = true
+			resp.Status = status
+		case waitCtx.Err() != nil:
+			// The call was cut short by the timeout, keep the previous
+			// status which tells more than the context error.
+			if resp.Status == "" {
+				resp.Status = err.Error()
+			}
+		case !isRetryableAPIError(err):
+			return "", err
+		default:
+			resp.Status = err.Error()
+		}
+
+		if includeWALReplay && waitCtx.Err() == nil {
+			walReplay, err := s.doAPICall(waitCtx, "/api/v1/status/walreplay", "failed to get WAL replay status from Prometheus",
+				func(ctx context.Context, client promv1.API) (any, error) {
+					return client.WalReplay(ctx)
+				})
+			if err != nil {
+				resp.WALReplayError = err.Error()
+			} else {
+				status, ok := walReplay.(promv1.WalReplayStatus)
+				if !ok {
+					return "", fmt.Errorf("unexpected WAL replay status result type %T", walReplay)
+				}
+				resp.WALReplay = &status
+				resp.WALReplayError = ""
+			}
+		}
+
+		if resp.Ready {
+			break
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return "", fmt.Errorf("wait cancelled: %w", ctx.Err())
+		case <-waitCtx.Done():
+			timer.Stop()
+			resp.Message = fmt.Sprintf("Prometheus did not become ready within %s.", model.Duration(timeout))
+			break poll
+		case <-timer.C:
+		}
 	}
 
-	return s.formatTruncatedQueryAPIResponse(result.String(), warnings, truncationLimit)
+	resp.Elapsed = time.Since(start).Round(time.Millisecond).String()
+	return s.FormatOutput(resp)
 }
 
-func (s *ServerContainer) rangeQueryAPICall(ctx context.Context, query string, start, end time.Time, step time.Duration, truncationLimit int) (string, error) {
+func (s *ServerContainer) cleanTombstonesAPICall(ctx context.Context) (string, error) {
 	client, _ := s.GetAPIClient(ctx)
-	ctx, cancel := context.WithTimeout(ctx, s.apiTimeout)
+	ctx, cancel := context.WithTimeout(ctx, s.getAPITimeout(ctx))
 	defer cancel()
 
-	path := "/api/v1/query_range"
+	path := "/api/v1/admin/tsdb/clean_tombstones"
 	startTs := time.Now()
-	result, warnings, err := client.QueryRange(ctx, query, promv1.Range{Start: start, End: end, Step: step})
+	err := client.CleanTombstones(ctx)
 	metricAPICallDuration.With(prometheus.Labels{"target_path": path}).Observe(time.Since(startTs).Seconds())
 	if err != nil {
 		metricAPICallsFailed.With(prometheus.Labels{"target_path": path}).Inc()
-		return "", fmt.Errorf("failed to execute range query: %w", wrapErrorIfNotFound(err, path))
+		return "", fmt.Errorf("failed to clean tombstones from Prometheus: %w", wrapClientError(err, path))
 	}
+	s.recordAPICallSuccess(ctx)
 
-	return s.formatTruncatedQueryAPIResponse(result.String(), warnings, truncationLimit)
+	return s.FormatOutput("success")
 }
 
-func (s *ServerContainer) exemplarQueryAPICall(ctx context.Context, query string, start, end time.Time, truncationLimit int) (string, error) {
+func (s *ServerContainer) deleteSeriesAPICall(ctx context.Context, matches []string, start, end time.Time) (string, error) {
 	client, _ := s.GetAPIClient(ctx)
-	ctx, cancel := context.WithTimeout(ctx, s.apiTimeout)
+	ctx, cancel := context.WithTimeout(ctx, s.getAPITimeout(ctx))
 	defer cancel()
 
-	path := "/api/v1/query_exemplars"
+	path := "/api/v1/admin/tsdb/delete_series"
 	startTs := time.Now()
-	res, err := client.QueryExemplars(ctx, query, start, end)
+	err := client.DeleteSeries(ctx, matches, start, end)
 	metricAPICallDuration.With(prometheus.Labels{"target_path": path}).Observe(time.Since(startTs).Seconds())
 	if err != nil {
 		metricAPICallsFailed.With(prometheus.Labels{"target_path": path}).Inc()
-		return "", fmt.Errorf("failed to execute exemplar query: %w", wrapErrorIfNotFound(err, path))
+		return "", fmt.Errorf("failed to delete series from Prometheus: %w", wrapClientError(err, path))
 	}
+	s.recordAPICallSuccess(ctx)
 
-	var resultSB strings.Builder
-	for _, r := range res {
-		b, err := json.Marshal(r)
-		if err != nil {
-			return "", fmt.Errorf("failed to marshal exemplar: %w", err)
-		}
-		resultSB.Write(b)
-		resultSB.WriteString("\n")
-	}
-	return s.formatTruncatedQueryAPIResponse(resultSB.String(), nil, truncationLimit)
+	return "success", nil
 }
 
-func (s *ServerContainer) seriesAPICall(ctx context.Context, matches []string, start, end time.Time, truncationLimit int) (string, error) {
+func (s *ServerContainer) snapshotAPICall(ctx context.Context, skipHead bool) (string, error) {
 	client, _ := s.GetAPIClient(ctx)
-	ctx, cancel := context.WithTimeout(ctx, s.apiTimeout)
+	ctx, cancel := context.WithTimeout(ctx, s.getAPITimeout(ctx))
 	defer cancel()
 
-	path := "/api/v1/series"
+	path := "/api/v1/admin/tsdb/snapshot"
 	startTs := time.Now()
-	result, warnings, err := client.Series(ctx, matches, start, end)
+	ss, err := client.Snapshot(ctx, skipHead)
 	metricAPICallDuration.With(prometheus.Labels{"target_path": path}).Observe(time.Since(startTs).Seconds())
 	if err != nil {
 		metricAPICallsFailed.With(prometheus.Labels{"target_path": path}).Inc()
-		return "", fmt.Errorf("failed to get series: %w", wrapErrorIfNotFound(err, path))
+		return "", fmt.Errorf("failed to create Prometheus snapshot: %w", wrapClientError(err, path))
 	}
+	s.recordAPICallSuccess(ctx)
 
-	lsets := make([]string, len(result))
-	for i, lset := range result {
-		lsets[i] = lset.String()
+	resp := snapshotResponse{Name: ss.Name}
+	tsdbPath, err := s.tsdbPath(ctx)
+	if err != nil {
+		s.logger.Debug("Failed to get TSDB path for snapshot directory", "err", err)
+	} else if tsdbPath != "" {
+		resp.Path = snapshotDir(tsdbPath, ss.Name)
+		if !strings.HasPrefix(resp.Path, "/") {
+			resp.Message = "The TSDB path is relative to the working directory of Prometheus."
+		}
 	}
 
-	return s.formatTruncatedQueryAPIResponse(strings.Join(lsets, "\n"), warnings, truncationLimit)
+	return s.FormatOutput(resp)
 }
 
-func (s *ServerContainer) labelNamesAPICall(ctx context.Context, matches []string, start, end time.Time, truncationLimit int) (string, error) {
-	client, _ := s.GetAPIClient(ctx)
-	ctx, cancel := context.WithTimeout(ctx, s.apiTimeout)
-	defer cancel()
+// snapshotResponse is the response structure for the snapshot tool.
+type snapshotResponse struct {
+	Name    string `json:"name"`
+	Path    string `json:"path,omitempty"`
+	Message string `json:"message,omitempty"`
+}
 
-	path := "/api/v1/labels"
-	startTs := time.Now()
-	result, warnings, err := client.LabelNames(ctx, matches, start, end)
-	metricAPICallDuration.With(prometheus.Labels{"target_path": path}).Observe(time.Since(startTs).Seconds())
+// tsdbPath returns the TSDB data directory of Prometheus from its
+// `storage.tsdb.path` flag.
+func (s *ServerContainer) tsdbPath(ctx context.Context) (string, error) {
+	result, err := s.doAPICall(ctx, "/api/v1/status/flags", "failed to get runtime flags from Prometheus",
+		func(ctx context.Context, client promv1.API) (any, error) {
+			return client.Flags(ctx)
+		})
 	if err != nil {
-		metricAPICallsFailed.With(prometheus.Labels{"target_path": path}).Inc()
-		return "", fmt.Errorf("failed to get label names: %w", wrapErrorIfNotFound(err, path))
+		return "", err
 	}
 
-	return s.formatTruncatedQueryAPIResponse(strings.Join(result, "\n"), warnings, truncationLimit)
+	flags, ok := result.(promv1.FlagsResult)
+	if !ok {
+		return "", fmt.Errorf("unexpected flags result type %T", result)
+	}
+	return flags["storage.tsdb.path"], nil
 }
 
-func (s *ServerContainer) labelValuesAPICall(ctx context.Context, label string, matches []string, start, end time.Time, truncationLimit int) (string, error) {
-	client, _ := s.GetAPIClient(ctx)
-	ctx, cancel := context.WithTimeout(ctx, s.apiTimeout)
-	defer cancel()
+// snapshotDir returns the directory Prometheus creates a snapshot in, under
+// the `snapshots` directory of the TSDB. Paths on the Prometheus host are
+// always joined with slashes, independent of the OS of the MCP server.
+func snapshotDir(tsdbPath, name string) string {
+	return path.Join(tsdbPath, "snapshots", name)
+}
 
-	path := "/api/v1/label/:name/values"
-	startTs := time.Now()
-	result, warnings, err := client.LabelValues(ctx, label, matches, start, end)
-	metricAPICallDuration.With(prometheus.Labels{"target_path": path}).Observe(time.Since(startTs).Seconds())
-	if err != nil {
-		metricAPICallsFailed.With(prometheus.Labels{"target_path": path}).Inc()
-		return "", fmt.Errorf("failed to get label values: %w", wrapErrorIfNotFound(err, path))
+// mimirTenantsURL returns the URL of the endpoint listing tenants. An absolute
+// path is resolved against the root of the Prometheus URL, so Mimir's
+// /prometheus API prefix is dropped, and a full URL is used as is.
+func (s *ServerContainer) mimirTenantsURL(ctx context.Context) (*url.URL, error) {
+	tenantsPath := s.mimirTenantsPath
+	if tenantsPath == "" {
+		tenantsPath = DefaultMimirTenantsPath
 	}
 
-	lvals := make([]string, len(result))
-	for i, lval := range result {
-		lvals[i] = string(lval)
+	base, err := url.Parse(s.getPrometheusURL(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Prometheus URL: %w", err)
 	}
-
-	return s.formatTruncatedQueryAPIResponse(strings.Join(lvals, "\n"), warnings, truncationLimit)
+	ref, err := url.Parse(tenantsPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse tenants path %q: %w", tenantsPath, err)
+	}
+	return base.ResolveReference(ref), nil
 }
 
-func (s *ServerContainer) metricMetadataAPICall(ctx context.Context, metric, limit string) (string, error) {
-	client, _ := s.GetAPIClient(ctx)
-	ctx, cancel := context.WithTimeout(ctx, s.apiTimeout)
+func (s *ServerContainer) listTenantsAPICall(ctx context.Context) (string, error) {
+	_, rt := s.GetAPIClient(ctx)
+	ctx, cancel := context.WithTimeout(ctx, s.getAPITimeout(ctx))
 	defer cancel()
 
-	path := "/api/v1/metadata"
-	startTs := time.Now()
-
-	truncationLimit := s.truncationLimit
-	if limit == "" && truncationLimit != 0 {
-		limit = strconv.Itoa(truncationLimit)
-	}
-
-	limitInt := 0
-	if limit != "" {
-		n, err := strconv.Atoi(limit)
-		if err != nil {
-			return "", fmt.Errorf("failed to convert limit to int: %w", err)
-		}
-		if n < 0 {
-			// In the `truncation_limit` field of other tools, `-1`
-			// means unlimited. If we're given a negative number
-			// here, normalize it to 0 for the prom API call to
-			// treat it as unlimited.
-			n = 0
-		}
-		limitInt = n
-	}
-
-	if limitInt == 0 {
-		limit = ""
+	endpoint, err := s.mimirTenantsURL(ctx)
+	if err != nil {
+		return "", err
 	}
 
-	mm, err := client.Metadata(ctx, metric, limit)
-	metricAPICallDuration.With(prometheus.Labels{"target_path": path}).Observe(time.Since(startTs).Seconds())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint.String(), nil)
 	if err != nil {
-		metricAPICallsFailed.With(prometheus.Labels{"target_path": path}).Inc()
-		return "", fmt.Errorf("failed to get metric metadata from Prometheus: %w", wrapErrorIfNotFound(err, path))
+		return "", fmt.Errorf("failed to create HTTP request: %w", err)
 	}
+	// Mimir and Cortex render HTML pages unless JSON is requested.
+	req.Header.Set("Accept", "application/json")
 
-	encodedData, err := s.FormatOutput(mm)
+	body, err := s.fetchHTTPResponseBody(req, rt, s.getPrometheusURL(ctx), endpoint.Path)
 	if err != nil {
-		return "", fmt.Errorf("failed to encode metric metadata: %w", err)
+		return "", s.withUnsupportedBackend(err)
 	}
 
-	if limitInt != 0 {
-		encodedData += displayTruncationWarning(limitInt)
+	tenants, err := parseTenantIDs(body)
+	if err != nil {
+		return "", err
 	}
 
-	return encodedData, nil
+	return s.FormatOutput(listTenantsResponse{
+		Endpoint: endpoint.Path,
+		Count:    len(tenants),
+		Tenants:  tenants,
+	})
 }
 
-func (s *ServerContainer) targetsMetadataAPICall(ctx context.Context, matchTarget, metric, limit string) (string, error) {
-	client, _ := s.GetAPIClient(ctx)
-	ctx, cancel := context.WithTimeout(ctx, s.apiTimeout)
-	defer cancel()
-
-	path := "/api/v1/targets/metadata"
-	startTs := time.Now()
+// parseTenantIDs returns the sorted tenant IDs of a tenant listing. It accepts
+// the user stats of Mimir and Cortex distributors, a list of objects with a
+// userID field, and the tenant list of the Grafana Enterprise Metrics admin
+// API, an object with items that have a name field.
+func parseTenantIDs(body []byte) ([]string, error) {
+	var ids []string
 
-	truncationLimit := s.truncationLimit
-	if limit == "" && truncationLimit != 0 {
-		limit = strconv.Itoa(truncationLimit)
+	var userStats []struct {
+		UserID string `json:"userID"`
 	}
-
-	limitInt := 0
-	if limit != "" {
-		n, err := strconv.Atoi(limit)
-		if err != nil {
-			return "", fmt.Errorf("failed to convert limit to int: %w", err)
+	var adminTenants struct {
+		Items []struct {
+			Name string `json:"name"`
+		} `json:"items"`
+	}
+	switch {
+	case json.Unmarshal(body, &userStats) == nil:
+		for _, stats := range userStats {
+			ids = append(ids, stats.UserID)
 		}
-		if n < 0 {
-			// In the `truncation_limit` field of other tools, `-1`
-			// means unlimited. If we're given a negative number
-			// here, normalize it to 0 for the prom API call to
-			// treat it as unlimited.
-			n = 0
+	case json.Unmarshal(body, &adminTenants) == nil && adminTenants.Items != nil:
+		for _, item := range adminTenants.Items {
+			ids = append(ids, item.Name)
 		}
-		limitInt = n
+	default:
+		return nil, errors.New("unrecognized response, expected the JSON user stats of a Mimir or Cortex distributor or the tenant list of the Grafana Enterprise Metrics admin API. Check --mimir.tenants-path")
 	}
 
-	if limitInt == 0 {
-		limit = ""
-	}
+	ids = slices.DeleteFunc(ids, func(id string) bool { return id == "" })
+	slices.Sort(ids)
+	return slices.Compact(ids), nil
+}
 
-	tm, err := client.TargetsMetadata(ctx, matchTarget, metric, limit)
-	metricAPICallDuration.With(prometheus.Labels{"target_path": path}).Observe(time.Since(startTs).Seconds())
-	if err != nil {
-		metricAPICallsFailed.With(prometheus.Labels{"target_path": path}).Inc()
-		return "", fmt.Errorf("failed to get target metadata from Prometheus: %w", wrapErrorIfNotFound(err, path))
-	}
+func (s *ServerContainer) vmCardinalityAPICall(ctx context.Context, topN int, date, match, focusLabel string) (string, error) {
+	_, rt := s.GetAPIClient(ctx)
+	ctx, cancel := context.WithTimeout(ctx, s.getAPITimeout(ctx))
+	defer cancel()
 
-	encodedData, err := s.FormatOutput(tm)
+	path := "/api/v1/status/tsdb"
+	fullPath, err := url.JoinPath(s.getPrometheusURL(ctx), path)
 	if err != nil {
-		return "", fmt.Errorf("failed to encode target metadata: %w", err)
+		return "", fmt.Errorf("failed to construct URL for request: %w", err)
 	}
-
-	if limitInt != 0 {
-		encodedData += displayTruncationWarning(limitInt)
+	query := url.Values{"topN": []string{strconv.Itoa(topN)}}
+	if date != "" {
+		query.Set("date", date)
+	}
+	if match != "" {
+		query.Set("match[]", match)
+	}
+	if focusLabel != "" {
+		query.Set("focusLabel", focusLabel)
 	}
 
-	return encodedData, nil
-}
-
-// doSimpleAPICall encapsulates the common pattern for Prometheus API calls that
-// take no parameters beyond context: get client, set timeout, record metrics,
-// call the API, and format the result.
-func (s *ServerContainer) doSimpleAPICall(ctx context.Context, path, errMsg string, call func(context.Context, promv1.API) (any, error)) (string, error) {
-	client, _ := s.GetAPIClient(ctx)
-	ctx, cancel := context.WithTimeout(ctx, s.apiTimeout)
-	defer cancel()
-
-	startTs := time.Now()
-	result, err := call(ctx, client)
-	metricAPICallDuration.With(prometheus.Labels{"target_path": path}).Observe(time.Since(startTs).Seconds())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fullPath+"?"+query.Encode(), nil)
 	if err != nil {
-		metricAPICallsFailed.With(prometheus.Labels{"target_path": path}).Inc()
-		return "", fmt.Errorf("%s: %w", errMsg, wrapErrorIfNotFound(err, path))
+		return "", fmt.Errorf("failed to create HTTP request: %w", err)
 	}
+	req.Header.Set("Accept", "application/json")
 
-	return s.FormatOutput(result)
-}
-
-func (s *ServerContainer) alertmanagersAPICall(ctx context.Context) (string, error) {
-	return s.doSimpleAPICall(ctx, "/api/v1/alertmanagers", "failed to get alertmanager status from Prometheus",
-		func(ctx context.Context, client promv1.API) (any, error) {
-			return client.AlertManagers(ctx)
-		})
-}
-
-func (s *ServerContainer) flagsAPICall(ctx context.Context) (string, error) {
-	return s.doSimpleAPICall(ctx, "/api/v1/status/flags", "failed to get runtime flags from Prometheus",
-		func(ctx context.Context, client promv1.API) (any, error) {
-			return client.Flags(ctx)
-		})
-}
-
-func (s *ServerContainer) listAlertsAPICall(ctx context.Context) (string, error) {
-	return s.doSimpleAPICall(ctx, "/api/v1/alerts", "failed to get alerts from Prometheus",
-		func(ctx context.Context, client promv1.API) (any, error) {
-			return client.Alerts(ctx)
-		})
-}
-
-func (s *ServerContainer) tsdbStatsAPICall(ctx context.Context) (string, error) {
-	return s.doSimpleAPICall(ctx, "/api/v1/status/tsdb", "failed to get tsdb stats from Prometheus",
-		func(ctx context.Context, client promv1.API) (any, error) {
-			return client.TSDB(ctx)
-		})
-}
-
-func (s *ServerContainer) buildinfoAPICall(ctx context.Context) (string, error) {
-	return s.doSimpleAPICall(ctx, "/api/v1/status/buildinfo", "failed to get build info from Prometheus",
-		func(ctx context.Context, client promv1.API) (any, error) {
-			return client.Buildinfo(ctx)
-		})
-}
-
-func (s *ServerContainer) configAPICall(ctx context.Context) (string, error) {
-	return s.doSimpleAPICall(ctx, "/api/v1/status/config", "failed to get configuration from Prometheus",
-		func(ctx context.Context, client promv1.API) (any, error) {
-			return client.Config(ctx)
-		})
-}
-
-func (s *ServerContainer) runtimeinfoAPICall(ctx context.Context) (string, error) {
-	return s.doSimpleAPICall(ctx, "/api/v1/status/runtimeinfo", "failed to get runtime info from Prometheus",
-		func(ctx context.Context, client promv1.API) (any, error) {
-			return client.Runtimeinfo(ctx)
-		})
-}
-
-func (s *ServerContainer) rulesAPICall(ctx context.Context) (string, error) {
-	return s.doSimpleAPICall(ctx, "/api/v1/rules", "failed to get rules from Prometheus",
-		func(ctx context.Context, client promv1.API) (any, error) {
-			return client.Rules(ctx)
-		})
-}
-
-func (s *ServerContainer) targetsAPICall(ctx context.Context) (string, error) {
-	return s.doSimpleAPICall(ctx, "/api/v1/targets", "failed to get targets from Prometheus",
-		func(ctx context.Context, client promv1.API) (any, error) {
-			return client.Targets(ctx)
-		})
-}
-
-func (s *ServerContainer) walReplayAPICall(ctx context.Context) (string, error) {
-	return s.doSimpleAPICall(ctx, "/api/v1/status/walreplay", "failed to get WAL replay status from Prometheus",
-		func(ctx context.Context, client promv1.API) (any, error) {
-			return client.WalReplay(ctx)
-		})
-}
-
-func (s *ServerContainer) cleanTombstonesAPICall(ctx context.Context) (string, error) {
-	return s.doSimpleAPICall(ctx, "/api/v1/admin/tsdb/clean_tombstones", "failed to clean tombstones from Prometheus",
-		func(ctx context.Context, client promv1.API) (any, error) {
-			return "success", client.CleanTombstones(ctx)
-		})
-}
+	body, err := s.fetchHTTPResponseBody(req, rt, s.getPrometheusURL(ctx), path)
+	if err != nil {
+		return "", s.withUnsupportedBackend(err)
+	}
 
-func (s *ServerContainer) deleteSeriesAPICall(ctx context.Context, matches []string, start, end time.Time) (string, error) {
-	client, _ := s.GetAPIClient(ctx)
-	ctx, cancel := context.WithTimeout(ctx, s.apiTimeout)
-	defer cancel()
+	var status struct {
+		Data vmTSDBStatus `json:"data"`
+	}
+	if err := json.Unmarshal(body, &status); err != nil {
+		return "", fmt.Errorf("failed to unmarshal JSON response: %w", err)
+	}
+	if status.Data.TotalSeries == nil {
+		return "", errors.New("the response is missing VictoriaMetrics' totalSeries field, the backend does not look like VictoriaMetrics. Use the tsdb_stats tool for Prometheus")
+	}
 
-	path := "/api/v1/admin/tsdb/delete_series"
-	startTs := time.Now()
-	err := client.DeleteSeries(ctx, matches, start, end)
-	metricAPICallDuration.With(prometheus.Labels{"target_path": path}).Observe(time.Since(startTs).Seconds())
-	if err != nil {
-		metricAPICallsFailed.With(prometheus.Labels{"target_path": path}).Inc()
-		return "", fmt.Errorf("failed to delete series from Prometheus: %w", wrapErrorIfNotFound(err, path))
+	resp := vmCardinalityResponse{
+		Date:                         date,
+		Match:                        match,
+		FocusLabel:                   focusLabel,
+		TotalSeries:                  *status.Data.TotalSeries,
+		TotalLabelValuePairs:         status.Data.TotalLabelValuePairs,
+		SeriesCountByMetricName:      vmCardinalityEntries(status.Data.SeriesCountByMetricName),
+		SeriesCountByLabelName:       vmCardinalityEntries(status.Data.SeriesCountByLabelName),
+		SeriesCountByFocusLabelValue: vmCardinalityEntries(status.Data.SeriesCountByFocusLabelValue),
+		SeriesCountByLabelValuePair:  vmCardinalityEntries(status.Data.SeriesCountByLabelValuePair),
+		LabelValueCountByLabelName:   vmCardinalityEntries(status.Data.LabelValueCountByLabelName),
 	}
 
-	return "success", nil
+	return s.FormatOutput(resp)
 }
 
-func (s *ServerContainer) snapshotAPICall(ctx context.Context, skipHead bool) (string, error) {
-	client, _ := s.GetAPIClient(ctx)
-	ctx, cancel := context.WithTimeout(ctx, s.apiTimeout)
-	defer cancel()
-
-	path := "/api/v1/admin/tsdb/snapshot"
-	startTs := time.Now()
-	ss, err := client.Snapshot(ctx, skipHead)
-	metricAPICallDuration.With(prometheus.Labels{"target_path": path}).Observe(time.Since(startTs).Seconds())
-	if err != nil {
-		metricAPICallsFailed.With(prometheus.Labels{"target_path": path}).Inc()
-		return "", fmt.Errorf("failed to create Prometheus snapshot: %w", wrapErrorIfNotFound(err, path))
+// vmCardinalityEntries converts entries of the VictoriaMetrics TSDB status,
+// converting the last request Unix timestamp to a time.
+func vmCardinalityEntries(entries []vmTSDBStatusEntry) []vmCardinalityEntry {
+	result := make([]vmCardinalityEntry, 0, len(entries))
+	for _, e := range entries {
+		entry := vmCardinalityEntry{Name: e.Name, Count: e.Value, RequestsCount: e.RequestsCount}
+		if e.LastRequestTimestamp > 0 {
+			lastRequest := time.Unix(e.LastRequestTimestamp, 0).UTC()
+			entry.LastRequest = &lastRequest
+		}
+		result = append(result, entry)
 	}
-
-	return s.FormatOutput(ss)
+	return result
 }
 
 func (s *ServerContainer) doManagementAPICall(ctx context.Context, method, path string) (string, error) {
 	_, rt := s.GetAPIClient(ctx)
-	ctx, cancel := context.WithTimeout(ctx, s.apiTimeout)
+	ctx, cancel := context.WithTimeout(ctx, s.getAPITimeout(ctx))
 	defer cancel()
 
 	data, err := s.doHTTPRequest(ctx, method, rt, path, false)
@@ -1008,7 +5536,7 @@ func (s *ServerContainer) doManagementAPICall(ctx context.Context, method, path
 
 // doHTTPRequest makes an HTTP request using the provided round tripper.
 func (s *ServerContainer) doHTTPRequest(ctx context.Context, method string, rt http.RoundTripper, requestPath string, expectJSON bool) (string, error) {
-	fullPath, err := url.JoinPath(s.prometheusURL, requestPath)
+	fullPath, err := url.JoinPath(s.getPrometheusURL(ctx), requestPath)
 	if err != nil {
 		return "", fmt.Errorf("failed to construct URL for request: %w", err)
 	}
@@ -1019,6 +5547,33 @@ func (s *ServerContainer) doHTTPRequest(ctx context.Context, method string, rt h
 	}
 	req.Header.Set("Accept", "application/json")
 
+	body, err := s.fetchHTTPResponseBody(req, rt, s.getPrometheusURL(ctx), requestPath)
+	if err != nil {
+		return "", s.withUnsupportedBackend(err)
+	}
+	return s.formatHTTPResponseBody(body, expectJSON)
+}
+
+// sendHTTPRequest sends the request using the provided round tripper, records
+// API call telemetry for the backend under metricPath, and formats the
+// response body.
+func (s *ServerContainer) sendHTTPRequest(req *http.Request, rt http.RoundTripper, backend, metricPath string, expectJSON bool) (string, error) {
+	body, err := s.fetchHTTPResponseBody(req, rt, backend, metricPath)
+	if err != nil {
+		return "", err
+	}
+	return s.formatHTTPResponseBody(body, expectJSON)
+}
+
+// fetchHTTPResponseBody sends the request using the provided round tripper,
+// records API call telemetry for the backend under metricPath, and returns the
+// raw response body. Requests to hosts other than a backend pass an empty
+// backend, so they don't count as successful backend calls.
+//
+// GET requests are retried on transient errors. Other requests may change
+// state, e.g. reloading the config or creating a silence, so they are sent
+// only once.
+func (s *ServerContainer) fetchHTTPResponseBody(req *http.Request, rt http.RoundTripper, backend, metricPath string) ([]byte, error) {
 	// Reuse the cached client for the default transport to share its idle
 	// connection pool. For auth-overridden transports create a one-off client.
 	var httpClient *http.Client
@@ -1028,36 +5583,77 @@ func (s *ServerContainer) doHTTPRequest(ctx context.Context, method string, rt h
 		httpClient = &http.Client{Transport: rt}
 	}
 
+	if req.Method != http.MethodGet {
+		return sendHTTPRequestOnce(httpClient, req, backend, metricPath, s.maxResponseBytes)
+	}
+
+	var body []byte
+	err := s.retryAPICall(req.Context(), metricPath, func() error {
+		var err error
+		body, err = sendHTTPRequestOnce(httpClient, req, backend, metricPath, s.maxResponseBytes)
+		return err
+	})
+	return body, err
+}
+
+// sendHTTPRequestOnce sends the request with the given client, records API
+// call telemetry for the backend under metricPath, and returns the raw
+// response body. If maxBytes is positive, bodies larger than maxBytes are
+// rejected with an error.
+func sendHTTPRequestOnce(httpClient *http.Client, req *http.Request, backend, metricPath string, maxBytes int64) ([]byte, error) {
 	startTs := time.Now()
 	resp, err := httpClient.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("failed to make HTTP request: %w", err)
+		return nil, fmt.Errorf("failed to make HTTP request: %w", err)
 	}
 	defer resp.Body.Close()
-	metricAPICallDuration.With(prometheus.Labels{"target_path": requestPath}).Observe(time.Since(startTs).Seconds())
+	metricAPICallDuration.With(prometheus.Labels{"target_path": metricPath}).Observe(time.Since(startTs).Seconds())
 
 	if resp.StatusCode != http.StatusOK {
-		metricAPICallsFailed.With(prometheus.Labels{"target_path": requestPath}).Inc()
-		if resp.StatusCode == http.StatusNotFound {
-			return "", &ErrEndpointNotSupported{
-				Endpoint:   requestPath,
+		metricAPICallsFailed.With(prometheus.Labels{"target_path": metricPath}).Inc()
+		switch resp.StatusCode {
+		case http.StatusNotFound:
+			return nil, &ErrEndpointNotSupported{
+				Endpoint:   metricPath,
 				StatusCode: resp.StatusCode,
 			}
+		case http.StatusUnauthorized, http.StatusForbidden:
+			// The body is only read for the reason, so errors are ignored.
+			body, _ := io.ReadAll(io.LimitReader(resp.Body, maxAccessDeniedReasonLen+1))
+			return nil, newErrAccessDenied(metricPath, resp.StatusCode, string(body), nil)
 		}
-		return "", fmt.Errorf("received non-ok HTTP status code: %d", resp.StatusCode)
+		return nil, &httpStatusError{StatusCode: resp.StatusCode}
+	}
+	if backend != "" {
+		metrics.RecordSuccessfulAPICall(redactURL(backend))
+	}
+
+	if maxBytes <= 0 {
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response body: %w", err)
+		}
+		return body, nil
 	}
 
-	// TODO(@tjhop): add an io.LimitReader and enforce max response body
-	// size? Should it be user configurable (flag)?
-	body, err := io.ReadAll(resp.Body)
+	// Read one byte past the limit to tell a body of exactly maxBytes apart
+	// from one that was cut off.
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBytes+1))
 	if err != nil {
-		return "", fmt.Errorf("failed to read response body: %w", err)
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	if int64(len(body)) > maxBytes {
+		return nil, fmt.Errorf("response body exceeds the maximum size of %d bytes set by --http.max-response-bytes", maxBytes)
 	}
+	return body, nil
+}
 
+// formatHTTPResponseBody formats a raw response body, decoding it as JSON
+// first if expectJSON is set.
+func (s *ServerContainer) formatHTTPResponseBody(body []byte, expectJSON bool) (string, error) {
 	var data any
 	if expectJSON {
-		err = json.Unmarshal(body, &data)
-		if err != nil {
+		if err := json.Unmarshal(body, &data); err != nil {
 			return "", fmt.Errorf("failed to unmarshal JSON response: %w", err)
 		}
 	} else {
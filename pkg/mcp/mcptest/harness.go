@@ -37,6 +37,14 @@ type TestServer struct {
 // The server and client are automatically connected and ready for use.
 func NewTestServer(t *testing.T) *TestServer {
 	t.Helper()
+	return NewTestServerWithClientOptions(t, nil)
+}
+
+// NewTestServerWithClientOptions creates a new test server like NewTestServer,
+// with the given options for the client, e.g. handlers for notifications sent
+// by the server.
+func NewTestServerWithClientOptions(t *testing.T, opts *mcp.ClientOptions) *TestServer {
+	t.Helper()
 
 	ctx, cancel := context.WithCancel(context.Background())
 
@@ -55,7 +63,7 @@ func NewTestServer(t *testing.T) *TestServer {
 	}
 
 	// Create and connect the client.
-	client := mcp.NewClient(impl, nil)
+	client := mcp.NewClient(impl, opts)
 	clientSession, err := client.Connect(ctx, clientTransport, nil)
 	if err != nil {
 		cancel()
@@ -93,6 +101,17 @@ func (ts *TestServer) CallTool(ctx context.Context, name string, args map[string
 	})
 }
 
+// CallToolWithProgressToken invokes a tool like CallTool, including the given
+// progress token in the request to ask for progress notifications.
+func (ts *TestServer) CallToolWithProgressToken(ctx context.Context, name string, args map[string]any, token any) (*mcp.CallToolResult, error) {
+	params := &mcp.CallToolParams{
+		Name:      name,
+		Arguments: args,
+	}
+	params.SetProgressToken(token)
+	return ts.session.CallTool(ctx, params)
+}
+
 // AddResource registers a static resource with the test server.
 func (ts *TestServer) AddResource(resource *mcp.Resource, handler func(context.Context, *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error)) {
 	ts.Server.AddResource(resource, handler)
@@ -109,6 +128,20 @@ func (ts *TestServer) ReadResource(ctx context.Context, uri string) (*mcp.ReadRe
 	return ts.session.ReadResource(ctx, &mcp.ReadResourceParams{URI: uri})
 }
 
+// AddPrompt registers a prompt with the test server.
+func (ts *TestServer) AddPrompt(prompt *mcp.Prompt, handler func(context.Context, *mcp.GetPromptRequest) (*mcp.GetPromptResult, error)) {
+	ts.Server.AddPrompt(prompt, handler)
+}
+
+// GetPrompt invokes a prompt by name with the given arguments through the MCP
+// protocol. Returns the prompt result or an error.
+func (ts *TestServer) GetPrompt(ctx context.Context, name string, args map[string]string) (*mcp.GetPromptResult, error) {
+	return ts.session.GetPrompt(ctx, &mcp.GetPromptParams{
+		Name:      name,
+		Arguments: args,
+	})
+}
+
 // ListTools returns the tools registered on the server as seen through the
 // MCP protocol. The returned schemas include any auto-generated fields
 // (e.g., InputSchema) that the SDK produces during registration.
@@ -116,6 +149,12 @@ func (ts *TestServer) ListTools(ctx context.Context) (*mcp.ListToolsResult, erro
 	return ts.session.ListTools(ctx, &mcp.ListToolsParams{})
 }
 
+// ListResources returns the resources registered on the server as seen
+// through the MCP protocol.
+func (ts *TestServer) ListResources(ctx context.Context) (*mcp.ListResourcesResult, error) {
+	return ts.session.ListResources(ctx, &mcp.ListResourcesParams{})
+}
+
 // Close shuts down the test server and releases resources.
 // This is automatically called via t.Cleanup(), but can be called
 // manually if needed.
@@ -165,3 +204,18 @@ func GetResourceText(result *mcp.ReadResourceResult) string {
 	}
 	return sb.String()
 }
+
+// GetPromptText extracts text content from a GetPromptResult.
+// It concatenates the text content of all messages in the result.
+func GetPromptText(result *mcp.GetPromptResult) string {
+	if result == nil {
+		return ""
+	}
+	var sb strings.Builder
+	for _, m := range result.Messages {
+		if content, ok := m.Content.(*mcp.TextContent); ok {
+			sb.WriteString(content.Text)
+		}
+	}
+	return sb.String()
+}
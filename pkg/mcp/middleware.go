@@ -14,8 +14,11 @@
 package mcp
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"log/slog"
+	"strings"
 	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
@@ -107,9 +110,11 @@ func telemetryHandleToolCall(ctx context.Context, method string, req mcp.Request
 
 	toolName := params.Name
 	args := params.Arguments
-	logger = logger.With("tool_name", toolName, "request_arguments", args)
+	logger = logger.With("tool_name", toolName, "request_arguments", redactToolArguments(args))
 
 	logger.Debug("Calling tool")
+	metricToolCalls.With(prometheus.Labels{"tool_name": toolName}).Inc()
+	ctx = addToolNameToContext(ctx, toolName)
 	startTime := time.Now()
 	result, err := next(ctx, method, req)
 	duration := time.Since(startTime)
@@ -122,14 +127,154 @@ func telemetryHandleToolCall(ctx context.Context, method string, req mcp.Request
 		logger.Error("Failed to convert result to call tool result")
 		return result, err
 	}
-	if err != nil || toolResult.IsError {
+	// The SDK returns a typed nil result along with an error for calls it
+	// rejects itself, such as calls to unknown tools.
+	if err != nil || toolResult == nil || toolResult.IsError {
 		metricToolCallsFailed.With(prometheus.Labels{"tool_name": toolName}).Inc()
 		logger.Error("Failed calling tool", "result", result, "error", err)
 	}
 
+	if toolResult != nil {
+		metricToolResponseBytes.With(prometheus.Labels{"tool_name": toolName}).Observe(float64(toolResultSize(toolResult)))
+	}
+
 	return result, err
 }
 
+// toolCallLoggingMiddleware creates an MCP middleware that logs every tool
+// call at info level with its redacted arguments, duration, and outcome, so
+// operators can audit what clients did. Unlike the debug logs of the
+// telemetry middleware, it doesn't require debug logging to be enabled.
+func toolCallLoggingMiddleware(logger *slog.Logger) mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			if method != methodToolsCall {
+				return next(ctx, method, req)
+			}
+			params, ok := req.GetParams().(*mcp.CallToolParamsRaw)
+			if !ok {
+				return next(ctx, method, req)
+			}
+
+			startTime := time.Now()
+			result, err := next(ctx, method, req)
+			duration := time.Since(startTime)
+
+			attrs := []any{
+				"tool_name", params.Name,
+				"arguments", redactToolArguments(params.Arguments),
+				"duration", duration,
+			}
+			toolResult, _ := result.(*mcp.CallToolResult)
+			switch {
+			case err != nil:
+				attrs = append(attrs, "status", "error", "error", err)
+			case toolResult == nil:
+				attrs = append(attrs, "status", "error")
+			case toolResult.IsError:
+				attrs = append(attrs, "status", "error", "error", toolResultErrorText(toolResult))
+			default:
+				attrs = append(attrs, "status", "success")
+			}
+			logger.Info("Tool call", attrs...)
+
+			return result, err
+		}
+	}
+}
+
+// toolResultErrorText returns the text of the first text content of a failed
+// tool result, which holds the error message.
+func toolResultErrorText(result *mcp.CallToolResult) string {
+	for _, c := range result.Content {
+		if content, ok := c.(*mcp.TextContent); ok {
+			return content.Text
+		}
+	}
+	return ""
+}
+
+// sensitiveArgumentNames are substrings of tool argument names, matched case
+// insensitively, whose values are redacted from logs. `confirm` holds the
+// confirmation token of destructive tools.
+var sensitiveArgumentNames = []string{"token", "password", "secret", "credential", "authorization", "api_key", "apikey", "confirm"}
+
+// redactedArgumentValue replaces the values of sensitive tool arguments in
+// logs.
+const redactedArgumentValue = "<redacted>"
+
+// redactToolArguments returns the JSON arguments of a tool call with the
+// values of sensitive arguments replaced, also in nested objects. Arguments
+// that can't be decoded as JSON are returned as is.
+func redactToolArguments(args json.RawMessage) json.RawMessage {
+	if len(args) == 0 {
+		return args
+	}
+	dec := json.NewDecoder(bytes.NewReader(args))
+	dec.UseNumber()
+	var decoded any
+	if err := dec.Decode(&decoded); err != nil {
+		return args
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(redactArgumentValues(decoded)); err != nil {
+		return args
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n"))
+}
+
+func redactArgumentValues(v any) any {
+	switch val := v.(type) {
+	case map[string]any:
+		for key, child := range val {
+			if isSensitiveArgumentName(key) {
+				val[key] = redactedArgumentValue
+				continue
+			}
+			val[key] = redactArgumentValues(child)
+		}
+	case []any:
+		for i, child := range val {
+			val[i] = redactArgumentValues(child)
+		}
+	}
+	return v
+}
+
+func isSensitiveArgumentName(name string) bool {
+	name = strings.ToLower(name)
+	for _, sensitive := range sensitiveArgumentNames {
+		if strings.Contains(name, sensitive) {
+			return true
+		}
+	}
+	return false
+}
+
+// toolResultSize returns the total size, in bytes, of the text content in a
+// tool result. This is the formatted payload that counts against the client's
+// context budget.
+func toolResultSize(result *mcp.CallToolResult) int {
+	if result == nil {
+		return 0
+	}
+	size := 0
+	for _, c := range result.Content {
+		switch content := c.(type) {
+		case *mcp.TextContent:
+			size += len(content.Text)
+		case *mcp.EmbeddedResource:
+			if content.Resource != nil {
+				size += len(content.Resource.Text)
+			}
+		}
+	}
+	return size
+}
+
 // telemetryHandleResourceRead instruments a resources/read request with metrics and logging.
 func telemetryHandleResourceRead(ctx context.Context, method string, req mcp.Request, next mcp.MethodHandler, logger *slog.Logger) (mcp.Result, error) {
 	params, ok := req.GetParams().(*mcp.ReadResourceParams)
@@ -14,6 +14,7 @@
 package mcp
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
@@ -22,13 +23,22 @@ import (
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/common/config"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/prometheus/prometheus-mcp/internal/metrics"
+	"github.com/prometheus/prometheus-mcp/pkg/mcp/mcptest"
+	mcpProm "github.com/prometheus/prometheus-mcp/pkg/prometheus"
 )
 
 // newTestLogger creates a slog.Logger backed by a bytes.Buffer for log
@@ -330,6 +340,18 @@ func TestTelemetryHandleToolCall(t *testing.T) {
 			wantLogged: "Failed to convert result to call tool result",
 			wantErr:    true,
 		},
+		{
+			name: "typed nil tool result logs failure without panic",
+			req: mockRequest(&mcp.CallToolParamsRaw{
+				Name:      "query",
+				Arguments: json.RawMessage(`{}`),
+			}),
+			// The SDK returns a typed nil result for unknown tools.
+			nextResult: (*mcp.CallToolResult)(nil),
+			nextErr:    errors.New("unknown tool"),
+			wantLogged: "Failed calling tool",
+			wantErr:    true,
+		},
 		{
 			name: "tool result with IsError true logs failure",
 			req: mockRequest(&mcp.CallToolParamsRaw{
@@ -388,6 +410,238 @@ func TestTelemetryHandleToolCall(t *testing.T) {
 	}
 }
 
+// toolResponseBytesHistogram returns the sample count and sum of the tool
+// response size histogram for the given tool, as gathered from the registry.
+func toolResponseBytesHistogram(t *testing.T, toolName string) (uint64, float64) {
+	t.Helper()
+
+	families, err := metrics.Registry.Gather()
+	require.NoError(t, err)
+
+	for _, mf := range families {
+		if mf.GetName() != "prom_mcp_tool_response_bytes" {
+			continue
+		}
+		for _, m := range mf.GetMetric() {
+			for _, lp := range m.GetLabel() {
+				if lp.GetName() == "tool_name" && lp.GetValue() == toolName {
+					return m.GetHistogram().GetSampleCount(), m.GetHistogram().GetSampleSum()
+				}
+			}
+		}
+	}
+	return 0, 0
+}
+
+// TestTelemetryHandleToolCall_ResponseBytes is intentionally not parallel so
+// that other tests observing the same tool's histogram can't race with the
+// before/after comparison.
+func TestTelemetryHandleToolCall_ResponseBytes(t *testing.T) {
+	logger, _ := newTestLogger()
+
+	const body = "query result data"
+	req := mockRequest(&mcp.CallToolParamsRaw{
+		Name:      "query",
+		Arguments: json.RawMessage(`{"query":"up"}`),
+	})
+	next := func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: body}},
+		}, nil
+	}
+
+	countBefore, sumBefore := toolResponseBytesHistogram(t, "query")
+
+	_, err := telemetryHandleToolCall(context.Background(), methodToolsCall, req, next, logger)
+	require.NoError(t, err)
+
+	countAfter, sumAfter := toolResponseBytesHistogram(t, "query")
+	require.Equal(t, countBefore+1, countAfter)
+	require.InDelta(t, float64(len(body)), sumAfter-sumBefore, 0)
+}
+
+func TestTelemetryHandleToolCall_CallsTotal(t *testing.T) {
+	logger, _ := newTestLogger()
+
+	const toolName = "calls_total_test_tool"
+	req := mockRequest(&mcp.CallToolParamsRaw{Name: toolName})
+	next := func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		return &mcp.CallToolResult{IsError: true}, nil
+	}
+
+	callsBefore := testutil.ToFloat64(metricToolCalls.WithLabelValues(toolName))
+	failedBefore := testutil.ToFloat64(metricToolCallsFailed.WithLabelValues(toolName))
+
+	for range 3 {
+		_, err := telemetryHandleToolCall(context.Background(), methodToolsCall, req, next, logger)
+		require.NoError(t, err)
+	}
+
+	require.InDelta(t, 3, testutil.ToFloat64(metricToolCalls.WithLabelValues(toolName))-callsBefore, 0)
+	require.InDelta(t, 3, testutil.ToFloat64(metricToolCallsFailed.WithLabelValues(toolName))-failedBefore, 0)
+}
+
+// TestTelemetryMiddleware_UnknownTool verifies that calls to unknown tools,
+// which the SDK rejects with a typed nil result, don't crash the server.
+func TestTelemetryMiddleware_UnknownTool(t *testing.T) {
+	logger, buf := newTestLogger()
+
+	ts := mcptest.NewTestServer(t)
+	ts.Server.AddReceivingMiddleware(telemetryMiddleware(logger))
+	mcptest.AddTool(ts, &mcp.Tool{Name: "known_tool_test"}, func(ctx context.Context, req *mcp.CallToolRequest, input EmptyInput) (*mcp.CallToolResult, any, error) {
+		return newToolTextResult("ok"), nil, nil
+	})
+
+	const toolName = "unknown_tool_test"
+	failedBefore := testutil.ToFloat64(metricToolCallsFailed.WithLabelValues(toolName))
+	countBefore, _ := toolResponseBytesHistogram(t, toolName)
+
+	_, err := ts.CallTool(ts.Context(), toolName, map[string]any{})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "unknown tool")
+
+	// The server still handles calls after the failed one.
+	result, err := ts.CallTool(ts.Context(), "known_tool_test", map[string]any{})
+	require.NoError(t, err)
+	require.False(t, result.IsError, mcptest.GetResultText(result))
+
+	require.InDelta(t, 1, testutil.ToFloat64(metricToolCallsFailed.WithLabelValues(toolName))-failedBefore, 0)
+	countAfter, _ := toolResponseBytesHistogram(t, toolName)
+	require.Equal(t, countBefore, countAfter)
+	require.Contains(t, buf.String(), "Failed calling tool")
+}
+
+func TestToolCallLoggingMiddleware(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name       string
+		method     string
+		req        mcp.Request
+		nextResult mcp.Result
+		nextErr    error
+		wantFields map[string]any // expected fields of the logged record, nil if nothing should be logged
+	}{
+		{
+			name:   "successful tool call",
+			method: methodToolsCall,
+			req: mockRequest(&mcp.CallToolParamsRaw{
+				Name:      "query",
+				Arguments: json.RawMessage(`{"query":"up"}`),
+			}),
+			nextResult: &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "ok"}}},
+			wantFields: map[string]any{
+				"msg":       "Tool call",
+				"level":     "INFO",
+				"tool_name": "query",
+				"arguments": map[string]any{"query": "up"},
+				"status":    "success",
+			},
+		},
+		{
+			name:   "failed tool call logs the error",
+			method: methodToolsCall,
+			req: mockRequest(&mcp.CallToolParamsRaw{
+				Name:      "query",
+				Arguments: json.RawMessage(`{"query":"up{"}`),
+			}),
+			nextResult: newToolErrorResult("parse error"),
+			wantFields: map[string]any{
+				"tool_name": "query",
+				"status":    "error",
+				"error":     "parse error",
+			},
+		},
+		{
+			name:   "sensitive arguments are redacted",
+			method: methodToolsCall,
+			req: mockRequest(&mcp.CallToolParamsRaw{
+				Name:      "create_silence",
+				Arguments: json.RawMessage(`{"comment":"maintenance","auth":{"Password":"hunter2","user":"admin"},"api_token":"abc"}`),
+			}),
+			nextResult: &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "ok"}}},
+			wantFields: map[string]any{
+				"arguments": map[string]any{
+					"comment":   "maintenance",
+					"auth":      map[string]any{"Password": redactedArgumentValue, "user": "admin"},
+					"api_token": redactedArgumentValue,
+				},
+			},
+		},
+		{
+			name:       "other methods are not logged",
+			method:     methodResourcesRead,
+			req:        mockRequest(&mcp.ReadResourceParams{URI: "prometheus://metrics"}),
+			nextResult: &mcp.ReadResourceResult{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			logger, buf := newTestLogger()
+			next := func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+				return tc.nextResult, tc.nextErr
+			}
+
+			result, err := toolCallLoggingMiddleware(logger)(next)(context.Background(), tc.method, tc.req)
+			require.NoError(t, err)
+			require.Equal(t, tc.nextResult, result)
+
+			if tc.wantFields == nil {
+				require.Empty(t, buf.String())
+				return
+			}
+			var record map[string]any
+			require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+			require.Contains(t, record, "duration")
+			for key, want := range tc.wantFields {
+				require.Equal(t, want, record[key], key)
+			}
+			require.NotContains(t, buf.String(), "hunter2")
+		})
+	}
+}
+
+func TestRedactToolArguments(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		args     string
+		expected string
+	}{
+		{
+			name:     "no sensitive arguments",
+			args:     `{"query":"up","limit":10}`,
+			expected: `{"limit":10,"query":"up"}`,
+		},
+		{
+			name:     "sensitive arguments in nested objects and lists",
+			args:     `{"bearer_token":"abc","targets":[{"name":"a","client_secret":"s"}]}`,
+			expected: `{"bearer_token":"<redacted>","targets":[{"client_secret":"<redacted>","name":"a"}]}`,
+		},
+		{
+			name:     "empty arguments",
+			args:     ``,
+			expected: ``,
+		},
+		{
+			name:     "invalid JSON is returned as is",
+			args:     `{not json`,
+			expected: `{not json`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tc.expected, string(redactToolArguments(json.RawMessage(tc.args))))
+		})
+	}
+}
+
 func TestTelemetryHandleResourceRead(t *testing.T) {
 	t.Parallel()
 
@@ -467,6 +721,46 @@ func TestTelemetryHandleResourceRead(t *testing.T) {
 	}
 }
 
+func TestNewSSEHandler(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "test"}, nil)
+	srv := httptest.NewServer(NewSSEHandler(ctx, server))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	// The first event tells the client where to post its messages.
+	reader := bufio.NewReader(resp.Body)
+	line, err := reader.ReadString('\n')
+	require.NoError(t, err)
+	require.Equal(t, "event: endpoint\n", line)
+	line, err = reader.ReadString('\n')
+	require.NoError(t, err)
+	require.Contains(t, line, "sessionid=")
+
+	// Canceling the context closes the session, ending the stream.
+	cancel()
+	done := make(chan error, 1)
+	go func() {
+		_, err := io.ReadAll(reader)
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("SSE stream was not closed after context cancellation")
+	}
+}
+
 // TestAuthContextMiddleware tests the HTTP middleware that extracts
 // Authorization headers and adds them to the request context.
 func TestAuthContextMiddleware(t *testing.T) {
@@ -603,6 +897,325 @@ func TestAuthContextMiddleware_RequestMethod(t *testing.T) {
 	}
 }
 
+func TestParseCORSOrigins(t *testing.T) {
+	t.Parallel()
+
+	origins, err := ParseCORSOrigins("")
+	require.NoError(t, err)
+	require.Empty(t, origins)
+
+	origins, err = ParseCORSOrigins("*")
+	require.NoError(t, err)
+	require.Equal(t, []string{"*"}, origins)
+
+	origins, err = ParseCORSOrigins(" https://example.com/ , http://localhost:6274,")
+	require.NoError(t, err)
+	require.Equal(t, []string{"https://example.com", "http://localhost:6274"}, origins)
+
+	for _, spec := range []string{"example.com", "https://example.com/path", "https://example.com?a=b", "https://"} {
+		_, err = ParseCORSOrigins(spec)
+		require.Error(t, err, spec)
+	}
+}
+
+func TestNewCORSHandler(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name           string
+		allowedOrigins []string
+		method         string
+		headers        map[string]string
+		expectedStatus int
+		expectedOrigin string
+		preflight      bool
+		nextCalled     bool
+	}{
+		{
+			name:           "allowed origin",
+			allowedOrigins: []string{"https://example.com"},
+			method:         http.MethodPost,
+			headers:        map[string]string{"Origin": "https://example.com"},
+			expectedStatus: http.StatusOK,
+			expectedOrigin: "https://example.com",
+			nextCalled:     true,
+		},
+		{
+			name:           "wildcard origin",
+			allowedOrigins: []string{"*"},
+			method:         http.MethodGet,
+			headers:        map[string]string{"Origin": "https://other.example.com"},
+			expectedStatus: http.StatusOK,
+			expectedOrigin: "*",
+			nextCalled:     true,
+		},
+		{
+			name:           "disallowed origin",
+			allowedOrigins: []string{"https://example.com"},
+			method:         http.MethodPost,
+			headers:        map[string]string{"Origin": "https://evil.example.com"},
+			expectedStatus: http.StatusOK,
+			nextCalled:     true,
+		},
+		{
+			name:           "same-origin request without Origin header",
+			allowedOrigins: []string{"*"},
+			method:         http.MethodPost,
+			expectedStatus: http.StatusOK,
+			nextCalled:     true,
+		},
+		{
+			name:           "preflight from allowed origin",
+			allowedOrigins: []string{"https://example.com"},
+			method:         http.MethodOptions,
+			headers: map[string]string{
+				"Origin":                         "https://example.com",
+				"Access-Control-Request-Method":  http.MethodPost,
+				"Access-Control-Request-Headers": "content-type, mcp-session-id",
+			},
+			expectedStatus: http.StatusNoContent,
+			expectedOrigin: "https://example.com",
+			preflight:      true,
+		},
+		{
+			name:           "preflight from disallowed origin is passed through",
+			allowedOrigins: []string{"https://example.com"},
+			method:         http.MethodOptions,
+			headers: map[string]string{
+				"Origin":                        "https://evil.example.com",
+				"Access-Control-Request-Method": http.MethodPost,
+			},
+			expectedStatus: http.StatusOK,
+			nextCalled:     true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			nextCalled := false
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				nextCalled = true
+				w.WriteHeader(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(tc.method, "/mcp", nil)
+			for k, v := range tc.headers {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+			NewCORSHandler(next, tc.allowedOrigins).ServeHTTP(rec, req)
+
+			require.Equal(t, tc.expectedStatus, rec.Code)
+			require.Equal(t, tc.nextCalled, nextCalled)
+			require.Equal(t, tc.expectedOrigin, rec.Header().Get("Access-Control-Allow-Origin"))
+			require.Contains(t, rec.Header().Values("Vary"), "Origin")
+			if tc.expectedOrigin != "" {
+				require.Equal(t, corsExposedHeaders, rec.Header().Get("Access-Control-Expose-Headers"))
+			}
+			if tc.preflight {
+				require.Equal(t, corsAllowedMethods, rec.Header().Get("Access-Control-Allow-Methods"))
+				require.Contains(t, rec.Header().Get("Access-Control-Allow-Headers"), "Mcp-Session-Id")
+				require.Contains(t, rec.Header().Get("Access-Control-Allow-Headers"), mimirTenantHeader)
+				require.Equal(t, corsMaxAge, rec.Header().Get("Access-Control-Max-Age"))
+			} else {
+				require.Empty(t, rec.Header().Get("Access-Control-Allow-Methods"))
+			}
+		})
+	}
+
+	t.Run("no allowed origins", func(t *testing.T) {
+		t.Parallel()
+
+		next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+		req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+		req.Header.Set("Origin", "https://example.com")
+		rec := httptest.NewRecorder()
+		NewCORSHandler(next, nil).ServeHTTP(rec, req)
+		require.Empty(t, rec.Header().Get("Access-Control-Allow-Origin"))
+		require.Empty(t, rec.Header().Values("Vary"))
+	})
+}
+
+func TestResolveAuthToken(t *testing.T) {
+	t.Parallel()
+
+	token, err := ResolveAuthToken("", "")
+	require.NoError(t, err)
+	require.Empty(t, token)
+
+	token, err = ResolveAuthToken("secret", "")
+	require.NoError(t, err)
+	require.Equal(t, "secret", token)
+
+	dir := t.TempDir()
+	tokenFile := filepath.Join(dir, "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("file-secret\n"), 0o600))
+	token, err = ResolveAuthToken("", tokenFile)
+	require.NoError(t, err)
+	require.Equal(t, "file-secret", token)
+
+	_, err = ResolveAuthToken("secret", tokenFile)
+	require.ErrorContains(t, err, "only one of")
+
+	_, err = ResolveAuthToken("", filepath.Join(dir, "missing"))
+	require.ErrorContains(t, err, "failed to read auth token file")
+
+	emptyFile := filepath.Join(dir, "empty")
+	require.NoError(t, os.WriteFile(emptyFile, []byte("\n"), 0o600))
+	_, err = ResolveAuthToken("", emptyFile)
+	require.ErrorContains(t, err, "is empty")
+}
+
+func TestNewBearerAuthHandler(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name           string
+		token          string
+		authHeader     string
+		expectedStatus int
+		nextCalled     bool
+	}{
+		{
+			name:           "matching token",
+			token:          "secret",
+			authHeader:     "Bearer secret",
+			expectedStatus: http.StatusOK,
+			nextCalled:     true,
+		},
+		{
+			name:           "missing header",
+			token:          "secret",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "wrong token",
+			token:          "secret",
+			authHeader:     "Bearer wrong",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "basic auth",
+			token:          "secret",
+			authHeader:     "Basic c2VjcmV0",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "authentication disabled",
+			authHeader:     "Bearer prometheus-token",
+			expectedStatus: http.StatusOK,
+			nextCalled:     true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				nextCalled bool
+				nextAuth   string
+			)
+			next := authContextMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				nextCalled = true
+				nextAuth = getAuthFromContext(r.Context())
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+			if tc.authHeader != "" {
+				req.Header.Set("Authorization", tc.authHeader)
+			}
+			rec := httptest.NewRecorder()
+			NewBearerAuthHandler(next, tc.token).ServeHTTP(rec, req)
+
+			require.Equal(t, tc.expectedStatus, rec.Code)
+			require.Equal(t, tc.nextCalled, nextCalled)
+			if tc.expectedStatus == http.StatusUnauthorized {
+				require.Contains(t, rec.Header().Get("WWW-Authenticate"), "Bearer")
+			}
+			// The MCP auth token must not be forwarded to Prometheus.
+			if tc.token != "" {
+				require.Empty(t, nextAuth)
+			} else {
+				require.Equal(t, tc.authHeader, nextAuth)
+			}
+			require.Equal(t, tc.authHeader, req.Header.Get("Authorization"), "original request is not modified")
+		})
+	}
+}
+
+// TestUserAgent verifies that API and raw HTTP calls send the configured
+// User-Agent with the name of the calling tool.
+func TestUserAgent(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	userAgents := map[string]string{}
+	promServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		userAgents[r.URL.Path] = r.Header.Get("User-Agent")
+		mu.Unlock()
+		if r.URL.Path == "/api/v1/query" {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[]}}`))
+		}
+	}))
+	t.Cleanup(promServer.Close)
+
+	newContainer := func(t *testing.T, userAgent string) *ServerContainer {
+		container, err := newServerContainer(context.Background(), ServerConfig{
+			Logger:            slog.Default(),
+			PrometheusURL:     promServer.URL,
+			PrometheusTimeout: time.Minute,
+			RoundTripper:      http.DefaultTransport,
+			UserAgent:         userAgent,
+		})
+		require.NoError(t, err)
+		return container
+	}
+
+	t.Run("tool calls append the tool name", func(t *testing.T) {
+		container := newContainer(t, "custom-agent/1.0")
+
+		ts := mcptest.NewTestServer(t)
+		ts.Server.AddReceivingMiddleware(telemetryMiddleware(slog.Default()))
+		mcptest.AddTool(ts, queryToolDef, container.QueryHandler)
+		mcptest.AddTool(ts, reloadToolDef, container.ReloadHandler)
+
+		for _, tool := range []string{"query", "reload"} {
+			args := map[string]any{}
+			if tool == "query" {
+				args["query"] = "up"
+			}
+			result, err := ts.CallTool(ts.Context(), tool, args)
+			require.NoError(t, err)
+			require.False(t, result.IsError, mcptest.GetResultText(result))
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		require.Equal(t, "custom-agent/1.0 mcp-tool/query", userAgents["/api/v1/query"])
+		require.Equal(t, "custom-agent/1.0 mcp-tool/reload", userAgents["/-/reload"])
+	})
+
+	t.Run("defaults to the project user agent outside tool calls", func(t *testing.T) {
+		container := newContainer(t, "")
+
+		_, rt := container.GetAPIClient(context.Background())
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, promServer.URL+"/api/v1/status/buildinfo", nil)
+		require.NoError(t, err)
+		resp, err := rt.RoundTrip(req)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+
+		mu.Lock()
+		defer mu.Unlock()
+		require.Equal(t, mcpProm.UserAgent(), userAgents["/api/v1/status/buildinfo"])
+	})
+}
+
 // TestFullAuthFlow tests the complete auth flow from HTTP request through
 // tool execution to Prometheus API call.
 func TestFullAuthFlow(t *testing.T) {
@@ -888,6 +1501,266 @@ func TestGetAuthFromContext(t *testing.T) {
 	})
 }
 
+// TestGetAPIClientWithHTTPConfig tests that per-request HTTP client configs
+// from the context are used to build API clients.
+func TestGetAPIClientWithHTTPConfig(t *testing.T) {
+	t.Parallel()
+
+	basicAuthConfig := func(password string) *config.HTTPClientConfig {
+		cfg := config.DefaultHTTPClientConfig
+		cfg.BasicAuth = &config.BasicAuth{Username: "tenant", Password: config.Secret(password)}
+		return &cfg
+	}
+
+	// roundTrip sends a request through the round tripper returned for ctx
+	// and returns the Authorization header the server received.
+	roundTrip := func(t *testing.T, container *ServerContainer, ctx context.Context) string {
+		t.Helper()
+
+		var (
+			mu       sync.Mutex
+			received string
+		)
+		promServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			received = r.Header.Get("Authorization")
+			mu.Unlock()
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[]}}`))
+		}))
+		defer promServer.Close()
+		container.prometheusURL = promServer.URL
+
+		client, rt := container.GetAPIClient(ctx)
+		require.NotNil(t, client)
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, promServer.URL+"/api/v1/query", nil)
+		require.NoError(t, err)
+		resp, err := rt.RoundTrip(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+
+		mu.Lock()
+		defer mu.Unlock()
+		return received
+	}
+
+	t.Run("uses HTTP config from context", func(t *testing.T) {
+		t.Parallel()
+
+		container := newTestContainer(nil)
+		ctx := addHTTPConfigToContext(context.Background(), basicAuthConfig("secret"))
+
+		require.Equal(t, "Basic dGVuYW50OnNlY3JldA==", roundTrip(t, container, ctx))
+	})
+
+	t.Run("falls back to default round tripper without HTTP config", func(t *testing.T) {
+		t.Parallel()
+
+		container := newTestContainer(nil)
+		_, rt := container.GetAPIClient(context.Background())
+		require.Equal(t, container.defaultRT, rt)
+		require.Empty(t, roundTrip(t, container, context.Background()))
+	})
+
+	t.Run("falls back to default round tripper for invalid HTTP config", func(t *testing.T) {
+		t.Parallel()
+
+		container := newTestContainer(nil)
+		cfg := basicAuthConfig("secret")
+		cfg.BearerToken = "also-a-token"
+		ctx := addHTTPConfigToContext(context.Background(), cfg)
+
+		_, rt := container.GetAPIClient(ctx)
+		require.Equal(t, container.defaultRT, rt)
+	})
+
+	t.Run("Authorization header takes precedence over HTTP config credentials", func(t *testing.T) {
+		t.Parallel()
+
+		container := newTestContainer(nil)
+		ctx := addHTTPConfigToContext(context.Background(), basicAuthConfig("secret"))
+		ctx = addAuthToContext(ctx, "Bearer header-token")
+
+		require.Equal(t, "Bearer header-token", roundTrip(t, container, ctx))
+	})
+
+	t.Run("round trippers are cached by config", func(t *testing.T) {
+		t.Parallel()
+
+		container := newTestContainer(nil)
+
+		_, rt1 := container.GetAPIClient(addHTTPConfigToContext(context.Background(), basicAuthConfig("secret")))
+		_, rt2 := container.GetAPIClient(addHTTPConfigToContext(context.Background(), basicAuthConfig("secret")))
+		_, rt3 := container.GetAPIClient(addHTTPConfigToContext(context.Background(), basicAuthConfig("other-secret")))
+
+		require.Same(t, rt1, rt2)
+		require.NotSame(t, rt1, rt3)
+		require.Len(t, container.httpConfigRTs, 2)
+	})
+}
+
+func TestGetAPIClientWithMimirTenant(t *testing.T) {
+	t.Parallel()
+
+	// roundTrip sends a request through both the API client and the round
+	// tripper returned for ctx, and returns the tenant headers the server
+	// received.
+	roundTrip := func(t *testing.T, container *ServerContainer, ctx context.Context) []string {
+		t.Helper()
+
+		var (
+			mu       sync.Mutex
+			received []string
+		)
+		promServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			received = append(received, r.Header.Get(mimirTenantHeader))
+			mu.Unlock()
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[]}}`))
+		}))
+		defer promServer.Close()
+		container.prometheusURL = promServer.URL
+
+		client, rt := container.GetAPIClient(ctx)
+		_, _, err := client.Query(ctx, "up", time.Now())
+		require.NoError(t, err)
+
+		_, err = container.doHTTPRequest(ctx, http.MethodGet, rt, "/-/ready", false)
+		require.NoError(t, err)
+
+		mu.Lock()
+		defer mu.Unlock()
+		return received
+	}
+
+	t.Run("sets tenant from flag", func(t *testing.T) {
+		t.Parallel()
+
+		container := newTestContainer(nil)
+		container.prometheusBackend = "mimir"
+		container.mimirTenant = "flag-tenant"
+
+		require.Equal(t, []string{"flag-tenant", "flag-tenant"}, roundTrip(t, container, context.Background()))
+	})
+
+	t.Run("tenant from context takes precedence over flag", func(t *testing.T) {
+		t.Parallel()
+
+		container := newTestContainer(nil)
+		container.prometheusBackend = "mimir"
+		container.mimirTenant = "flag-tenant"
+		ctx := addTenantToContext(context.Background(), "request-tenant")
+
+		require.Equal(t, []string{"request-tenant", "request-tenant"}, roundTrip(t, container, ctx))
+	})
+
+	t.Run("tenant is combined with Authorization header", func(t *testing.T) {
+		t.Parallel()
+
+		container := newTestContainer(nil)
+		container.prometheusBackend = "mimir"
+		ctx := addAuthToContext(context.Background(), "Bearer token")
+		ctx = addTenantToContext(ctx, "request-tenant")
+
+		require.Equal(t, []string{"request-tenant", "request-tenant"}, roundTrip(t, container, ctx))
+	})
+
+	t.Run("tenant is ignored for other backends", func(t *testing.T) {
+		t.Parallel()
+
+		container := newTestContainer(nil)
+		container.mimirTenant = "flag-tenant"
+		ctx := addTenantToContext(context.Background(), "request-tenant")
+
+		for _, tenant := range roundTrip(t, container, ctx) {
+			require.Empty(t, tenant)
+		}
+	})
+}
+
+func TestGetAPIClientWithForwardedHeaders(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu       sync.Mutex
+		received []http.Header
+	)
+	promServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		received = append(received, r.Header.Clone())
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[]}}`))
+	}))
+	defer promServer.Close()
+
+	container := newTestContainer(nil)
+	container.prometheusURL = promServer.URL
+	container.forwardHeaders = []string{"X-Scope-Orgid", "X-Custom"}
+
+	incoming := make(http.Header)
+	incoming.Set("X-Scope-OrgID", "team-a")
+	incoming.Add("X-Custom", "one")
+	incoming.Add("X-Custom", "two")
+	incoming.Set("Cookie", "session=secret")
+	ctx := addRequestHeadersToContext(context.Background(), incoming)
+	ctx = addAuthToContext(ctx, "Bearer token")
+
+	client, rt := container.GetAPIClient(ctx)
+	_, _, err := client.Query(ctx, "up", time.Now())
+	require.NoError(t, err)
+	_, err = container.doHTTPRequest(ctx, http.MethodGet, rt, "/-/ready", false)
+	require.NoError(t, err)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, received, 2)
+	for _, h := range received {
+		require.Equal(t, "team-a", h.Get("X-Scope-OrgID"))
+		require.Equal(t, []string{"one", "two"}, h.Values("X-Custom"))
+		require.Equal(t, "Bearer token", h.Get("Authorization"))
+		require.Empty(t, h.Get("Cookie"), "headers that are not allow-listed must not be forwarded")
+	}
+}
+
+func TestNormalizeForwardHeaders(t *testing.T) {
+	t.Parallel()
+
+	headers, err := normalizeForwardHeaders(nil)
+	require.NoError(t, err)
+	require.Empty(t, headers)
+
+	headers, err = normalizeForwardHeaders([]string{"x-scope-orgid", " X-Custom ", "X-Scope-OrgID"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"X-Scope-Orgid", "X-Custom"}, headers)
+
+	_, err = normalizeForwardHeaders([]string{"X Custom"})
+	require.ErrorContains(t, err, "invalid header name")
+
+	_, err = normalizeForwardHeaders([]string{"authorization"})
+	require.ErrorContains(t, err, "always forwarded")
+}
+
+func TestAuthContextMiddlewareTenant(t *testing.T) {
+	t.Parallel()
+
+	var capturedTenant string
+	handler := authContextMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		capturedTenant = getTenantFromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/mcp/v1", nil)
+	req.Header.Set(mimirTenantHeader, "team-a")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Equal(t, "team-a", capturedTenant)
+}
+
 // TestAuthContextMiddleware_Integration tests the full HTTP request flow
 // through the middleware to verify context propagation.
 func TestAuthContextMiddleware_Integration(t *testing.T) {
@@ -0,0 +1,157 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Prompt definitions.
+var (
+	investigateAlertPrompt = &mcp.Prompt{
+		Name:        "investigate_alert",
+		Title:       "Investigate Alert",
+		Description: "Step-by-step runbook for investigating a firing or pending alert: find the active alerts, evaluate the alerting rule's expression, and explore related series",
+		Arguments: []*mcp.PromptArgument{
+			{
+				Name:        "alertname",
+				Title:       "Alert Name",
+				Description: "Name of the alert to investigate, as set by the alertname label",
+				Required:    true,
+			},
+		},
+	}
+
+	explainTruncationPrompt = &mcp.Prompt{
+		Name:        "explain_truncation",
+		Title:       "Explain Truncation",
+		Description: "Explains how this server truncates tool results, including the current truncation limit and mode, and how to override truncation on a per-tool-call basis",
+	}
+)
+
+// investigateAlertTemplate is the message returned by the investigate_alert
+// prompt. Tool names must match the tool definitions in tools.go.
+var investigateAlertTemplate = template.Must(template.New("investigate_alert").Parse(
+	`Investigate the Prometheus alert {{ .Quoted }} by following these steps in order.
+
+1. Call the ` + "`list_alerts`" + ` tool and find the active alerts with alertname {{ .Quoted }}. Note whether each is pending or firing, when it became active, and its labels and annotations. If there are none, say so and call the ` + "`list_rules`" + ` tool to check that the alerting rule exists and is healthy.
+
+2. Find the alerting rule named {{ .Quoted }} in the output of the ` + "`list_rules`" + ` tool and call the ` + "`query`" + ` tool with its expression. Compare the result with the alerting threshold to see which series are breaching it and by how much. You can also query ` + "`ALERTS{alertname={{ .Quoted }}}`" + ` to see the alert's series.
+
+3. For each metric used in the expression, call the ` + "`series`" + ` tool with matchers built from the labels of the firing alerts (for example job and instance) to find related series for the affected targets that may explain the alert.
+
+4. Summarize what triggered the alert, which targets are affected, the most likely cause, and suggested next steps. Do not make any changes to Prometheus or silence the alert.
+`))
+
+// explainTruncationTemplate is the message returned by the
+// explain_truncation prompt, rendered with the server's truncation settings.
+var explainTruncationTemplate = template.Must(template.New("explain_truncation").Parse(
+	`This Prometheus MCP server may truncate large tool results to save context. Keep the following in mind when calling its tools.
+
+{{ if .Limit }}Truncation is enabled with a default limit of {{ .Limit }} {{ .Unit }}, set by the ` + "`--prometheus.truncation-limit`" + ` flag.{{ else }}Truncation is disabled by default, as the ` + "`--prometheus.truncation-limit`" + ` flag is 0. Results are only truncated when a tool call requests a limit.{{ end }}
+{{ if eq .Mode "bytes" }}In the ` + "`bytes`" + ` truncation mode, query results are cut after the limit's number of bytes, on a UTF-8 character boundary.{{ else if eq .Mode "tokens" }}In the ` + "`tokens`" + ` truncation mode, query results are cut at the last complete line within the limit's number of tokens, estimated as {{ .CharsPerToken }} characters per token.{{ else }}In the ` + "`lines`" + ` truncation mode, query results are cut after the limit's number of lines.{{ end }} Tools returning lists of entries, such as ` + "`series`" + ` and ` + "`label_values`" + `, always truncate by number of entries.
+
+A truncated result ends with a warning starting with "Warning: The result was truncated". Everything before the warning is valid, but incomplete: do not draw conclusions about the full result, such as counts or the absence of a series, from a truncated one.
+
+To handle truncated results:
+- Prefer narrowing the query first, with more specific label matchers or aggregations like ` + "`sum by (job)`" + `, or sort the series of the ` + "`query`" + ` and ` + "`range_query`" + ` tools with ` + "`sort_by`" + ` so the most relevant ones fit within the limit.
+- Set the ` + "`truncation_limit`" + ` argument on a tool call to use a different limit for that call only, in the same unit as above. Setting it to -1 disables truncation for the call. Omitting it, or setting it to 0, uses the default limit.
+- For the ` + "`series`" + ` and ` + "`label_values`" + ` tools, set the ` + "`page`" + ` or ` + "`page_size`" + ` arguments to paginate through large results instead of truncating them.
+`))
+
+// registerPrompts registers all MCP prompts with the server.
+func registerPrompts(server *mcp.Server, container *ServerContainer) {
+	server.AddPrompt(investigateAlertPrompt, container.InvestigateAlertPromptHandler)
+	server.AddPrompt(explainTruncationPrompt, container.ExplainTruncationPromptHandler)
+}
+
+// Prompt handlers
+
+// InvestigateAlertPromptHandler handles the investigate alert prompt request.
+func (s *ServerContainer) InvestigateAlertPromptHandler(ctx context.Context, req *mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	alertname := strings.TrimSpace(req.Params.Arguments["alertname"])
+	if alertname == "" {
+		return nil, errors.New("alertname argument is required")
+	}
+
+	var sb strings.Builder
+	err := investigateAlertTemplate.Execute(&sb, struct{ Quoted string }{
+		Quoted: strconv.Quote(alertname),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to render prompt: %w", err)
+	}
+
+	return &mcp.GetPromptResult{
+		Description: "Investigate the " + alertname + " alert",
+		Messages: []*mcp.PromptMessage{
+			{
+				Role:    "user",
+				Content: &mcp.TextContent{Text: sb.String()},
+			},
+		},
+	}, nil
+}
+
+// ExplainTruncationPromptHandler handles the explain truncation prompt
+// request.
+func (s *ServerContainer) ExplainTruncationPromptHandler(ctx context.Context, req *mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	mode := s.truncationMode
+	if mode == "" {
+		mode = TruncationModeLines
+	}
+
+	var unit string
+	switch mode {
+	case TruncationModeBytes:
+		unit = "bytes"
+	case TruncationModeTokens:
+		unit = "estimated tokens"
+	default:
+		unit = "lines"
+	}
+
+	var sb strings.Builder
+	err := explainTruncationTemplate.Execute(&sb, struct {
+		Limit         int
+		Mode          string
+		Unit          string
+		CharsPerToken int
+	}{
+		Limit:         s.truncationLimit,
+		Mode:          mode,
+		Unit:          unit,
+		CharsPerToken: charsPerToken,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to render prompt: %w", err)
+	}
+
+	return &mcp.GetPromptResult{
+		Description: "Truncation behavior of this Prometheus MCP server",
+		Messages: []*mcp.PromptMessage{
+			{
+				Role:    "user",
+				Content: &mcp.TextContent{Text: sb.String()},
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,155 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcp
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/prometheus/prometheus-mcp/pkg/mcp/mcptest"
+)
+
+func TestInvestigateAlertPromptHandler(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name          string
+		args          map[string]string
+		expectedError string
+		expectedText  []string
+	}{
+		{
+			name: "renders runbook for alert",
+			args: map[string]string{"alertname": "HighLatency"},
+			expectedText: []string{
+				"`list_alerts`",
+				"`query`",
+				"`series`",
+				`alertname "HighLatency"`,
+				`ALERTS{alertname="HighLatency"}`,
+			},
+		},
+		{
+			name:         "quotes alert names",
+			args:         map[string]string{"alertname": `Weird"Name`},
+			expectedText: []string{`ALERTS{alertname="Weird\"Name"}`},
+		},
+		{
+			name:          "missing alertname",
+			args:          map[string]string{},
+			expectedError: "alertname argument is required",
+		},
+		{
+			name:          "blank alertname",
+			args:          map[string]string{"alertname": "  "},
+			expectedError: "alertname argument is required",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			container := newTestContainer(nil)
+			ts := mcptest.NewTestServer(t)
+			ts.AddPrompt(investigateAlertPrompt, container.InvestigateAlertPromptHandler)
+
+			result, err := ts.GetPrompt(ts.Context(), "investigate_alert", tc.args)
+			if tc.expectedError != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Len(t, result.Messages, 1)
+			require.Equal(t, "user", string(result.Messages[0].Role))
+
+			text := mcptest.GetPromptText(result)
+			for _, expected := range tc.expectedText {
+				require.Contains(t, text, expected)
+			}
+
+			// Steps must be in runbook order.
+			listAlerts := strings.Index(text, "`list_alerts`")
+			query := strings.Index(text, "`query`")
+			series := strings.Index(text, "`series`")
+			require.Less(t, listAlerts, query)
+			require.Less(t, query, series)
+		})
+	}
+}
+
+func TestExplainTruncationPromptHandler(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name            string
+		truncationLimit int
+		truncationMode  string
+		expectedText    []string
+		unexpectedText  []string
+	}{
+		{
+			name:           "truncation disabled",
+			expectedText:   []string{"Truncation is disabled by default", "`lines` truncation mode", "`truncation_limit`", "-1 disables truncation"},
+			unexpectedText: []string{"default limit of"},
+		},
+		{
+			name:            "lines mode",
+			truncationLimit: 200,
+			truncationMode:  TruncationModeLines,
+			expectedText:    []string{"default limit of 200 lines", "`lines` truncation mode"},
+		},
+		{
+			name:            "bytes mode",
+			truncationLimit: 4096,
+			truncationMode:  TruncationModeBytes,
+			expectedText:    []string{"default limit of 4096 bytes", "`bytes` truncation mode"},
+		},
+		{
+			name:            "tokens mode",
+			truncationLimit: 1000,
+			truncationMode:  TruncationModeTokens,
+			expectedText:    []string{"default limit of 1000 estimated tokens", "`tokens` truncation mode", "4 characters per token"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			container := newTestContainer(nil)
+			container.truncationLimit = tc.truncationLimit
+			container.truncationMode = tc.truncationMode
+
+			ts := mcptest.NewTestServer(t)
+			ts.AddPrompt(explainTruncationPrompt, container.ExplainTruncationPromptHandler)
+
+			result, err := ts.GetPrompt(ts.Context(), "explain_truncation", nil)
+			require.NoError(t, err)
+			require.Len(t, result.Messages, 1)
+
+			text := mcptest.GetPromptText(result)
+			for _, expected := range tc.expectedText {
+				require.Contains(t, text, expected)
+			}
+			for _, unexpected := range tc.unexpectedText {
+				require.NotContains(t, text, unexpected)
+			}
+		})
+	}
+}
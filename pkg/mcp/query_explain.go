@@ -0,0 +1,273 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcp
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/promql/parser"
+)
+
+// promqlParser parses PromQL with the same default options as a stock
+// Prometheus server, so experimental features are rejected.
+var promqlParser = parser.NewParser(parser.Options{})
+
+// queryExplainSelector describes a vector or matrix selector found in a
+// parsed PromQL expression, along with any modifiers applied to it.
+type queryExplainSelector struct {
+	Selector string   `json:"selector"`
+	Type     string   `json:"type"`
+	Metric   string   `json:"metric,omitempty"`
+	Matchers []string `json:"matchers"`
+	Range    string   `json:"range,omitempty"`
+	Offset   string   `json:"offset,omitempty"`
+	At       string   `json:"at,omitempty"`
+}
+
+// queryExplainSubquery describes a subquery found in a parsed PromQL
+// expression, along with any modifiers applied to it.
+type queryExplainSubquery struct {
+	Subquery string `json:"subquery"`
+	Range    string `json:"range"`
+	Step     string `json:"step,omitempty"`
+	Offset   string `json:"offset,omitempty"`
+	At       string `json:"at,omitempty"`
+}
+
+type queryExplainResponse struct {
+	Query      string                 `json:"query"`
+	ResultType string                 `json:"result_type"`
+	Pretty     string                 `json:"pretty"`
+	Tree       string                 `json:"tree"`
+	Selectors  []queryExplainSelector `json:"selectors"`
+	Subqueries []queryExplainSubquery `json:"subqueries,omitempty"`
+}
+
+// explainPromQLQuery parses a PromQL expression without executing it and
+// describes its structure. Parse errors are returned with the position of
+// the problem in the query.
+func explainPromQLQuery(query string) (queryExplainResponse, error) {
+	expr, err := promqlParser.ParseExpr(query)
+	if err != nil {
+		return queryExplainResponse{}, describePromQLParseError(query, err)
+	}
+
+	resp := queryExplainResponse{
+		Query:      query,
+		ResultType: string(expr.Type()),
+		Pretty:     parser.Prettify(expr),
+		Tree:       parser.Tree(expr),
+		Selectors:  []queryExplainSelector{},
+	}
+
+	// A matrix selector wraps a vector selector, which is visited next and
+	// must not be reported twice.
+	var wrapped *parser.VectorSelector
+	parser.Inspect(expr, func(node parser.Node, _ []parser.Node) error {
+		switch n := node.(type) {
+		case *parser.MatrixSelector:
+			vs, ok := n.VectorSelector.(*parser.VectorSelector)
+			if !ok {
+				return nil
+			}
+			wrapped = vs
+			sel := explainVectorSelector(vs)
+			sel.Selector = n.String()
+			sel.Type = "matrix"
+			sel.Range = model.Duration(n.Range).String()
+			if n.RangeExpr != nil {
+				sel.Range = n.RangeExpr.String()
+			}
+			resp.Selectors = append(resp.Selectors, sel)
+		case *parser.VectorSelector:
+			if n != wrapped {
+				resp.Selectors = append(resp.Selectors, explainVectorSelector(n))
+			}
+		case *parser.SubqueryExpr:
+			sq := queryExplainSubquery{
+				Subquery: n.String(),
+				Range:    model.Duration(n.Range).String(),
+				Offset:   explainOffset(n.OriginalOffset, n.OriginalOffsetExpr),
+				At:       explainAt(n.Timestamp, n.StartOrEnd),
+			}
+			if n.RangeExpr != nil {
+				sq.Range = n.RangeExpr.String()
+			}
+			if n.StepExpr != nil {
+				sq.Step = n.StepExpr.String()
+			} else if n.Step != 0 {
+				sq.Step = model.Duration(n.Step).String()
+			}
+			resp.Subqueries = append(resp.Subqueries, sq)
+		}
+		return nil
+	})
+
+	return resp, nil
+}
+
+type queryValidation struct {
+	Valid    bool   `json:"valid"`
+	Error    string `json:"error,omitempty"`
+	Position *int   `json:"position,omitempty"`
+}
+
+// validatePromQLQuery reports whether query is a valid PromQL expression. For
+// invalid queries, the error and the byte offset of the problem in the query
+// are included.
+func validatePromQLQuery(query string) queryValidation {
+	if strings.TrimSpace(query) == "" {
+		return queryValidation{Error: "query parameter is required"}
+	}
+
+	_, err := promqlParser.ParseExpr(query)
+	if err == nil {
+		return queryValidation{Valid: true}
+	}
+
+	v := queryValidation{Error: err.Error()}
+	var parseErrs parser.ParseErrors
+	if errors.As(err, &parseErrs) && len(parseErrs) > 0 {
+		pos := int(parseErrs[0].PositionRange.Start)
+		v.Position = &pos
+	}
+	return v
+}
+
+func explainVectorSelector(vs *parser.VectorSelector) queryExplainSelector {
+	matchers := make([]string, 0, len(vs.LabelMatchers))
+	for _, m := range vs.LabelMatchers {
+		matchers = append(matchers, m.String())
+	}
+
+	return queryExplainSelector{
+		Selector: vs.String(),
+		Type:     "vector",
+		Metric:   vs.Name,
+		Matchers: matchers,
+		Offset:   explainOffset(vs.OriginalOffset, vs.OriginalOffsetExpr),
+		At:       explainAt(vs.Timestamp, vs.StartOrEnd),
+	}
+}
+
+func explainOffset(offset time.Duration, offsetExpr *parser.DurationExpr) string {
+	switch {
+	case offsetExpr != nil:
+		return offsetExpr.String()
+	case offset != 0:
+		return model.Duration(offset).String()
+	default:
+		return ""
+	}
+}
+
+func explainAt(ts *int64, startOrEnd parser.ItemType) string {
+	switch {
+	case ts != nil:
+		return time.UnixMilli(*ts).UTC().Format(time.RFC3339Nano)
+	case startOrEnd == parser.START:
+		return "start()"
+	case startOrEnd == parser.END:
+		return "end()"
+	default:
+		return ""
+	}
+}
+
+// describePromQLParseError formats a PromQL parse error with the line and
+// column of the problem, followed by the offending line of the query and a
+// caret pointing at the position.
+func describePromQLParseError(query string, err error) error {
+	var parseErrs parser.ParseErrors
+	if !errors.As(err, &parseErrs) || len(parseErrs) == 0 {
+		return err
+	}
+
+	pe := parseErrs[0]
+	start := min(max(int(pe.PositionRange.Start), 0), len(query))
+	lineStart := strings.LastIndexByte(query[:start], '\n') + 1
+	lineEnd := strings.IndexByte(query[start:], '\n')
+	if lineEnd < 0 {
+		lineEnd = len(query)
+	} else {
+		lineEnd += start
+	}
+
+	return fmt.Errorf("%s: %w\n%s\n%s^", pe.PositionRange.StartPosInput(query, 0), pe.Err, query[lineStart:lineEnd], strings.Repeat(" ", start-lineStart))
+}
+
+// Notes added to query results when the query uses offset or @ modifiers,
+// explaining how they interact with the requested time or range.
+const (
+	offsetInstantNote         = "The query uses the offset modifier: the selectors it is applied to read data from that long before the query time, while the result is still reported at the query time."
+	offsetRangeNote           = "The query uses the offset modifier: the selectors it is applied to read data from that long before each step of the range, while the samples are still reported at the step timestamps."
+	negativeOffsetInstantNote = "The query uses a negative offset, which reads data from after the query time. If that lies in the future, there is no data to return yet."
+	negativeOffsetRangeNote   = "The query uses a negative offset, which reads data from after each step of the range. Steps close to now may have no data to return yet."
+	atTimestampInstantNote    = "The query uses the @ modifier with a fixed timestamp: the selectors it is applied to always read data at that time, regardless of the query time."
+	atTimestampRangeNote      = "The query uses the @ modifier with a fixed timestamp: the selectors it is applied to always read data at that time, so they return the same value at every step of the range."
+	atStartEndInstantNote     = "The query uses @ start() or @ end(), which for an instant query both resolve to the query time."
+	atStartEndRangeNote       = "The query uses @ start() or @ end(): the selectors they are applied to read data at the start or end of the requested range, so they return the same value at every step of the range."
+)
+
+// queryModifierNotes returns advisory notes for the offset and @ modifiers
+// used in query, for an instant or a range query. Queries that fail to parse
+// get no notes, Prometheus reports the error when executing them.
+func queryModifierNotes(query string, rangeQuery bool) []string {
+	expr, err := promqlParser.ParseExpr(query)
+	if err != nil {
+		return nil
+	}
+
+	var offset, negativeOffset, atTimestamp, atStartEnd bool
+	record := func(originalOffset time.Duration, offsetExpr *parser.DurationExpr, ts *int64, startOrEnd parser.ItemType) {
+		offset = offset || originalOffset > 0 || offsetExpr != nil
+		negativeOffset = negativeOffset || originalOffset < 0
+		atTimestamp = atTimestamp || ts != nil
+		atStartEnd = atStartEnd || startOrEnd == parser.START || startOrEnd == parser.END
+	}
+	parser.Inspect(expr, func(node parser.Node, _ []parser.Node) error {
+		switch n := node.(type) {
+		case *parser.VectorSelector:
+			record(n.OriginalOffset, n.OriginalOffsetExpr, n.Timestamp, n.StartOrEnd)
+		case *parser.SubqueryExpr:
+			record(n.OriginalOffset, n.OriginalOffsetExpr, n.Timestamp, n.StartOrEnd)
+		}
+		return nil
+	})
+
+	pick := func(instant, ranged string) string {
+		if rangeQuery {
+			return ranged
+		}
+		return instant
+	}
+	var notes []string
+	if offset {
+		notes = append(notes, pick(offsetInstantNote, offsetRangeNote))
+	}
+	if negativeOffset {
+		notes = append(notes, pick(negativeOffsetInstantNote, negativeOffsetRangeNote))
+	}
+	if atTimestamp {
+		notes = append(notes, pick(atTimestampInstantNote, atTimestampRangeNote))
+	}
+	if atStartEnd {
+		notes = append(notes, pick(atStartEndInstantNote, atStartEndRangeNote))
+	}
+	return notes
+}
@@ -0,0 +1,104 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/time/rate"
+
+	"github.com/prometheus/prometheus-mcp/internal/metrics"
+)
+
+var metricToolCallsThrottled = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: prometheus.BuildFQName(metrics.MetricNamespace, "tool", "calls_throttled_total"),
+		Help: "Total number of tool calls rejected by the rate limiter, per tool.",
+	},
+	[]string{"tool_name"},
+)
+
+func init() {
+	metrics.Registry.MustRegister(metricToolCallsThrottled)
+}
+
+// toolRateLimiter is a token bucket rate limiter for tool calls. It is shared
+// by all clients: the MCP server doesn't verify who makes a call, so it has
+// no user to keep a separate bucket for, and forwarded credentials could be
+// varied to get around a per-user limit.
+type toolRateLimiter struct {
+	limit rate.Limit
+	burst int
+
+	limiter *rate.Limiter
+}
+
+// newToolRateLimiter returns a rate limiter allowing rps tool calls per
+// second with the given burst, or nil if rps is not positive. A burst below 1
+// defaults to rps rounded up.
+func newToolRateLimiter(rps float64, burst int) *toolRateLimiter {
+	if rps <= 0 {
+		return nil
+	}
+	if burst < 1 {
+		burst = int(math.Ceil(rps))
+	}
+	return &toolRateLimiter{
+		limit:   rate.Limit(rps),
+		burst:   burst,
+		limiter: rate.NewLimiter(rate.Limit(rps), burst),
+	}
+}
+
+// allow reports whether a tool call is allowed now. If not, it also returns
+// how long to wait before the next call would be allowed.
+func (l *toolRateLimiter) allow(now time.Time) (bool, time.Duration) {
+	r := l.limiter.ReserveN(now, 1)
+	if delay := r.DelayFrom(now); delay > 0 {
+		r.CancelAt(now)
+		return false, delay
+	}
+	return true, 0
+}
+
+// rateLimitMiddleware creates an MCP middleware that rejects tool calls
+// exceeding the rate limit with a tool error.
+func rateLimitMiddleware(l *toolRateLimiter, logger *slog.Logger) mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			if method != methodToolsCall {
+				return next(ctx, method, req)
+			}
+			params, ok := req.GetParams().(*mcp.CallToolParamsRaw)
+			if !ok {
+				return next(ctx, method, req)
+			}
+
+			allowed, retryAfter := l.allow(time.Now())
+			if !allowed {
+				metricToolCallsThrottled.With(prometheus.Labels{"tool_name": params.Name}).Inc()
+				logger.Warn("Tool call rate limited", "tool_name", params.Name, "retry_after", retryAfter)
+				return newToolErrorResult(fmt.Sprintf("rate limit exceeded, retry after %ds", int(math.Ceil(retryAfter.Seconds())))), nil
+			}
+
+			return next(ctx, method, req)
+		}
+	}
+}
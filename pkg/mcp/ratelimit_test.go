@@ -0,0 +1,104 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcp
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewToolRateLimiter(t *testing.T) {
+	t.Parallel()
+
+	require.Nil(t, newToolRateLimiter(0, 10))
+	require.Nil(t, newToolRateLimiter(-1, 10))
+
+	l := newToolRateLimiter(2.5, 0)
+	require.NotNil(t, l)
+	require.Equal(t, 3, l.burst, "burst defaults to rps rounded up")
+
+	l = newToolRateLimiter(0.5, 0)
+	require.Equal(t, 1, l.burst)
+
+	l = newToolRateLimiter(1, 5)
+	require.Equal(t, 5, l.burst)
+}
+
+func TestToolRateLimiterAllow(t *testing.T) {
+	t.Parallel()
+
+	l := newToolRateLimiter(1, 2)
+	now := time.Now()
+
+	// The burst is allowed, after that calls have to wait for a token.
+	for range 2 {
+		allowed, _ := l.allow(now)
+		require.True(t, allowed)
+	}
+	allowed, retryAfter := l.allow(now)
+	require.False(t, allowed)
+	require.Equal(t, time.Second, retryAfter)
+
+	// Rejected calls don't use up tokens.
+	allowed, retryAfter = l.allow(now.Add(500 * time.Millisecond))
+	require.False(t, allowed)
+	require.Equal(t, 500*time.Millisecond, retryAfter)
+	allowed, _ = l.allow(now.Add(time.Second))
+	require.True(t, allowed)
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	t.Parallel()
+
+	logger, buf := newTestLogger()
+	middleware := rateLimitMiddleware(newToolRateLimiter(0.001, 1), logger)
+
+	nextCalls := 0
+	handler := middleware(func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		nextCalls++
+		return newToolTextResult("ok"), nil
+	})
+
+	toolCall := &mcp.ServerRequest[*mcp.CallToolParamsRaw]{Params: &mcp.CallToolParamsRaw{Name: "rate_limit_test_tool"}}
+	throttled := func() float64 {
+		return testutil.ToFloat64(metricToolCallsThrottled.WithLabelValues("rate_limit_test_tool"))
+	}
+
+	// All calls share the global bucket, even with different basic auth
+	// usernames, which are not verified by the MCP server.
+	ctx := context.Background()
+	result, err := handler(addAuthToContext(ctx, "Basic "+base64.StdEncoding.EncodeToString([]byte("alice:pw"))), methodToolsCall, toolCall)
+	require.NoError(t, err)
+	require.False(t, result.(*mcp.CallToolResult).IsError)
+
+	result, err = handler(addAuthToContext(ctx, "Basic "+base64.StdEncoding.EncodeToString([]byte("mallory:pw"))), methodToolsCall, toolCall)
+	require.NoError(t, err)
+	toolResult := result.(*mcp.CallToolResult)
+	require.True(t, toolResult.IsError)
+	require.Regexp(t, `^rate limit exceeded, retry after [0-9]+s$`, toolResultErrorText(toolResult))
+	require.Equal(t, 1, nextCalls)
+	require.InDelta(t, 1, throttled(), 0)
+	require.Contains(t, buf.String(), "Tool call rate limited")
+
+	// Other methods are not rate limited.
+	_, err = handler(ctx, methodResourcesRead, mockRequest(&mcp.ReadResourceParams{URI: "prometheus://rules"}))
+	require.NoError(t, err)
+	require.Equal(t, 2, nextCalls)
+}
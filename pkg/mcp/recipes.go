@@ -0,0 +1,150 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcp
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	recipeQuantileRegex = regexp.MustCompile(`\bp(\d{1,2}(?:\.\d+)?)\b`)
+	recipeGroupByRegex  = regexp.MustCompile(`\b(?:by|per)\s+([a-zA-Z_][a-zA-Z0-9_]*)\b`)
+)
+
+// recipeGroupByIgnoredWords are words that commonly follow "by"/"per" in a
+// goal description without naming a label, e.g. "requests per second".
+var recipeGroupByIgnoredWords = map[string]struct{}{
+	"second": {},
+	"minute": {},
+	"hour":   {},
+	"day":    {},
+	"the":    {},
+	"a":      {},
+}
+
+// percentileToQuantile converts a percentile below 100 like "99.9" to the
+// quantile "0.999". The decimal point is moved in the string, as dividing
+// the parsed float by 100 gives results like 0.9990000000000001.
+func percentileToQuantile(percentile string) (string, bool) {
+	whole, frac, _ := strings.Cut(percentile, ".")
+	if len(whole) > 2 {
+		return "", false
+	}
+	digits := strings.TrimRight(strings.Repeat("0", 2-len(whole))+whole+frac, "0")
+	if digits == "" {
+		return "", false
+	}
+	return "0." + digits, true
+}
+
+// promqlRecipe is a templated PromQL query skeleton suggested for a goal.
+type promqlRecipe struct {
+	Skeleton    string   `json:"query_skeleton"`
+	Explanation string   `json:"explanation"`
+	Functions   []string `json:"functions"`
+}
+
+// suggestPromQLRecipe picks a query skeleton for a natural-language goal
+// using simple keyword matching. The returned skeleton contains <placeholders>
+// that must be replaced with real metric names and label matchers.
+func suggestPromQLRecipe(goal string) promqlRecipe {
+	g := strings.ToLower(goal)
+	hasAny := func(words ...string) bool {
+		for _, w := range words {
+			if strings.Contains(g, w) {
+				return true
+			}
+		}
+		return false
+	}
+
+	groupBy := ""
+	for _, m := range recipeGroupByRegex.FindAllStringSubmatch(g, -1) {
+		if _, ignored := recipeGroupByIgnoredWords[m[1]]; !ignored {
+			groupBy = m[1]
+			break
+		}
+	}
+	sumBy := "sum"
+	if groupBy != "" {
+		sumBy = "sum by (" + groupBy + ")"
+	}
+
+	quantileMatch := recipeQuantileRegex.FindStringSubmatch(g)
+	switch {
+	case quantileMatch != nil || hasAny("percentile", "quantile", "latency", "duration"):
+		quantile := "0.99"
+		if quantileMatch != nil {
+			if q, ok := percentileToQuantile(quantileMatch[1]); ok {
+				quantile = q
+			}
+		}
+		le := "le"
+		if groupBy != "" {
+			le = "le, " + groupBy
+		}
+		return promqlRecipe{
+			Skeleton:    "histogram_quantile(" + quantile + ", sum by (" + le + ") (rate(<metric>_bucket{<selector>}[5m])))",
+			Explanation: "Estimates the " + quantile + " quantile from a classic histogram. The per-bucket rate must be aggregated while keeping the 'le' label. For native histograms, drop the _bucket suffix and the 'le' grouping.",
+			Functions:   []string{"histogram_quantile", "rate", "sum"},
+		}
+	case hasAny("error") && hasAny("rate", "ratio", "percent", "%", "fraction"):
+		return promqlRecipe{
+			Skeleton:    sumBy + "(rate(<metric>_total{<selector>, <error_matcher>}[5m])) / " + sumBy + "(rate(<metric>_total{<selector>}[5m]))",
+			Explanation: "Divides the rate of failed events by the rate of all events to get an error ratio between 0 and 1.",
+			Functions:   []string{"rate", "sum"},
+		}
+	case hasAny("top", "highest", "most", "largest", "biggest"):
+		label := groupBy
+		if label == "" {
+			label = "<label>"
+		}
+		return promqlRecipe{
+			Skeleton:    "topk(10, sum by (" + label + ") (rate(<metric>_total{<selector>}[5m])))",
+			Explanation: "Returns the 10 series with the highest per-second rate, aggregated by the chosen label.",
+			Functions:   []string{"topk", "rate", "sum"},
+		}
+	case hasAny("down", "absent", "missing", "unreachable", "not scraped"):
+		return promqlRecipe{
+			Skeleton:    "up{<selector>} == 0",
+			Explanation: "Selects scrape targets that failed their most recent scrape. Use absent(<metric>{<selector>}) to detect series that do not exist at all.",
+			Functions:   []string{"up", "absent"},
+		}
+	case hasAny("predict", "run out", "fill up", "full", "exhaust"):
+		return promqlRecipe{
+			Skeleton:    "predict_linear(<metric>{<selector>}[6h], 4 * 3600) < 0",
+			Explanation: "Linearly extrapolates the last 6 hours of a gauge 4 hours into the future, e.g. to detect a resource that will run out.",
+			Functions:   []string{"predict_linear"},
+		}
+	case hasAny("increase", "how many", "total number", "count of", "over the last"):
+		return promqlRecipe{
+			Skeleton:    sumBy + "(increase(<metric>_total{<selector>}[1h]))",
+			Explanation: "Returns how much a counter increased over the range, accounting for counter resets.",
+			Functions:   []string{"increase", "sum"},
+		}
+	case hasAny("average", "avg", "memory", "usage", "temperature", "current", "gauge"):
+		return promqlRecipe{
+			Skeleton:    sumBy + "(avg_over_time(<metric>{<selector>}[5m]))",
+			Explanation: "Smooths a gauge by averaging each series over the range before aggregating.",
+			Functions:   []string{"avg_over_time", "sum"},
+		}
+	default:
+		return promqlRecipe{
+			Skeleton:    sumBy + "(rate(<metric>_total{<selector>}[5m]))",
+			Explanation: "Computes the per-second rate of a counter, aggregated across series. This is the most common starting point for throughput-style questions.",
+			Functions:   []string{"rate", "sum"},
+		}
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSuggestPromQLRecipe(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name             string
+		goal             string
+		expectedSkeleton string
+	}{
+		{
+			name:             "percentile latency",
+			goal:             "p99 latency for service X",
+			expectedSkeleton: "histogram_quantile(0.99, sum by (le) (rate(<metric>_bucket{<selector>}[5m])))",
+		},
+		{
+			name:             "percentile latency grouped by label",
+			goal:             "p95 request duration by handler",
+			expectedSkeleton: "histogram_quantile(0.95, sum by (le, handler) (rate(<metric>_bucket{<selector>}[5m])))",
+		},
+		{
+			name:             "fractional percentile",
+			goal:             "p99.9 latency",
+			expectedSkeleton: "histogram_quantile(0.999, sum by (le) (rate(<metric>_bucket{<selector>}[5m])))",
+		},
+		{
+			name:             "single digit percentile",
+			goal:             "p5 latency",
+			expectedSkeleton: "histogram_quantile(0.05, sum by (le) (rate(<metric>_bucket{<selector>}[5m])))",
+		},
+		{
+			name:             "zero percentile falls back to p99",
+			goal:             "p0 latency",
+			expectedSkeleton: "histogram_quantile(0.99, sum by (le) (rate(<metric>_bucket{<selector>}[5m])))",
+		},
+		{
+			name:             "error ratio",
+			goal:             "error rate of the checkout service",
+			expectedSkeleton: "sum(rate(<metric>_total{<selector>, <error_matcher>}[5m])) / sum(rate(<metric>_total{<selector>}[5m]))",
+		},
+		{
+			name:             "top k by label",
+			goal:             "which pods have the highest request rate by pod",
+			expectedSkeleton: "topk(10, sum by (pod) (rate(<metric>_total{<selector>}[5m])))",
+		},
+		{
+			name:             "targets down",
+			goal:             "find targets that are down",
+			expectedSkeleton: "up{<selector>} == 0",
+		},
+		{
+			name:             "requests per second is not treated as grouping",
+			goal:             "requests per second",
+			expectedSkeleton: "sum(rate(<metric>_total{<selector>}[5m]))",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			recipe := suggestPromQLRecipe(tc.goal)
+			require.Equal(t, tc.expectedSkeleton, recipe.Skeleton)
+			require.NotEmpty(t, recipe.Explanation)
+			require.NotEmpty(t, recipe.Functions)
+		})
+	}
+}
@@ -15,6 +15,7 @@ package mcp
 
 import (
 	"log/slog"
+	"maps"
 	"slices"
 	"strings"
 
@@ -58,6 +59,8 @@ var (
 	PrometheusBackends = []string{
 		"prometheus",
 		"thanos",
+		"mimir",
+		"victoriametrics",
 	}
 
 	// prometheusToolset contains all the tools to interact with standard
@@ -67,6 +70,14 @@ var (
 	// thanosToolset contains all the tools to interact with thanos as a
 	// prometheus HTTP API compatible backend.
 	thanosToolset map[string]toolRegistration
+
+	// mimirToolset contains all the tools to interact with mimir as a
+	// prometheus HTTP API compatible backend.
+	mimirToolset map[string]toolRegistration
+
+	// victoriaMetricsToolset contains all the tools to interact with
+	// victoriametrics as a prometheus HTTP API compatible backend.
+	victoriaMetricsToolset map[string]toolRegistration
 )
 
 // initPrometheusToolset initializes the prometheus toolset map. Called during
@@ -85,18 +96,72 @@ func initPrometheusToolset() {
 				mcp.AddTool(s, rangeQueryToolDef, c.RangeQueryHandler)
 			},
 		},
+		"query_stats_summary": {
+			tool: queryStatsSummaryToolDef,
+			register: func(s *mcp.Server, c *ServerContainer) {
+				mcp.AddTool(s, queryStatsSummaryToolDef, c.QueryStatsSummaryHandler)
+			},
+		},
 		"exemplar_query": {
 			tool: exemplarQueryToolDef,
 			register: func(s *mcp.Server, c *ServerContainer) {
 				mcp.AddTool(s, exemplarQueryToolDef, c.ExemplarQueryHandler)
 			},
 		},
+		"exemplar_coverage": {
+			tool: exemplarCoverageToolDef,
+			register: func(s *mcp.Server, c *ServerContainer) {
+				mcp.AddTool(s, exemplarCoverageToolDef, c.ExemplarCoverageHandler)
+			},
+		},
+		"query_explain": {
+			tool: queryExplainToolDef,
+			register: func(s *mcp.Server, c *ServerContainer) {
+				mcp.AddTool(s, queryExplainToolDef, c.QueryExplainHandler)
+			},
+		},
+		"validate_query": {
+			tool: validateQueryToolDef,
+			register: func(s *mcp.Server, c *ServerContainer) {
+				mcp.AddTool(s, validateQueryToolDef, c.ValidateQueryHandler)
+			},
+		},
 		"series": {
 			tool: seriesToolDef,
 			register: func(s *mcp.Server, c *ServerContainer) {
 				mcp.AddTool(s, seriesToolDef, c.SeriesHandler)
 			},
 		},
+		"series_count": {
+			tool: seriesCountToolDef,
+			register: func(s *mcp.Server, c *ServerContainer) {
+				mcp.AddTool(s, seriesCountToolDef, c.SeriesCountHandler)
+			},
+		},
+		"labels_cardinality": {
+			tool: labelsCardinalityToolDef,
+			register: func(s *mcp.Server, c *ServerContainer) {
+				mcp.AddTool(s, labelsCardinalityToolDef, c.LabelsCardinalityHandler)
+			},
+		},
+		"series_by_label_regex": {
+			tool: seriesByLabelRegexToolDef,
+			register: func(s *mcp.Server, c *ServerContainer) {
+				mcp.AddTool(s, seriesByLabelRegexToolDef, c.SeriesByLabelRegexHandler)
+			},
+		},
+		"find_metrics": {
+			tool: findMetricsToolDef,
+			register: func(s *mcp.Server, c *ServerContainer) {
+				mcp.AddTool(s, findMetricsToolDef, c.FindMetricsHandler)
+			},
+		},
+		"otlp_metrics_names": {
+			tool: otlpMetricsNamesToolDef,
+			register: func(s *mcp.Server, c *ServerContainer) {
+				mcp.AddTool(s, otlpMetricsNamesToolDef, c.OTLPMetricsNamesHandler)
+			},
+		},
 		"label_names": {
 			tool: labelNamesToolDef,
 			register: func(s *mcp.Server, c *ServerContainer) {
@@ -109,12 +174,24 @@ func initPrometheusToolset() {
 				mcp.AddTool(s, labelValuesToolDef, c.LabelValuesHandler)
 			},
 		},
+		"label_explosion": {
+			tool: labelExplosionToolDef,
+			register: func(s *mcp.Server, c *ServerContainer) {
+				mcp.AddTool(s, labelExplosionToolDef, c.LabelExplosionHandler)
+			},
+		},
 		"metric_metadata": {
 			tool: metricMetadataToolDef,
 			register: func(s *mcp.Server, c *ServerContainer) {
 				mcp.AddTool(s, metricMetadataToolDef, c.MetricMetadataHandler)
 			},
 		},
+		"metrics_with_help": {
+			tool: metricsWithHelpToolDef,
+			register: func(s *mcp.Server, c *ServerContainer) {
+				mcp.AddTool(s, metricsWithHelpToolDef, c.MetricsWithHelpHandler)
+			},
+		},
 		"targets_metadata": {
 			tool: targetsMetadataToolDef,
 			register: func(s *mcp.Server, c *ServerContainer) {
@@ -127,12 +204,78 @@ func initPrometheusToolset() {
 				mcp.AddTool(s, alertmanagersToolDef, c.AlertmanagersHandler)
 			},
 		},
+		"list_silences": {
+			tool: listSilencesToolDef,
+			register: func(s *mcp.Server, c *ServerContainer) {
+				mcp.AddTool(s, listSilencesToolDef, c.ListSilencesHandler)
+			},
+		},
+		"alertmanager_alerts": {
+			tool: alertmanagerAlertsToolDef,
+			register: func(s *mcp.Server, c *ServerContainer) {
+				mcp.AddTool(s, alertmanagerAlertsToolDef, c.AlertmanagerAlertsHandler)
+			},
+		},
+		"create_silence": {
+			tool: createSilenceToolDef,
+			register: func(s *mcp.Server, c *ServerContainer) {
+				mcp.AddTool(s, createSilenceToolDef, c.CreateSilenceHandler)
+			},
+		},
+		"alertmanager_status": {
+			tool: alertmanagerStatusToolDef,
+			register: func(s *mcp.Server, c *ServerContainer) {
+				mcp.AddTool(s, alertmanagerStatusToolDef, c.AlertmanagerStatusHandler)
+			},
+		},
 		"flags": {
 			tool: flagsToolDef,
 			register: func(s *mcp.Server, c *ServerContainer) {
 				mcp.AddTool(s, flagsToolDef, c.FlagsHandler)
 			},
 		},
+		"active_alerts_detail": {
+			tool: activeAlertsDetailToolDef,
+			register: func(s *mcp.Server, c *ServerContainer) {
+				mcp.AddTool(s, activeAlertsDetailToolDef, c.ActiveAlertsDetailHandler)
+			},
+		},
+		"target_churn": {
+			tool: targetChurnToolDef,
+			register: func(s *mcp.Server, c *ServerContainer) {
+				mcp.AddTool(s, targetChurnToolDef, c.TargetChurnHandler)
+			},
+		},
+		"sample_limits": {
+			tool: sampleLimitsToolDef,
+			register: func(s *mcp.Server, c *ServerContainer) {
+				mcp.AddTool(s, sampleLimitsToolDef, c.SampleLimitsHandler)
+			},
+		},
+		"fleet_health": {
+			tool: fleetHealthToolDef,
+			register: func(s *mcp.Server, c *ServerContainer) {
+				mcp.AddTool(s, fleetHealthToolDef, c.FleetHealthHandler)
+			},
+		},
+		"why_no_data": {
+			tool: whyNoDataToolDef,
+			register: func(s *mcp.Server, c *ServerContainer) {
+				mcp.AddTool(s, whyNoDataToolDef, c.WhyNoDataHandler)
+			},
+		},
+		"detect_gaps": {
+			tool: detectGapsToolDef,
+			register: func(s *mcp.Server, c *ServerContainer) {
+				mcp.AddTool(s, detectGapsToolDef, c.DetectGapsHandler)
+			},
+		},
+		"metrics_missing_metadata": {
+			tool: metricsMissingMetadataToolDef,
+			register: func(s *mcp.Server, c *ServerContainer) {
+				mcp.AddTool(s, metricsMissingMetadataToolDef, c.MetricsMissingMetadataHandler)
+			},
+		},
 		"list_alerts": {
 			tool: listAlertsToolDef,
 			register: func(s *mcp.Server, c *ServerContainer) {
@@ -157,6 +300,30 @@ func initPrometheusToolset() {
 				mcp.AddTool(s, configToolDef, c.ConfigHandler)
 			},
 		},
+		"scrape_configs": {
+			tool: scrapeConfigsToolDef,
+			register: func(s *mcp.Server, c *ServerContainer) {
+				mcp.AddTool(s, scrapeConfigsToolDef, c.ScrapeConfigsHandler)
+			},
+		},
+		"job_config": {
+			tool: jobConfigToolDef,
+			register: func(s *mcp.Server, c *ServerContainer) {
+				mcp.AddTool(s, jobConfigToolDef, c.JobConfigHandler)
+			},
+		},
+		"config_pending_changes": {
+			tool: configPendingChangesToolDef,
+			register: func(s *mcp.Server, c *ServerContainer) {
+				mcp.AddTool(s, configPendingChangesToolDef, c.ConfigPendingChangesHandler)
+			},
+		},
+		"config_diff": {
+			tool: configDiffToolDef,
+			register: func(s *mcp.Server, c *ServerContainer) {
+				mcp.AddTool(s, configDiffToolDef, c.ConfigDiffHandler)
+			},
+		},
 		"runtime_info": {
 			tool: runtimeInfoToolDef,
 			register: func(s *mcp.Server, c *ServerContainer) {
@@ -175,6 +342,18 @@ func initPrometheusToolset() {
 				mcp.AddTool(s, listTargetsToolDef, c.ListTargetsHandler)
 			},
 		},
+		"remote_read": {
+			tool: remoteReadToolDef,
+			register: func(s *mcp.Server, c *ServerContainer) {
+				mcp.AddTool(s, remoteReadToolDef, c.RemoteReadHandler)
+			},
+		},
+		"scrape_target": {
+			tool: scrapeTargetToolDef,
+			register: func(s *mcp.Server, c *ServerContainer) {
+				mcp.AddTool(s, scrapeTargetToolDef, c.ScrapeTargetHandler)
+			},
+		},
 		"wal_replay_status": {
 			tool: walReplayToolDef,
 			register: func(s *mcp.Server, c *ServerContainer) {
@@ -213,6 +392,18 @@ func initPrometheusToolset() {
 				mcp.AddTool(s, readyToolDef, c.ReadyHandler)
 			},
 		},
+		"status_overview": {
+			tool: statusOverviewToolDef,
+			register: func(s *mcp.Server, c *ServerContainer) {
+				mcp.AddTool(s, statusOverviewToolDef, c.StatusOverviewHandler)
+			},
+		},
+		"wait_ready": {
+			tool: waitReadyToolDef,
+			register: func(s *mcp.Server, c *ServerContainer) {
+				mcp.AddTool(s, waitReadyToolDef, c.WaitReadyHandler)
+			},
+		},
 		"reload": {
 			tool: reloadToolDef,
 			register: func(s *mcp.Server, c *ServerContainer) {
@@ -225,6 +416,24 @@ func initPrometheusToolset() {
 				mcp.AddTool(s, quitToolDef, c.QuitHandler)
 			},
 		},
+		"test_relabel": {
+			tool: testRelabelToolDef,
+			register: func(s *mcp.Server, c *ServerContainer) {
+				mcp.AddTool(s, testRelabelToolDef, c.TestRelabelHandler)
+			},
+		},
+		"mcp_config": {
+			tool: mcpConfigToolDef,
+			register: func(s *mcp.Server, c *ServerContainer) {
+				mcp.AddTool(s, mcpConfigToolDef, c.MCPConfigHandler)
+			},
+		},
+		"effective_limits": {
+			tool: effectiveLimitsToolDef,
+			register: func(s *mcp.Server, c *ServerContainer) {
+				mcp.AddTool(s, effectiveLimitsToolDef, c.EffectiveLimitsHandler)
+			},
+		},
 		// Documentation tools
 		"docs_list": {
 			tool: docsListToolDef,
@@ -244,6 +453,18 @@ func initPrometheusToolset() {
 				mcp.AddTool(s, docsSearchToolDef, c.DocsSearchHandler)
 			},
 		},
+		"promql_recipe": {
+			tool: promqlRecipeToolDef,
+			register: func(s *mcp.Server, c *ServerContainer) {
+				mcp.AddTool(s, promqlRecipeToolDef, c.PromQLRecipeHandler)
+			},
+		},
+		"examples": {
+			tool: examplesToolDef,
+			register: func(s *mcp.Server, c *ServerContainer) {
+				mcp.AddTool(s, examplesToolDef, c.ExamplesHandler)
+			},
+		},
 	}
 }
 
@@ -253,6 +474,11 @@ var thanosRemovedTools = append(
 	[]string{
 		"alertmanagers",
 		"config",
+		"config_diff",
+		"config_pending_changes",
+		"job_config",
+		"scrape_configs",
+		"sample_limits",
 		"wal_replay_status",
 		"reload",
 		"quit",
@@ -281,9 +507,129 @@ func initThanosToolset() {
 	}
 }
 
+// mimirRemovedTools lists tools from prometheusToolset that Mimir does not
+// support. Mimir has no scrape targets or central Prometheus config of its
+// own, and doesn't implement the TSDB status, admin, or management endpoints.
+var mimirRemovedTools = append(
+	PrometheusTsdbAdminTools,
+	[]string{
+		"alertmanagers",
+		"config",
+		"config_diff",
+		"config_pending_changes",
+		"fleet_health",
+		"flags",
+		"healthy",
+		"job_config",
+		"scrape_configs",
+		"list_targets",
+		"quit",
+		"ready",
+		"reload",
+		"runtime_info",
+		"sample_limits",
+		"scrape_target",
+		"status_overview",
+		"target_churn",
+		"targets_metadata",
+		"tsdb_stats",
+		"wait_ready",
+		"wal_replay_status",
+	}...,
+)
+
+// initMimirToolset initializes the mimir toolset map. Called during init to
+// avoid initialization cycles and control initialization order.
+//
+// It starts from prometheusToolset, removes unsupported tools, and adds
+// Mimir-specific tools (list_tenants).
+func initMimirToolset() {
+	mimirToolset = make(map[string]toolRegistration)
+	for name, tool := range prometheusToolset {
+		if !slices.Contains(mimirRemovedTools, name) {
+			mimirToolset[name] = tool
+		}
+	}
+
+	// Add Mimir-specific tools.
+	mimirToolset["list_tenants"] = toolRegistration{
+		tool: listTenantsToolDef,
+		register: func(s *mcp.Server, c *ServerContainer) {
+			mcp.AddTool(s, listTenantsToolDef, c.ListTenantsHandler)
+		},
+	}
+}
+
+// victoriaMetricsRemovedTools lists tools from prometheusToolset that
+// VictoriaMetrics does not support. VictoriaMetrics has no lifecycle
+// management or Prometheus status endpoints, and its admin API differs from
+// the Prometheus TSDB admin API. Its TSDB status is covered by vm_cardinality.
+var victoriaMetricsRemovedTools = append(
+	PrometheusTsdbAdminTools,
+	[]string{
+		"alertmanagers",
+		"config",
+		"config_diff",
+		"config_pending_changes",
+		"flags",
+		"job_config",
+		"scrape_configs",
+		"quit",
+		"reload",
+		"runtime_info",
+		"sample_limits",
+		"targets_metadata",
+		"tsdb_stats",
+		"wal_replay_status",
+	}...,
+)
+
+// initVictoriaMetricsToolset initializes the victoriametrics toolset map.
+// Called during init to avoid initialization cycles and control
+// initialization order.
+//
+// It starts from prometheusToolset, removes unsupported tools, and adds
+// VictoriaMetrics-specific tools (vm_cardinality).
+func initVictoriaMetricsToolset() {
+	victoriaMetricsToolset = make(map[string]toolRegistration)
+	for name, tool := range prometheusToolset {
+		if !slices.Contains(victoriaMetricsRemovedTools, name) {
+			victoriaMetricsToolset[name] = tool
+		}
+	}
+
+	// Add VictoriaMetrics-specific tools.
+	victoriaMetricsToolset["vm_cardinality"] = toolRegistration{
+		tool: vmCardinalityToolDef,
+		register: func(s *mcp.Server, c *ServerContainer) {
+			mcp.AddTool(s, vmCardinalityToolDef, c.VMCardinalityHandler)
+		},
+	}
+}
+
 func init() {
 	initPrometheusToolset()
 	initThanosToolset()
+	initMimirToolset()
+	initVictoriaMetricsToolset()
+}
+
+// backendSupportsTool reports whether the given Prometheus backend has the
+// named tool in its toolset. Backends without custom tool support are
+// treated like Prometheus.
+func backendSupportsTool(backend, tool string) bool {
+	toolset := prometheusToolset
+	switch strings.ToLower(backend) {
+	case "thanos":
+		toolset = thanosToolset
+	case "mimir":
+		toolset = mimirToolset
+	case "victoriametrics":
+		toolset = victoriaMetricsToolset
+	}
+
+	_, ok := toolset[tool]
+	return ok
 }
 
 // registerTools registers the given toolset with the MCP server.
@@ -295,6 +641,7 @@ func registerTools(server *mcp.Server, container *ServerContainer, toolset []too
 
 type toolsetConfig struct {
 	enabledTools      []string
+	disabledTools     []string
 	prometheusBackend string
 	logger            *slog.Logger
 }
@@ -354,14 +701,71 @@ func getToolset(cfg toolsetConfig) map[string]toolRegistration {
 			backendToolset[name] = tool
 		}
 		toolset = backendToolset
+	case "mimir":
+		logger.Info("Setting tools based on provided prometheus backend", "backend", backend)
+		backendToolset := make(map[string]toolRegistration)
+		for name, tool := range mimirToolset {
+			backendToolset[name] = tool
+		}
+		toolset = backendToolset
+	case "victoriametrics":
+		logger.Info("Setting tools based on provided prometheus backend", "backend", backend)
+		backendToolset := make(map[string]toolRegistration)
+		for name, tool := range victoriaMetricsToolset {
+			backendToolset[name] = tool
+		}
+		toolset = backendToolset
 	default:
 		logger.Warn("Prometheus backend does not have custom tool support, keeping existing toolset",
 			"backend", backend, "toolset", cfg.enabledTools)
 	}
 
+	// Disabled tools are removed last, so they win over both the allow-list
+	// and the backend toolset.
+	for _, toolName := range cfg.disabledTools {
+		if _, ok := toolset[toolName]; !ok {
+			logger.Warn("Failed to find tool to disable in toolset", "tool_name", toolName)
+			continue
+		}
+
+		logger.Debug("Removing disabled tool from toolset", "tool_name", toolName)
+		delete(toolset, toolName)
+	}
+
 	return toolset
 }
 
+// toolsetNames returns the sorted names of the tools in the given toolset,
+// along with the sorted names of all other known tools across backends.
+func toolsetNames(toolset map[string]toolRegistration) (enabled, disabled []string) {
+	enabled = slices.Sorted(maps.Keys(toolset))
+
+	known := slices.Concat(
+		slices.Collect(maps.Keys(prometheusToolset)),
+		slices.Collect(maps.Keys(thanosToolset)),
+		slices.Collect(maps.Keys(mimirToolset)),
+		slices.Collect(maps.Keys(victoriaMetricsToolset)),
+	)
+	slices.Sort(known)
+	for _, name := range slices.Compact(known) {
+		if _, ok := toolset[name]; !ok {
+			disabled = append(disabled, name)
+		}
+	}
+
+	return enabled, disabled
+}
+
+// toolsetTools returns the definitions of the tools in the given toolset,
+// sorted by name.
+func toolsetTools(toolset map[string]toolRegistration) []*mcp.Tool {
+	tools := make([]*mcp.Tool, 0, len(toolset))
+	for _, name := range slices.Sorted(maps.Keys(toolset)) {
+		tools = append(tools, toolset[name].tool)
+	}
+	return tools
+}
+
 // toolsetToToolRegistrationSlice converts a toolset map to a slice of toolRegistrations.
 // This is useful for passing to RegisterTools which expects a slice.
 func toolsetToToolRegistrationSlice(toolset map[string]toolRegistration) []toolRegistration {
@@ -143,6 +143,58 @@ func TestGetToolset(t *testing.T) {
 		require.Len(t, toolset, len(thanosToolset))
 	})
 
+	t.Run("mimir backend overrides toolset with mimir-specific tools", func(t *testing.T) {
+		cfg := toolsetConfig{
+			enabledTools:      []string{"all"},
+			prometheusBackend: "mimir",
+			logger:            slog.Default(),
+		}
+
+		toolset := getToolset(cfg)
+		names := getToolNames(toolset)
+
+		// Tools for endpoints Mimir doesn't implement should NOT be present.
+		for _, tool := range mimirRemovedTools {
+			require.NotContains(t, names, tool)
+		}
+
+		// Shared tools should still be present.
+		require.Contains(t, names, "query")
+		require.Contains(t, names, "list_rules")
+		require.Contains(t, names, "build_info")
+		require.NotContains(t, names, "list_stores")
+
+		// Mimir-specific tools should be present.
+		require.Contains(t, names, "list_tenants")
+
+		require.Len(t, toolset, len(mimirToolset))
+	})
+
+	t.Run("victoriametrics backend overrides toolset with victoriametrics-specific tools", func(t *testing.T) {
+		cfg := toolsetConfig{
+			enabledTools:      []string{"core"},
+			prometheusBackend: "victoriametrics",
+			logger:            slog.Default(),
+		}
+
+		toolset := getToolset(cfg)
+		names := getToolNames(toolset)
+
+		// VictoriaMetrics-specific tool should be present.
+		require.Contains(t, names, "vm_cardinality")
+
+		// Lifecycle and TSDB admin tools should NOT be present.
+		for _, tool := range victoriaMetricsRemovedTools {
+			require.NotContains(t, names, tool)
+		}
+
+		// Shared tools should still be present.
+		require.Contains(t, names, "query")
+		require.Contains(t, names, "list_targets")
+
+		require.Len(t, toolset, len(victoriaMetricsToolset))
+	})
+
 	t.Run("prometheus backend explicitly loads full prometheus toolset", func(t *testing.T) {
 		cfg := toolsetConfig{
 			enabledTools:      []string{"core"}, // Would normally just load core
@@ -244,6 +296,64 @@ func TestGetToolset(t *testing.T) {
 		})
 	})
 
+	t.Run("disabled tools are removed from all tools", func(t *testing.T) {
+		cfg := toolsetConfig{
+			enabledTools:  []string{"all"},
+			disabledTools: []string{"quit", "reload"},
+			logger:        slog.Default(),
+		}
+
+		toolset := getToolset(cfg)
+		names := getToolNames(toolset)
+
+		require.NotContains(t, names, "quit")
+		require.NotContains(t, names, "reload")
+		require.Len(t, toolset, len(prometheusToolset)-2)
+	})
+
+	t.Run("disabled tools win over enabled and core tools", func(t *testing.T) {
+		cfg := toolsetConfig{
+			enabledTools:  []string{"alertmanagers", "config"},
+			disabledTools: []string{"config", "docs_search"},
+			logger:        slog.Default(),
+		}
+
+		toolset := getToolset(cfg)
+		names := getToolNames(toolset)
+
+		require.Contains(t, names, "alertmanagers")
+		require.NotContains(t, names, "config")
+		require.NotContains(t, names, "docs_search")
+		require.Len(t, toolset, len(CoreTools))
+	})
+
+	t.Run("disabled tools apply after backend selection", func(t *testing.T) {
+		cfg := toolsetConfig{
+			enabledTools:      []string{"core"},
+			disabledTools:     []string{"list_stores"},
+			prometheusBackend: "thanos",
+			logger:            slog.Default(),
+		}
+
+		toolset := getToolset(cfg)
+		names := getToolNames(toolset)
+
+		require.NotContains(t, names, "list_stores")
+		require.Len(t, toolset, len(thanosToolset)-1)
+	})
+
+	t.Run("unknown disabled tools are ignored with warning", func(t *testing.T) {
+		cfg := toolsetConfig{
+			enabledTools:  []string{"core"},
+			disabledTools: []string{"nonexistent_tool"},
+			logger:        slog.Default(),
+		}
+
+		toolset := getToolset(cfg)
+
+		require.Len(t, toolset, len(CoreTools))
+	})
+
 	t.Run("mixed case backend prometheus", func(t *testing.T) {
 		cfg := toolsetConfig{
 			enabledTools:      []string{"core"},
@@ -257,6 +367,26 @@ func TestGetToolset(t *testing.T) {
 	})
 }
 
+func TestToolsetNames(t *testing.T) {
+	toolset := getToolset(toolsetConfig{
+		enabledTools: []string{"core"},
+		logger:       slog.Default(),
+	})
+
+	enabled, disabled := toolsetNames(toolset)
+	require.True(t, slices.IsSorted(enabled))
+	require.True(t, slices.IsSorted(disabled))
+	require.ElementsMatch(t, CoreTools, enabled)
+
+	// Disabled tools cover every other tool known to any backend.
+	require.Contains(t, disabled, "config")
+	require.Contains(t, disabled, "list_stores")
+	require.Contains(t, disabled, "vm_cardinality")
+	for _, name := range enabled {
+		require.NotContains(t, disabled, name)
+	}
+}
+
 // TestToolsetContents verifies the actual contents of the toolsets.
 func TestToolsetContents(t *testing.T) {
 	t.Run("thanosToolset excludes prometheus-only tools", func(t *testing.T) {
@@ -274,6 +404,13 @@ func TestToolsetContents(t *testing.T) {
 		}
 	})
 
+	t.Run("mimirToolset excludes TSDB admin tools", func(t *testing.T) {
+		for _, tool := range PrometheusTsdbAdminTools {
+			_, exists := mimirToolset[tool]
+			require.False(t, exists, "mimir toolset should not contain %s", tool)
+		}
+	})
+
 	t.Run("thanosToolset includes thanos-specific tools", func(t *testing.T) {
 		_, exists := thanosToolset["list_stores"]
 		require.True(t, exists, "thanos toolset should contain list_stores")
@@ -294,6 +431,39 @@ func TestToolsetContents(t *testing.T) {
 	})
 }
 
+// TestRegisterResourcesBackend verifies that resources are only registered
+// for backends with the tool that reads the same API.
+func TestRegisterResourcesBackend(t *testing.T) {
+	testCases := []struct {
+		backend       string
+		wantWalReplay bool
+	}{
+		{backend: "", wantWalReplay: true},
+		{backend: "prometheus", wantWalReplay: true},
+		{backend: "thanos", wantWalReplay: false},
+		{backend: "mimir", wantWalReplay: false},
+		{backend: "victoriametrics", wantWalReplay: false},
+		{backend: "unknown", wantWalReplay: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.backend, func(t *testing.T) {
+			ts := mcptest.NewTestServer(t)
+			registerResources(ts.Server, newTestContainer(nil), tc.backend)
+
+			result, err := ts.ListResources(ts.Context())
+			require.NoError(t, err)
+
+			var uris []string
+			for _, r := range result.Resources {
+				uris = append(uris, r.URI)
+			}
+			require.Contains(t, uris, alertsResource.URI)
+			require.Equal(t, tc.wantWalReplay, slices.Contains(uris, walReplayResource.URI))
+		})
+	}
+}
+
 // TestToolInputSchemaProperties verifies that every tool's InputSchema
 // includes a "properties" key after full MCP registration. This is a
 // regression test for OpenAI API compatibility -- the OpenAI-compatible
@@ -310,8 +480,10 @@ func TestToolInputSchemaProperties(t *testing.T) {
 	container := newTestContainer(nil)
 
 	toolsets := map[string]map[string]toolRegistration{
-		"prometheus": prometheusToolset,
-		"thanos":     thanosToolset,
+		"prometheus":      prometheusToolset,
+		"thanos":          thanosToolset,
+		"mimir":           mimirToolset,
+		"victoriametrics": victoriaMetricsToolset,
 	}
 
 	for tsName, toolset := range toolsets {
@@ -340,3 +512,46 @@ func TestToolInputSchemaProperties(t *testing.T) {
 		})
 	}
 }
+
+// TestToolInputSchemaExamples verifies that the example argument values of
+// tools with explicit input schemas reach clients, and that required
+// parameters are still validated.
+func TestToolInputSchemaExamples(t *testing.T) {
+	t.Parallel()
+
+	container := newTestContainer(&MockPrometheusAPI{})
+	ts := mcptest.NewTestServer(t)
+	for _, name := range []string{"query", "range_query", "query_stats_summary"} {
+		prometheusToolset[name].register(ts.Server, container)
+	}
+
+	result, err := ts.ListTools(ts.Context())
+	require.NoError(t, err)
+
+	type property struct {
+		Examples []any `json:"examples"`
+	}
+	schemas := make(map[string]struct {
+		Properties map[string]property `json:"properties"`
+		Required   []string            `json:"required"`
+	})
+	for _, tool := range result.Tools {
+		data, err := json.Marshal(tool.InputSchema)
+		require.NoError(t, err)
+		schema := schemas[tool.Name]
+		require.NoError(t, json.Unmarshal(data, &schema))
+		schemas[tool.Name] = schema
+	}
+
+	require.Equal(t, timestampExamples, schemas["query"].Properties["timestamp"].Examples)
+	require.Equal(t, []string{"query"}, schemas["query"].Required)
+	require.Equal(t, stepExamples, schemas["range_query"].Properties["step"].Examples)
+	require.Equal(t, timestampExamples, schemas["range_query"].Properties["start_time"].Examples)
+	require.Equal(t, timestampExamples, schemas["range_query"].Properties["end_time"].Examples)
+	require.Equal(t, timestampExamples, schemas["query_stats_summary"].Properties["timestamp"].Examples)
+
+	callResult, err := ts.CallTool(ts.Context(), "query", map[string]any{"timestamp": "now-1h"})
+	require.NoError(t, err)
+	require.True(t, callResult.IsError)
+	require.Contains(t, toolResultErrorText(callResult), "query")
+}
@@ -0,0 +1,188 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/golang/snappy"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	promv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/prompb"
+)
+
+var errRemoteReadURLNotConfigured = errors.New("a remote read URL must be configured with the `--prometheus.remote-read-url` flag")
+
+// remoteReadVersion is the version of the remote read protocol sent in the
+// X-Prometheus-Remote-Read-Version header.
+const remoteReadVersion = "0.1.0"
+
+// remoteReadHistogramsWarning is added to the result when series contained
+// native histogram samples, which the remote_read tool does not decode.
+const remoteReadHistogramsWarning = "native histogram samples are not supported by the remote_read tool and were skipped"
+
+// remoteReadMatcherTypes maps matcher types to the remote read LabelMatcher
+// enum.
+var remoteReadMatcherTypes = map[labels.MatchType]prompb.LabelMatcher_Type{
+	labels.MatchEqual:     prompb.LabelMatcher_EQ,
+	labels.MatchNotEqual:  prompb.LabelMatcher_NEQ,
+	labels.MatchRegexp:    prompb.LabelMatcher_RE,
+	labels.MatchNotRegexp: prompb.LabelMatcher_NRE,
+}
+
+// RemoteReadHandler handles the remote read tool.
+func (s *ServerContainer) RemoteReadHandler(ctx context.Context, req *mcp.CallToolRequest, input RemoteReadInput) (*mcp.CallToolResult, any, error) {
+	if input.Selector == "" {
+		return newToolErrorResult("selector parameter is required"), nil, nil
+	}
+	matchers, err := promqlParser.ParseMetricSelector(input.Selector)
+	if err != nil {
+		return newToolErrorResult(fmt.Sprintf("failed to parse selector: %v", err)), nil, nil
+	}
+
+	endTs, err := parseTimeWithDefault(input.EndTime, time.Now())
+	if err != nil {
+		return newToolErrorResult(fmt.Sprintf("failed to parse end_time: %v", err)), nil, nil
+	}
+	startTs, err := parseTimeWithDefault(input.StartTime, endTs.Add(s.getLookbackDelta()))
+	if err != nil {
+		return newToolErrorResult(fmt.Sprintf("failed to parse start_time: %v", err)), nil, nil
+	}
+	if startTs.After(endTs) {
+		return newToolErrorResult("start_time must not be after end_time"), nil, nil
+	}
+
+	truncationLimit := s.GetEffectiveTruncationLimit(input.TruncationLimit)
+	result, err := s.remoteReadAPICall(ctx, matchers, startTs, endTs, truncationLimit)
+	if err != nil {
+		return newToolErrorResult("failed making remote read call: " + err.Error()), nil, nil
+	}
+	return newToolTextResult(result), nil, nil
+}
+
+func (s *ServerContainer) remoteReadAPICall(ctx context.Context, matchers []*labels.Matcher, start, end time.Time, truncationLimit int) (string, error) {
+	if s.remoteReadURL == "" {
+		return "", errRemoteReadURLNotConfigured
+	}
+	u, err := url.Parse(s.remoteReadURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse remote read URL: %w", err)
+	}
+
+	body, err := encodeRemoteReadRequest(matchers, start, end)
+	if err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.getAPITimeout(ctx))
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.remoteReadURL, bytes.NewReader(snappy.Encode(nil, body)))
+	if err != nil {
+		return "", fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("X-Prometheus-Remote-Read-Version", remoteReadVersion)
+
+	_, rt := s.GetAPIClient(ctx)
+	compressed, err := s.fetchHTTPResponseBody(req, rt, s.remoteReadURL, u.Path)
+	if err != nil {
+		return "", err
+	}
+
+	decodedLen, err := snappy.DecodedLen(compressed)
+	if err != nil {
+		return "", fmt.Errorf("failed to decompress remote read response: %w", err)
+	}
+	if s.maxResponseBytes > 0 && int64(decodedLen) > s.maxResponseBytes {
+		return "", fmt.Errorf("decompressed remote read response of %d bytes exceeds the limit of %d bytes set by --http.max-response-bytes", decodedLen, s.maxResponseBytes)
+	}
+	decoded, err := snappy.Decode(nil, compressed)
+	if err != nil {
+		return "", fmt.Errorf("failed to decompress remote read response: %w", err)
+	}
+
+	matrix, skippedHistograms, err := decodeRemoteReadResponse(decoded)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode remote read response: %w", err)
+	}
+
+	var warnings promv1.Warnings
+	if skippedHistograms {
+		warnings = append(warnings, remoteReadHistogramsWarning)
+	}
+	result, _, err := s.formatQueryResult(matrix, warnings, nil, nil, resultSort{}, "", truncationLimit)
+	return result, err
+}
+
+// encodeRemoteReadRequest encodes a ReadRequest with a single query for the
+// given matchers and time range. Only sampled responses are requested.
+func encodeRemoteReadRequest(matchers []*labels.Matcher, start, end time.Time) ([]byte, error) {
+	query := &prompb.Query{
+		StartTimestampMs: start.UnixMilli(),
+		EndTimestampMs:   end.UnixMilli(),
+	}
+	for _, m := range matchers {
+		matchType, ok := remoteReadMatcherTypes[m.Type]
+		if !ok {
+			return nil, fmt.Errorf("unsupported matcher type %q", m.Type)
+		}
+		query.Matchers = append(query.Matchers, &prompb.LabelMatcher{Type: matchType, Name: m.Name, Value: m.Value})
+	}
+
+	req := &prompb.ReadRequest{
+		Queries:               []*prompb.Query{query},
+		AcceptedResponseTypes: []prompb.ReadRequest_ResponseType{prompb.ReadRequest_SAMPLES},
+	}
+	return req.Marshal()
+}
+
+// decodeRemoteReadResponse decodes the series of all query results of a
+// ReadResponse into a matrix. It reports whether any native histogram
+// samples were skipped.
+func decodeRemoteReadResponse(b []byte) (model.Matrix, bool, error) {
+	var resp prompb.ReadResponse
+	if err := resp.Unmarshal(b); err != nil {
+		return nil, false, err
+	}
+
+	matrix := model.Matrix{}
+	skippedHistograms := false
+	for _, result := range resp.Results {
+		for _, ts := range result.Timeseries {
+			stream := &model.SampleStream{Metric: make(model.Metric, len(ts.Labels))}
+			for _, l := range ts.Labels {
+				stream.Metric[model.LabelName(l.Name)] = model.LabelValue(l.Value)
+			}
+			for _, sample := range ts.Samples {
+				stream.Values = append(stream.Values, model.SamplePair{
+					Timestamp: model.Time(sample.Timestamp),
+					Value:     model.SampleValue(sample.Value),
+				})
+			}
+			skippedHistograms = skippedHistograms || len(ts.Histograms) > 0
+			matrix = append(matrix, stream)
+		}
+	}
+	return matrix, skippedHistograms, nil
+}
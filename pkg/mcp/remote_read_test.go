@@ -0,0 +1,215 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcp
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/snappy"
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prometheus/prometheus-mcp/pkg/mcp/mcptest"
+)
+
+// remoteReadTestSeries is a series returned by the fake remote read endpoint.
+type remoteReadTestSeries struct {
+	labels     [][2]string
+	samples    [][2]float64 // timestamp in ms, value
+	histograms bool
+}
+
+// encodeRemoteReadTestResponse encodes a ReadResponse with a single query
+// result holding the given series.
+func encodeRemoteReadTestResponse(t *testing.T, series []remoteReadTestSeries) []byte {
+	t.Helper()
+
+	result := &prompb.QueryResult{}
+	for _, s := range series {
+		var ts prompb.TimeSeries
+		for _, l := range s.labels {
+			ts.Labels = append(ts.Labels, prompb.Label{Name: l[0], Value: l[1]})
+		}
+		for _, smpl := range s.samples {
+			ts.Samples = append(ts.Samples, prompb.Sample{Timestamp: int64(smpl[0]), Value: smpl[1]})
+		}
+		if s.histograms {
+			ts.Histograms = append(ts.Histograms, prompb.Histogram{})
+		}
+		result.Timeseries = append(result.Timeseries, &ts)
+	}
+
+	b, err := (&prompb.ReadResponse{Results: []*prompb.QueryResult{result}}).Marshal()
+	require.NoError(t, err)
+	return b
+}
+
+func TestRemoteReadHandler(t *testing.T) {
+	t.Parallel()
+
+	start := time.Unix(1767225600, 0)
+	end := start.Add(time.Minute)
+	series := []remoteReadTestSeries{
+		{
+			labels:  [][2]string{{"__name__", "up"}, {"job", "api"}},
+			samples: [][2]float64{{float64(start.UnixMilli()), 1}, {float64(start.Add(30 * time.Second).UnixMilli()), 0}},
+		},
+		{
+			labels:     [][2]string{{"__name__", "up"}, {"job", "db"}},
+			samples:    [][2]float64{{float64(start.UnixMilli()), 1}},
+			histograms: true,
+		},
+	}
+
+	testCases := []struct {
+		name           string
+		args           map[string]any
+		handler        http.HandlerFunc
+		validateResult func(t *testing.T, result string, isError bool, err error)
+	}{
+		{
+			name: "decodes series and sends matchers",
+			args: map[string]any{
+				"selector":   `up{job=~"api|db",instance!=""}`,
+				"start_time": start.Format(time.RFC3339),
+				"end_time":   end.Format(time.RFC3339),
+			},
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				require.Equal(t, http.MethodPost, r.Method)
+				require.Equal(t, "/api/v1/read", r.URL.Path)
+				require.Equal(t, "snappy", r.Header.Get("Content-Encoding"))
+				require.Equal(t, "application/x-protobuf", r.Header.Get("Content-Type"))
+				require.Equal(t, remoteReadVersion, r.Header.Get("X-Prometheus-Remote-Read-Version"))
+
+				compressed, err := io.ReadAll(r.Body)
+				require.NoError(t, err)
+				body, err := snappy.Decode(nil, compressed)
+				require.NoError(t, err)
+
+				var readReq prompb.ReadRequest
+				require.NoError(t, readReq.Unmarshal(body))
+				require.Equal(t, []prompb.ReadRequest_ResponseType{prompb.ReadRequest_SAMPLES}, readReq.AcceptedResponseTypes)
+				require.Len(t, readReq.Queries, 1)
+				require.Equal(t, start.UnixMilli(), readReq.Queries[0].StartTimestampMs)
+				require.Equal(t, end.UnixMilli(), readReq.Queries[0].EndTimestampMs)
+				require.ElementsMatch(t, []*prompb.LabelMatcher{
+					{Type: prompb.LabelMatcher_RE, Name: "job", Value: "api|db"},
+					{Type: prompb.LabelMatcher_NEQ, Name: "instance", Value: ""},
+					{Type: prompb.LabelMatcher_EQ, Name: "__name__", Value: "up"},
+				}, readReq.Queries[0].Matchers)
+
+				w.Header().Set("Content-Type", "application/x-protobuf")
+				w.Header().Set("Content-Encoding", "snappy")
+				_, _ = w.Write(snappy.Encode(nil, encodeRemoteReadTestResponse(t, series)))
+			},
+			validateResult: func(t *testing.T, result string, isError bool, err error) {
+				require.NoError(t, err)
+				require.False(t, isError)
+
+				var resp queryAPIResponse
+				require.NoError(t, json.Unmarshal([]byte(result), &resp))
+				require.Contains(t, resp.Result, `up{job="api"}`)
+				require.Contains(t, resp.Result, "1 @[1767225600]")
+				require.Contains(t, resp.Result, "0 @[1767225630]")
+				require.Contains(t, resp.Result, `up{job="db"}`)
+				require.Equal(t, []string{remoteReadHistogramsWarning}, []string(resp.Warnings))
+			},
+		},
+		{
+			name: "remote read URL not configured",
+			args: map[string]any{"selector": "up"},
+			validateResult: func(t *testing.T, result string, isError bool, err error) {
+				require.NoError(t, err)
+				require.True(t, isError)
+				require.Contains(t, result, "--prometheus.remote-read-url")
+			},
+		},
+		{
+			name: "endpoint error",
+			args: map[string]any{"selector": "up"},
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, "remote read is disabled", http.StatusServiceUnavailable)
+			},
+			validateResult: func(t *testing.T, result string, isError bool, err error) {
+				require.NoError(t, err)
+				require.True(t, isError)
+				require.Contains(t, result, "failed making remote read call")
+			},
+		},
+		{
+			name: "invalid selector",
+			args: map[string]any{"selector": "up{"},
+			validateResult: func(t *testing.T, result string, isError bool, err error) {
+				require.NoError(t, err)
+				require.True(t, isError)
+				require.Contains(t, result, "failed to parse selector")
+			},
+		},
+		{
+			name: "start after end",
+			args: map[string]any{"selector": "up", "start_time": end.Format(time.RFC3339), "end_time": start.Format(time.RFC3339)},
+			validateResult: func(t *testing.T, result string, isError bool, err error) {
+				require.NoError(t, err)
+				require.True(t, isError)
+				require.Contains(t, result, "start_time must not be after end_time")
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			container := newTestContainer(&MockPrometheusAPI{})
+			if tc.handler != nil {
+				srv := httptest.NewServer(tc.handler)
+				t.Cleanup(srv.Close)
+				container.remoteReadURL = srv.URL + "/api/v1/read"
+			}
+
+			ts := mcptest.NewTestServer(t)
+			mcptest.AddTool(ts, remoteReadToolDef, container.RemoteReadHandler)
+
+			result, err := ts.CallTool(ts.Context(), "remote_read", tc.args)
+
+			resultText := mcptest.GetResultText(result)
+			isError := result != nil && result.IsError
+			tc.validateResult(t, resultText, isError, err)
+		})
+	}
+}
+
+func TestRemoteReadForwardsAuth(t *testing.T) {
+	t.Parallel()
+
+	var gotAuth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		_, _ = w.Write(snappy.Encode(nil, encodeRemoteReadTestResponse(t, nil)))
+	}))
+	t.Cleanup(srv.Close)
+
+	container := newTestContainer(&MockPrometheusAPI{})
+	container.remoteReadURL = srv.URL + "/api/v1/read"
+
+	matchers, err := promqlParser.ParseMetricSelector("up")
+	require.NoError(t, err)
+	ctx := addAuthToContext(t.Context(), "Bearer secret")
+	_, err = container.remoteReadAPICall(ctx, matchers, time.Unix(0, 0), time.Unix(60, 0), 0)
+	require.NoError(t, err)
+	require.Equal(t, "Bearer secret", gotAuth)
+}
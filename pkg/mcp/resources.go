@@ -18,6 +18,8 @@ import (
 	"errors"
 	"fmt"
 	"net/url"
+	"os"
+	"slices"
 	"strings"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
@@ -42,12 +44,81 @@ var (
 		Description: "Read the named markdown file containing official Prometheus documentation from the prometheus/docs repo",
 		MIMEType:    "text/markdown",
 	}
+
+	instructionsResource = &mcp.Resource{
+		URI:         resourcePrefix + "instructions",
+		Name:        "Operator Instructions",
+		Description: "Deployment-specific guidance from the operator of this MCP server, such as which namespaces to query or which query patterns to prefer. Read it before querying Prometheus and follow it.",
+		MIMEType:    "text/markdown",
+	}
+
+	walReplayResource = &mcp.Resource{
+		URI:         resourcePrefix + "walreplay",
+		Name:        "WAL Replay Status",
+		Description: "Status of the Write-Ahead Log (WAL) replay of the Prometheus server, formatted like the output of the wal_replay_status tool",
+		MIMEType:    "text/plain",
+	}
+
+	alertsResource = &mcp.Resource{
+		URI:         resourcePrefix + "alerts",
+		Name:        "Active Alerts",
+		Description: "All active alerts of the Prometheus server, formatted like the output of the list_alerts tool",
+		MIMEType:    "text/plain",
+	}
+
+	rulesResource = &mcp.Resource{
+		URI:         resourcePrefix + "rules",
+		Name:        "Alerting and Recording Rules",
+		Description: "All alerting and recording rule groups loaded by the Prometheus server, formatted like the output of the list_rules tool",
+		MIMEType:    "text/plain",
+	}
+
+	toolsResource = &mcp.Resource{
+		URI:         resourcePrefix + "tools",
+		Name:        "Available Tools",
+		Description: "Names and descriptions of the tools registered by this MCP server after applying the configured backend and tool allow/deny lists, including whether dangerous tools are enabled",
+		MIMEType:    "text/plain",
+	}
 )
 
-// registerResources registers all MCP resources with the server.
-func registerResources(server *mcp.Server, container *ServerContainer) {
+// defaultOperatorInstructionsAsset is the embedded fallback for the
+// instructions resource when no `--mcp.instructions-file` is given.
+const defaultOperatorInstructionsAsset = "assets/operator_instructions.md"
+
+// loadOperatorInstructions reads the operator instructions from path, or the
+// built-in default if path is empty.
+func loadOperatorInstructions(path string) (string, error) {
+	if path == "" {
+		content, err := assets.ReadFile(defaultOperatorInstructionsAsset)
+		if err != nil {
+			return "", fmt.Errorf("failed to read default instructions from embedded assets: %w", err)
+		}
+		return string(content), nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read instructions file: %w", err)
+	}
+	if strings.TrimSpace(string(content)) == "" {
+		return "", fmt.Errorf("instructions file %q is empty", path)
+	}
+	return string(content), nil
+}
+
+// registerResources registers all MCP resources with the server. Resources
+// backed by an API the Prometheus backend doesn't have are skipped, the
+// same as the tools that read them.
+func registerResources(server *mcp.Server, container *ServerContainer, backend string) {
 	// Add static resources
 	server.AddResource(docsListResource, container.DocsListResourceHandler)
+	server.AddResource(instructionsResource, container.InstructionsResourceHandler)
+	if backendSupportsTool(backend, "wal_replay_status") {
+		server.AddResource(walReplayResource, container.WalReplayResourceHandler)
+	}
+	server.AddResource(alertsResource, container.AlertsResourceHandler)
+	server.AddResource(rulesResource, container.RulesResourceHandler)
+	server.AddResource(toolsResource, container.ToolsResourceHandler)
 
 	// Add resource template for reading specific doc files
 	server.AddResourceTemplate(docsReadResourceTemplate, container.DocsReadResourceHandler)
@@ -92,7 +163,7 @@ func (s *ServerContainer) DocsReadResourceHandler(ctx context.Context, req *mcp.
 		return nil, errors.New("at least 1 filename is required when requesting docs to read")
 	}
 
-	content, err := s.GetDocFileContent(filename)
+	content, err := s.GetDocFileContent(ctx, filename)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read file from docs: %w", err)
 	}
@@ -107,3 +178,99 @@ func (s *ServerContainer) DocsReadResourceHandler(ctx context.Context, req *mcp.
 		},
 	}, nil
 }
+
+// InstructionsResourceHandler handles the operator instructions resource
+// request.
+func (s *ServerContainer) InstructionsResourceHandler(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+	return &mcp.ReadResourceResult{
+		Contents: []*mcp.ResourceContents{
+			{
+				URI:      req.Params.URI,
+				MIMEType: "text/markdown",
+				Text:     s.operatorInstructions,
+			},
+		},
+	}, nil
+}
+
+// WalReplayResourceHandler handles the WAL replay status resource request.
+func (s *ServerContainer) WalReplayResourceHandler(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+	status, err := s.walReplayAPICall(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get WAL replay status: %w", err)
+	}
+
+	return newTextResourceResult(req.Params.URI, status), nil
+}
+
+// AlertsResourceHandler handles the alerts resource request.
+func (s *ServerContainer) AlertsResourceHandler(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+	alerts, err := s.listAlertsAPICall(ctx, "", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get alerts: %w", err)
+	}
+
+	return newTextResourceResult(req.Params.URI, alerts), nil
+}
+
+// RulesResourceHandler handles the rules resource request.
+func (s *ServerContainer) RulesResourceHandler(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+	rules, err := s.rulesAPICall(ctx, "", "", "", false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rules: %w", err)
+	}
+
+	return newTextResourceResult(req.Params.URI, rules), nil
+}
+
+// toolsResourceEntry describes a registered tool in the tools resource.
+type toolsResourceEntry struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Enabled     bool   `json:"enabled"`
+	Note        string `json:"note,omitempty"`
+}
+
+// ToolsResourceHandler handles the tools resource request.
+func (s *ServerContainer) ToolsResourceHandler(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+	entries := make([]toolsResourceEntry, 0, len(s.tools))
+	for _, tool := range s.tools {
+		entry := toolsResourceEntry{
+			Name:        tool.Name,
+			Description: tool.Description,
+			Enabled:     true,
+		}
+		// Dangerous tools are registered regardless of their flags, but
+		// their handlers reject calls until they are enabled.
+		switch {
+		case slices.Contains(PrometheusTsdbAdminTools, tool.Name) && !s.tsdbAdminToolsEnabled:
+			entry.Enabled = false
+			entry.Note = errTSDBAdminToolsNotEnabled.Error()
+		case tool.Name == createSilenceToolDef.Name && !s.silenceToolsEnabled:
+			entry.Enabled = false
+			entry.Note = errAlertmanagerSilencesNotEnabled.Error()
+		}
+		entries = append(entries, entry)
+	}
+
+	result, err := s.FormatOutput(entries)
+	if err != nil {
+		return nil, fmt.Errorf("failed to format tools: %w", err)
+	}
+
+	return newTextResourceResult(req.Params.URI, result), nil
+}
+
+// newTextResourceResult returns a resource result with the formatted output
+// of an API call as its plain text content.
+func newTextResourceResult(uri, text string) *mcp.ReadResourceResult {
+	return &mcp.ReadResourceResult{
+		Contents: []*mcp.ResourceContents{
+			{
+				URI:      uri,
+				MIMEType: "text/plain",
+				Text:     text,
+			},
+		},
+	}
+}
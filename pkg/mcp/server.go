@@ -15,19 +15,33 @@ package mcp
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
 	"embed"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"io/fs"
 	"log/slog"
+	"maps"
+	"math"
 	"net/http"
+	"net/url"
+	"os"
+	"reflect"
+	"regexp"
+	"slices"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/alpkeskin/gotoon"
 	"github.com/blevesearch/bleve/v2"
+	"github.com/blevesearch/bleve/v2/index/scorch"
+	index "github.com/blevesearch/bleve_index_api"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	promv1 "github.com/prometheus/client_golang/api/prometheus/v1"
 	"github.com/prometheus/client_golang/prometheus"
@@ -35,6 +49,7 @@ import (
 	"github.com/prometheus/common/promslog"
 	promversion "github.com/prometheus/common/version"
 	"github.com/tmc/langchaingo/textsplitter"
+	"gopkg.in/yaml.v3"
 
 	"github.com/prometheus/prometheus-mcp/internal/metrics"
 	mcpProm "github.com/prometheus/prometheus-mcp/pkg/prometheus"
@@ -58,6 +73,14 @@ var (
 		},
 	)
 
+	metricToolCalls = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: prometheus.BuildFQName(metrics.MetricNamespace, "tool", "calls_total"),
+			Help: "Total number of calls per tool.",
+		},
+		[]string{"tool_name"},
+	)
+
 	metricToolCallDuration = prometheus.NewHistogramVec(
 		prometheus.HistogramOpts{
 			Name:                        prometheus.BuildFQName(metrics.MetricNamespace, "tool", "call_duration_seconds"),
@@ -76,6 +99,16 @@ var (
 		[]string{"tool_name"},
 	)
 
+	metricToolResponseBytes = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:                        prometheus.BuildFQName(metrics.MetricNamespace, "tool", "response_bytes"),
+			Help:                        "Size of formatted tool responses returned to the client, per tool, in bytes.",
+			Buckets:                     prometheus.ExponentialBuckets(256, 4, 8),
+			NativeHistogramBucketFactor: 1.1,
+		},
+		[]string{"tool_name"},
+	)
+
 	metricResourceCallDuration = prometheus.NewHistogramVec(
 		prometheus.HistogramOpts{
 			Name:                        prometheus.BuildFQName(metrics.MetricNamespace, "resource", "call_duration_seconds"),
@@ -98,27 +131,169 @@ var (
 func init() {
 	metrics.Registry.MustRegister(
 		metricServerReady,
+		metricToolCalls,
 		metricToolCallDuration,
 		metricToolCallsFailed,
+		metricToolResponseBytes,
 		metricResourceCallDuration,
 		metricResourceCallsFailed,
 	)
 }
 
+// Output formats supported for tool responses.
+const (
+	OutputFormatJSON = "json"
+	OutputFormatTOON = "toon"
+	OutputFormatYAML = "yaml"
+)
+
+// OutputFormats is the list of supported output formats for tool responses.
+var OutputFormats = []string{
+	OutputFormatJSON,
+	OutputFormatTOON,
+	OutputFormatYAML,
+}
+
+// Truncation modes supported for query responses.
+const (
+	TruncationModeLines  = "lines"
+	TruncationModeBytes  = "bytes"
+	TruncationModeTokens = "tokens"
+)
+
+// TruncationModes is the list of supported truncation modes.
+var TruncationModes = []string{
+	TruncationModeLines,
+	TruncationModeBytes,
+	TruncationModeTokens,
+}
+
 // ServerConfig holds configuration for creating a new MCP server.
 type ServerConfig struct {
-	Logger                *slog.Logger
-	PrometheusURL         string
-	PrometheusBackend     string
-	PrometheusTimeout     time.Duration
-	TruncationLimit       int
-	RoundTripper          http.RoundTripper
-	TSDBAdminToolsEnabled bool
-	EnabledTools          []string
-	DocsFS                fs.FS
-	ToonOutputEnabled     bool
-	ClientLoggingEnabled  bool
-	KeepAlive             time.Duration
+	Logger                  *slog.Logger
+	PrometheusURL           string
+	PrometheusTargets       map[string]string
+	PrometheusBackend       string
+	PrometheusTimeout       time.Duration
+	PrometheusMaxTimeout    time.Duration
+	PrometheusLookbackDelta time.Duration
+	PrometheusRetries       int
+	PrometheusRetryBackoff  time.Duration
+	PrometheusConfigPath    string
+	TruncationLimit         int
+	TruncationMode          string
+	DisplayTimezone         string
+	RoundTripper            http.RoundTripper
+	UserAgent               string
+	HTTPMaxResponseBytes    int64
+	TSDBAdminToolsEnabled   bool
+	RequireConfirmation     bool
+	ConfirmationToken       string
+	AllowEmptyMatchers      bool
+	AlertmanagerURL         string
+	RemoteReadURL           string
+	SilenceToolsEnabled     bool
+	EnabledTools            []string
+	DisabledTools           []string
+	DocsFS                  fs.FS
+	DocsIndexTimeout        time.Duration
+	DocsReadRetries         int
+	DocsFSEmbedded          bool
+	OutputFormat            string
+	StripHelpText           bool
+	ClientLoggingEnabled    bool
+	LogToolCalls            bool
+	AuditFile               string
+	RateLimitRPS            float64
+	RateLimitBurst          int
+	KeepAlive               time.Duration
+	ProgressInterval        time.Duration
+	Transport               string
+	InstructionsFile        string
+	ServerInstructions      string
+	CacheTTL                time.Duration
+	MimirTenant             string
+	MimirTenantsPath        string
+	ForwardHeaders          []string
+	MaxConcurrentRequests   int
+}
+
+// prometheusTargetNameRegex matches valid names for named Prometheus targets.
+var prometheusTargetNameRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_-]*$`)
+
+// ParsePrometheusURLs parses `--prometheus.url` flag values of the form `url`
+// or `name=url`. It returns the default URL and the named targets. The
+// unnamed URL is the default, at most one may be given. If all URLs are
+// named, the first one is the default.
+func ParsePrometheusURLs(specs []string) (string, map[string]string, error) {
+	var (
+		unnamedURL    string
+		firstNamedURL string
+		targets       = make(map[string]string)
+	)
+
+	for _, spec := range specs {
+		name, targetURL, ok := strings.Cut(spec, "=")
+		if !ok || !prometheusTargetNameRegex.MatchString(name) {
+			if unnamedURL != "" {
+				return "", nil, fmt.Errorf("only one unnamed Prometheus URL may be given, got %q and %q", unnamedURL, spec)
+			}
+			unnamedURL = spec
+			continue
+		}
+
+		if targetURL == "" {
+			return "", nil, fmt.Errorf("empty URL for Prometheus target %q", name)
+		}
+		if _, exists := targets[name]; exists {
+			return "", nil, fmt.Errorf("duplicate Prometheus target name %q", name)
+		}
+		targets[name] = targetURL
+		if firstNamedURL == "" {
+			firstNamedURL = targetURL
+		}
+	}
+
+	switch {
+	case unnamedURL != "":
+		return unnamedURL, targets, nil
+	case firstNamedURL != "":
+		return firstNamedURL, targets, nil
+	default:
+		return "", nil, errors.New("at least one Prometheus URL is required")
+	}
+}
+
+// operatorInstructionsHeading introduces the rules given with the
+// `--mcp.server-instructions` flag, which are appended to the built-in
+// instructions. They are separate from the `prometheus://instructions`
+// resource loaded from `--mcp.instructions-file`, and outrank it.
+const operatorInstructionsHeading = "Operator Instructions:\n" +
+	"    These rules were set by the operator of this server for this deployment. They take precedence over the general guidelines above and over the `prometheus://instructions` resource.\n\n"
+
+// buildServerInstructions returns the instructions sent to clients during
+// initialization. The spec is either inline text or, prefixed with `@`, the
+// path of a file to read. Its content is appended to the built-in
+// instructions, so the general guidance still applies.
+func buildServerInstructions(builtin, spec string) (string, error) {
+	if spec == "" {
+		return builtin, nil
+	}
+
+	operator := spec
+	if path, ok := strings.CutPrefix(spec, "@"); ok {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("failed to read server instructions file: %w", err)
+		}
+		operator = string(content)
+	}
+	operator = strings.TrimSpace(operator)
+	if operator == "" {
+		return "", fmt.Errorf("server instructions from %q are empty", spec)
+	}
+
+	return strings.TrimRight(builtin, "\n") + "\n\n" + operatorInstructionsHeading + operator + "\n", nil
 }
 
 // NewServer creates a new MCP server using the official Go SDK.
@@ -134,9 +309,12 @@ func NewServer(ctx context.Context, cfg ServerConfig) (*mcp.Server, *ServerConta
 		logger.Error("Failed to read instructions from embedded assets", "err", err)
 		coreInstructions = []byte("Prometheus MCP Server")
 	}
-	instrx := string(coreInstructions)
+	instrx, err := buildServerInstructions(string(coreInstructions), cfg.ServerInstructions)
+	if err != nil {
+		return nil, nil, err
+	}
 
-	container, err := newServerContainer(cfg)
+	container, err := newServerContainer(ctx, cfg)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -157,22 +335,41 @@ func NewServer(ctx context.Context, cfg ServerConfig) (*mcp.Server, *ServerConta
 	// Select the appropriate toolset based on configuration.
 	toolsetMap := getToolset(toolsetConfig{
 		enabledTools:      cfg.EnabledTools,
+		disabledTools:     cfg.DisabledTools,
 		prometheusBackend: cfg.PrometheusBackend,
 		logger:            logger,
 	})
 	toolset := toolsetToToolRegistrationSlice(toolsetMap)
+	container.enabledTools, container.disabledTools = toolsetNames(toolsetMap)
+	container.tools = toolsetTools(toolsetMap)
 
 	// Register tools.
 	registerTools(server, container, toolset)
 
 	// Register resources.
-	registerResources(server, container)
+	registerResources(server, container, cfg.PrometheusBackend)
+
+	// Register prompts.
+	registerPrompts(server, container)
+
+	// Middleware added first runs innermost, so rate limited calls are still
+	// counted, logged, and audited by the middleware below.
+	if container.rateLimiter != nil {
+		server.AddReceivingMiddleware(rateLimitMiddleware(container.rateLimiter, logger))
+	}
 
 	// Add telemetry middleware for metrics and logging.
 	server.AddReceivingMiddleware(telemetryMiddleware(logger))
+	if cfg.LogToolCalls {
+		server.AddReceivingMiddleware(toolCallLoggingMiddleware(logger))
+	}
+	if container.auditLog != nil {
+		server.AddReceivingMiddleware(auditMiddleware(container.auditLog, logger))
+	}
 
 	logger.Info("MCP server created",
 		"prometheus_url", cfg.PrometheusURL,
+		"prometheus_targets", slices.Sorted(maps.Keys(cfg.PrometheusTargets)),
 		"tool_count", len(toolset),
 	)
 
@@ -202,6 +399,31 @@ func NewStreamableHTTPHandler(server *mcp.Server, logger *slog.Logger, sessionTi
 	return authContextMiddleware(handler)
 }
 
+// NewSSEHandler creates an HTTP handler serving the MCP server over the legacy
+// SSE transport, for clients that don't support streamable HTTP. Like the
+// streamable HTTP handler, it forwards Authorization headers.
+//
+// An SSE session lasts as long as the client's GET request, which would keep
+// a graceful web server shutdown waiting. Sessions are therefore closed once
+// ctx is canceled.
+func NewSSEHandler(ctx context.Context, server *mcp.Server) http.Handler {
+	handler := mcp.NewSSEHandler(
+		func(r *http.Request) *mcp.Server {
+			return server
+		},
+		nil,
+	)
+
+	return authContextMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reqCtx, cancel := context.WithCancel(r.Context())
+		defer cancel()
+		stop := context.AfterFunc(ctx, cancel)
+		defer stop()
+
+		handler.ServeHTTP(w, r.WithContext(reqCtx))
+	}))
+}
+
 // authHeaderKey is the context key for storing the Authorization header.
 type authHeaderKey struct{}
 
@@ -218,23 +440,258 @@ func getAuthFromContext(ctx context.Context) string {
 	return ""
 }
 
+// targetKey is the context key for storing the requested Prometheus target.
+type targetKey struct{}
+
+// addTargetToContext adds a Prometheus target name to the context.
+func addTargetToContext(ctx context.Context, target string) context.Context {
+	return context.WithValue(ctx, targetKey{}, target)
+}
+
+// getTargetFromContext retrieves the Prometheus target name from the context.
+func getTargetFromContext(ctx context.Context) string {
+	if target, ok := ctx.Value(targetKey{}).(string); ok {
+		return target
+	}
+	return ""
+}
+
+// apiTimeoutKey is the context key for storing a per-call override of the
+// API timeout.
+type apiTimeoutKey struct{}
+
+// addAPITimeoutToContext adds an API timeout override to the context.
+func addAPITimeoutToContext(ctx context.Context, timeout time.Duration) context.Context {
+	return context.WithValue(ctx, apiTimeoutKey{}, timeout)
+}
+
+// getAPITimeoutFromContext retrieves the API timeout override from the
+// context, or 0 if there is none.
+func getAPITimeoutFromContext(ctx context.Context) time.Duration {
+	if timeout, ok := ctx.Value(apiTimeoutKey{}).(time.Duration); ok {
+		return timeout
+	}
+	return 0
+}
+
+// toolNameKey is the context key for storing the name of the tool being
+// called.
+type toolNameKey struct{}
+
+// addToolNameToContext adds the name of the tool being called to the context.
+func addToolNameToContext(ctx context.Context, toolName string) context.Context {
+	return context.WithValue(ctx, toolNameKey{}, toolName)
+}
+
+// getToolNameFromContext retrieves the name of the tool being called from the
+// context.
+func getToolNameFromContext(ctx context.Context) string {
+	if toolName, ok := ctx.Value(toolNameKey{}).(string); ok {
+		return toolName
+	}
+	return ""
+}
+
+// httpConfigKey is the context key for storing a per-request HTTP client
+// config.
+type httpConfigKey struct{}
+
+// addHTTPConfigToContext adds an HTTP client config to the context. API
+// clients for requests with this context are built from it instead of the
+// server's default round tripper.
+func addHTTPConfigToContext(ctx context.Context, cfg *config.HTTPClientConfig) context.Context {
+	return context.WithValue(ctx, httpConfigKey{}, cfg)
+}
+
+// getHTTPConfigFromContext retrieves the HTTP client config from the context.
+func getHTTPConfigFromContext(ctx context.Context) *config.HTTPClientConfig {
+	if cfg, ok := ctx.Value(httpConfigKey{}).(*config.HTTPClientConfig); ok {
+		return cfg
+	}
+	return nil
+}
+
+// tenantKey is the context key for storing the Mimir tenant ID.
+type tenantKey struct{}
+
+// addTenantToContext adds a Mimir tenant ID to the context.
+func addTenantToContext(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// getTenantFromContext retrieves the Mimir tenant ID from the context.
+func getTenantFromContext(ctx context.Context) string {
+	if tenant, ok := ctx.Value(tenantKey{}).(string); ok {
+		return tenant
+	}
+	return ""
+}
+
 // authContextMiddleware creates an HTTP middleware that extracts the Authorization
-// header from requests and adds it to the request context.
+// and X-Scope-OrgID headers from requests and adds them to the request context.
+// All request headers are added as well, to forward the allow-listed ones.
 func authContextMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 		if auth := r.Header.Get("Authorization"); auth != "" {
 			ctx = addAuthToContext(ctx, auth)
 		}
+		if tenant := r.Header.Get(mimirTenantHeader); tenant != "" {
+			ctx = addTenantToContext(ctx, tenant)
+		}
+		ctx = addRequestHeadersToContext(ctx, r.Header.Clone())
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
 
+// ResolveAuthToken returns the token MCP clients must present to use the
+// `/mcp` endpoint, given with either the `--mcp.auth-token` or the
+// `--mcp.auth-token-file` flag. Surrounding whitespace, such as a trailing
+// newline in the file, is trimmed. An empty token disables authentication.
+func ResolveAuthToken(token, tokenFile string) (string, error) {
+	if token != "" && tokenFile != "" {
+		return "", errors.New("only one of --mcp.auth-token and --mcp.auth-token-file may be set")
+	}
+	if tokenFile != "" {
+		data, err := os.ReadFile(tokenFile)
+		if err != nil {
+			return "", fmt.Errorf("failed to read auth token file: %w", err)
+		}
+		token = strings.TrimSpace(string(data))
+		if token == "" {
+			return "", fmt.Errorf("auth token file %q is empty", tokenFile)
+		}
+	}
+	return token, nil
+}
+
+// NewBearerAuthHandler wraps next with a middleware rejecting requests that
+// don't carry `Authorization: Bearer <token>` with 401 Unauthorized. The
+// token protects the MCP server itself, so the Authorization header is
+// removed before calling next and not forwarded to Prometheus. Without a
+// token, next is returned unchanged.
+func NewBearerAuthHandler(next http.Handler, token string) http.Handler {
+	if token == "" {
+		return next
+	}
+	expected := []byte("Bearer " + token)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), expected) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="prometheus-mcp-server"`)
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		r = r.Clone(r.Context())
+		r.Header.Del("Authorization")
+		next.ServeHTTP(w, r)
+	})
+}
+
+// corsAllowedMethods are the HTTP methods used by the streamable HTTP
+// transport.
+const corsAllowedMethods = "GET, POST, DELETE, OPTIONS"
+
+// corsAllowedHeaders are the request headers MCP clients may send.
+var corsAllowedHeaders = strings.Join([]string{
+	"Authorization",
+	"Content-Type",
+	"Accept",
+	"Last-Event-ID",
+	"Mcp-Session-Id",
+	"Mcp-Protocol-Version",
+	mimirTenantHeader,
+}, ", ")
+
+// corsExposedHeaders are the response headers browser clients need to read to
+// keep a session.
+const corsExposedHeaders = "Mcp-Session-Id, Mcp-Protocol-Version"
+
+// corsMaxAge is how long browsers may cache preflight responses, in seconds.
+const corsMaxAge = "600"
+
+// ParseCORSOrigins parses the `--web.cors-origins` flag value, a
+// comma-separated list of origins such as `https://example.com` or `*` to
+// allow any origin. An empty value disables CORS.
+func ParseCORSOrigins(spec string) ([]string, error) {
+	var origins []string
+	for origin := range strings.SplitSeq(spec, ",") {
+		origin = strings.TrimSpace(origin)
+		if origin == "" {
+			continue
+		}
+		if origin == "*" {
+			origins = append(origins, origin)
+			continue
+		}
+
+		u, err := url.Parse(origin)
+		if err != nil || u.Scheme == "" || u.Host == "" || (u.Path != "" && u.Path != "/") || u.RawQuery != "" || u.Fragment != "" {
+			return nil, fmt.Errorf("invalid CORS origin %q, expected `scheme://host[:port]` or `*`", origin)
+		}
+		origins = append(origins, u.Scheme+"://"+u.Host)
+	}
+	return origins, nil
+}
+
+// NewCORSHandler wraps next with a middleware setting CORS headers for
+// requests from the allowed origins and answering preflight requests. Without
+// allowed origins, next is returned unchanged.
+func NewCORSHandler(next http.Handler, allowedOrigins []string) http.Handler {
+	if len(allowedOrigins) == 0 {
+		return next
+	}
+	allowAll := slices.Contains(allowedOrigins, "*")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Origin")
+		origin := r.Header.Get("Origin")
+		if origin == "" || (!allowAll && !slices.Contains(allowedOrigins, origin)) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if allowAll {
+			w.Header().Set("Access-Control-Allow-Origin", "*")
+		} else {
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+		}
+		w.Header().Set("Access-Control-Expose-Headers", corsExposedHeaders)
+
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			w.Header().Add("Vary", "Access-Control-Request-Method")
+			w.Header().Add("Vary", "Access-Control-Request-Headers")
+			w.Header().Set("Access-Control-Allow-Methods", corsAllowedMethods)
+			w.Header().Set("Access-Control-Allow-Headers", corsAllowedHeaders)
+			w.Header().Set("Access-Control-Max-Age", corsMaxAge)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
 // docsState holds the documentation filesystem and search index.
 // It is designed to be swapped atomically for live documentation updates.
 type docsState struct {
 	fs          fs.FS
 	searchIndex bleve.Index
+	// indexErr records why the search index is unavailable, if it is. The
+	// docs filesystem may still be usable for listing and reading.
+	indexErr error
+	// readRetries is the number of times a failed read of a doc file is
+	// retried. It is only set for docs served from --docs.dir, as reads from
+	// the embedded or downloaded docs held in memory can't fail transiently.
+	readRetries int
+}
+
+// prometheusTarget is a named Prometheus backend that can be selected per tool
+// call.
+type prometheusTarget struct {
+	url    string
+	client promv1.API
 }
 
 // ServerContainer holds all dependencies needed by tool and resource handlers.
@@ -242,76 +699,588 @@ type docsState struct {
 // mcp-go library with explicit dependency injection.
 type ServerContainer struct {
 	// Core dependencies.
-	logger            *slog.Logger
-	defaultAPIClient  promv1.API
-	prometheusURL     string
-	defaultRT         http.RoundTripper
-	defaultHTTPClient http.Client
+	logger               *slog.Logger
+	defaultAPIClient     promv1.API
+	prometheusURL        string
+	prometheusTargets    map[string]prometheusTarget
+	prometheusConfigPath string
+	defaultRT            http.RoundTripper
+	defaultHTTPClient    http.Client
+	userAgent            string
+	alertmanagerURL      string
+	alertmanagerRT       http.RoundTripper
+	remoteReadURL        string
+
+	// Round trippers built from per-request HTTP client configs, keyed by
+	// config hash.
+	httpConfigRTsMu sync.Mutex
+	httpConfigRTs   map[string]http.RoundTripper
 
 	// Configuration values the MCP server needs to use/cares about.
 	truncationLimit       int
-	toonOutputEnabled     bool
+	truncationMode        string
+	outputFormat          string
+	stripHelpText         bool
 	tsdbAdminToolsEnabled bool
+	allowEmptyMatchers    bool
+	silenceToolsEnabled   bool
 	apiTimeout            time.Duration
+	apiMaxTimeout         time.Duration
+	lookbackDelta         time.Duration
+	apiRetries            int
+	apiRetryBackoff       time.Duration
+	maxResponseBytes      int64
+	displayLocation       *time.Location
 	clientLoggingEnabled  bool
+	logToolCalls          bool
+	progressInterval      time.Duration
+	auditFile             string
+	auditLog              *auditLog
+	rateLimiter           *toolRateLimiter
+	apiCallLimiter        *apiCallLimiter
+	docsIndexTimeout      time.Duration
+	operatorInstructions  string
+	responseCache         *responseCache
+	mimirTenant           string
+	mimirTenantsPath      string
+	forwardHeaders        []string
+
+	// Confirmation required by destructive tools, see checkConfirmation.
+	requireConfirmation bool
+	confirmationToken   string
+	revealConfirmation  bool
+
+	// Server settings that are only reported by the mcp_config tool.
+	prometheusBackend string
+	transport         string
+	keepAlive         time.Duration
+	instructionsFile  string
+	enabledTools      []string
+	disabledTools     []string
+
+	// Definitions of the registered tools, sorted by name, for the tools
+	// resource.
+	tools []*mcp.Tool
 
 	// Docs state management.
 	docsMu sync.RWMutex
 	docs   *docsState
+
+	// Last-seen scrape targets for the target churn tool, keyed by backend URL.
+	targetChurnMu        sync.Mutex
+	targetChurnBaselines map[string]map[string]targetChurnEntry
+}
+
+// generateConfirmationToken returns a random token for confirming destructive
+// operations.
+func generateConfirmationToken() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate confirmation token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
 }
 
 // newServerContainer creates a new ServerContainer with the given configuration.
-func newServerContainer(cfg ServerConfig) (*ServerContainer, error) {
-	client, err := mcpProm.NewAPIClient(cfg.PrometheusURL, cfg.RoundTripper)
+func newServerContainer(ctx context.Context, cfg ServerConfig) (*ServerContainer, error) {
+	userAgent := cfg.UserAgent
+	if userAgent == "" {
+		userAgent = mcpProm.UserAgent()
+	}
+	// Only requests to Prometheus count against the concurrency limit, so
+	// Alertmanager gets its own round tripper without the limiter.
+	apiCallLimiter := newAPICallLimiter(cfg.MaxConcurrentRequests)
+	rt := &userAgentRoundTripper{
+		userAgent: userAgent,
+		next:      &concurrencyLimitRoundTripper{limiter: apiCallLimiter, next: cfg.RoundTripper},
+	}
+
+	client, err := mcpProm.NewAPIClient(cfg.PrometheusURL, rt)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create default API client: %w", err)
 	}
 
+	targets := make(map[string]prometheusTarget, len(cfg.PrometheusTargets))
+	for name, targetURL := range cfg.PrometheusTargets {
+		targetClient, err := mcpProm.NewAPIClient(targetURL, rt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create API client for target %q: %w", name, err)
+		}
+		targets[name] = prometheusTarget{url: targetURL, client: targetClient}
+	}
+
+	outputFormat := cfg.OutputFormat
+	if outputFormat == "" {
+		outputFormat = OutputFormatJSON
+	}
+	if !slices.Contains(OutputFormats, outputFormat) {
+		return nil, fmt.Errorf("unsupported output format %q, must be one of: %s", outputFormat, strings.Join(OutputFormats, ", "))
+	}
+
+	truncationMode := cfg.TruncationMode
+	if truncationMode == "" {
+		truncationMode = TruncationModeLines
+	}
+	if !slices.Contains(TruncationModes, truncationMode) {
+		return nil, fmt.Errorf("unsupported truncation mode %q, must be one of: %s", truncationMode, strings.Join(TruncationModes, ", "))
+	}
+
+	var displayLocation *time.Location
+	if cfg.DisplayTimezone != "" {
+		displayLocation, err = time.LoadLocation(cfg.DisplayTimezone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid display time zone %q: %w", cfg.DisplayTimezone, err)
+		}
+	}
+
+	operatorInstructions, err := loadOperatorInstructions(cfg.InstructionsFile)
+	if err != nil {
+		return nil, err
+	}
+
+	forwardHeaders, err := normalizeForwardHeaders(cfg.ForwardHeaders)
+	if err != nil {
+		return nil, err
+	}
+
+	// Without a configured token, generate one that is revealed to the LLM
+	// by the first, unconfirmed call. This doesn't keep the LLM from running
+	// destructive tools, but forces it to do so deliberately in two steps.
+	confirmationToken := cfg.ConfirmationToken
+	revealConfirmation := false
+	if cfg.RequireConfirmation && confirmationToken == "" {
+		confirmationToken, err = generateConfirmationToken()
+		if err != nil {
+			return nil, err
+		}
+		revealConfirmation = true
+	}
+
+	var audit *auditLog
+	if cfg.AuditFile != "" {
+		audit, err = openAuditLog(cfg.AuditFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	container := &ServerContainer{
 		logger:                cfg.Logger,
 		defaultAPIClient:      client,
 		prometheusURL:         cfg.PrometheusURL,
-		defaultRT:             cfg.RoundTripper,
-		defaultHTTPClient:     http.Client{Transport: cfg.RoundTripper},
+		prometheusTargets:     targets,
+		prometheusConfigPath:  cfg.PrometheusConfigPath,
+		defaultRT:             rt,
+		defaultHTTPClient:     http.Client{Transport: rt},
+		userAgent:             userAgent,
 		truncationLimit:       cfg.TruncationLimit,
-		toonOutputEnabled:     cfg.ToonOutputEnabled,
+		truncationMode:        truncationMode,
+		outputFormat:          outputFormat,
+		stripHelpText:         cfg.StripHelpText,
 		tsdbAdminToolsEnabled: cfg.TSDBAdminToolsEnabled,
+		allowEmptyMatchers:    cfg.AllowEmptyMatchers,
+		alertmanagerURL:       cfg.AlertmanagerURL,
+		alertmanagerRT:        &userAgentRoundTripper{userAgent: userAgent, next: cfg.RoundTripper},
+		remoteReadURL:         cfg.RemoteReadURL,
+		silenceToolsEnabled:   cfg.SilenceToolsEnabled,
 		apiTimeout:            cfg.PrometheusTimeout,
+		apiMaxTimeout:         cfg.PrometheusMaxTimeout,
+		lookbackDelta:         normalizeLookbackDelta(cfg.PrometheusLookbackDelta),
+		apiRetries:            cfg.PrometheusRetries,
+		apiRetryBackoff:       cfg.PrometheusRetryBackoff,
+		maxResponseBytes:      cfg.HTTPMaxResponseBytes,
+		displayLocation:       displayLocation,
+		requireConfirmation:   cfg.RequireConfirmation,
+		confirmationToken:     confirmationToken,
+		revealConfirmation:    revealConfirmation,
 		clientLoggingEnabled:  cfg.ClientLoggingEnabled,
+		logToolCalls:          cfg.LogToolCalls,
+		auditFile:             cfg.AuditFile,
+		auditLog:              audit,
+		rateLimiter:           newToolRateLimiter(cfg.RateLimitRPS, cfg.RateLimitBurst),
+		apiCallLimiter:        apiCallLimiter,
+		docsIndexTimeout:      cfg.DocsIndexTimeout,
+		operatorInstructions:  operatorInstructions,
+		responseCache:         newResponseCache(cfg.CacheTTL),
+		mimirTenant:           cfg.MimirTenant,
+		mimirTenantsPath:      cfg.MimirTenantsPath,
+		forwardHeaders:        forwardHeaders,
+		prometheusBackend:     cfg.PrometheusBackend,
+		transport:             cfg.Transport,
+		keepAlive:             cfg.KeepAlive,
+		progressInterval:      cfg.ProgressInterval,
+		instructionsFile:      cfg.InstructionsFile,
+	}
+
+	if cfg.MimirTenant != "" && !strings.EqualFold(cfg.PrometheusBackend, "mimir") {
+		cfg.Logger.Warn("Mimir tenant is set but the Prometheus backend is not mimir, the tenant will be ignored", "backend", cfg.PrometheusBackend)
 	}
 
 	// Initialize docs search if FS is provided.
 	if cfg.DocsFS != nil {
-		state, err := buildDocsState(cfg.Logger, cfg.DocsFS)
+		indexCtx, cancel := container.docsIndexContext(ctx)
+		readRetries := 0
+		if !cfg.DocsFSEmbedded {
+			readRetries = cfg.DocsReadRetries
+		}
+		state, err := buildDocsState(indexCtx, cfg.Logger, cfg.DocsFS, readRetries)
+		cancel()
 		if err != nil {
-			cfg.Logger.Error("Failed to initialize docs search", "err", err)
+			if errors.Is(err, errDocsIndexTimeout) {
+				cfg.Logger.Error("Docs search index build timed out, docs search will be unavailable", "timeout", cfg.DocsIndexTimeout)
+			} else {
+				cfg.Logger.Error("Failed to initialize docs search", "err", err)
+			}
 			// Non-fatal - continue without docs search.
 		}
-
 		container.swapDocsState(state)
 	}
 
 	return container, nil
 }
 
+// requestHeadersKey is the context key for storing the headers of the MCP
+// client's HTTP request.
+type requestHeadersKey struct{}
+
+// addRequestHeadersToContext adds the headers of the MCP client's HTTP
+// request to the context. Only headers allow-listed with
+// --prometheus.forward-headers are ever sent to the backend.
+func addRequestHeadersToContext(ctx context.Context, headers http.Header) context.Context {
+	return context.WithValue(ctx, requestHeadersKey{}, headers)
+}
+
+// getRequestHeadersFromContext retrieves the headers of the MCP client's HTTP
+// request from the context.
+func getRequestHeadersFromContext(ctx context.Context) http.Header {
+	if headers, ok := ctx.Value(requestHeadersKey{}).(http.Header); ok {
+		return headers
+	}
+	return nil
+}
+
 // GetAPIClient returns a Prometheus API client, optionally with auth from context.
-// If an Authorization header is present in the context, a new client with those
-// credentials is created. Otherwise, the default client is returned.
+// If a target is present in the context, the client for that named backend is
+// used instead of the default one. If an HTTP client config is present in the
+// context, the client is built from it instead of the default round tripper.
+// If an Authorization header is present in the context, a new client with
+// those credentials is created. Headers of the MCP client's request that are
+// allow-listed with --prometheus.forward-headers are set on every request.
+// For the mimir backend, the client and round tripper also set the tenant's
+// X-Scope-OrgID header on every request.
 func (s *ServerContainer) GetAPIClient(ctx context.Context) (promv1.API, http.RoundTripper) {
+	client, prometheusURL := s.defaultAPIClient, s.prometheusURL
+	if target, ok := s.prometheusTargets[getTargetFromContext(ctx)]; ok {
+		client, prometheusURL = target.client, target.url
+	}
+
+	rt := s.defaultRT
+	if httpCfg := getHTTPConfigFromContext(ctx); httpCfg != nil {
+		cfgRT, err := s.roundTripperForHTTPConfig(httpCfg)
+		if err != nil {
+			s.logger.Warn("Failed to create round tripper from HTTP config in context, falling back to default client", "err", err)
+		} else {
+			cfgClient, err := mcpProm.NewAPIClient(prometheusURL, cfgRT)
+			if err != nil {
+				s.logger.Warn("Failed to create client from HTTP config in context, falling back to default client", "err", err)
+			} else {
+				client, rt = cfgClient, cfgRT
+			}
+		}
+	}
+
 	auth := getAuthFromContext(ctx)
 	if auth != "" {
-		client, rt := s.createClientWithAuth(auth)
-		if client != nil {
-			return client, rt
+		authClient, authRT := s.createClientWithAuth(prometheusURL, auth, rt)
+		if authClient != nil {
+			client, rt = authClient, authRT
+		} else {
+			s.logger.Warn("Failed to create client with provided auth, falling back to default client")
+		}
+	}
+
+	if headers := s.forwardedHeaders(ctx); len(headers) > 0 {
+		headersRT := &forwardHeadersRoundTripper{headers: headers, next: rt}
+		headersClient, err := mcpProm.NewAPIClient(prometheusURL, headersRT)
+		if err != nil {
+			s.logger.Warn("Failed to create client with forwarded headers, falling back to client without them", "err", err)
+		} else {
+			client, rt = headersClient, headersRT
+		}
+	}
+
+	if tenant := s.tenant(ctx); tenant != "" {
+		tenantRT := &tenantRoundTripper{tenant: tenant, next: rt}
+		tenantClient, err := mcpProm.NewAPIClient(prometheusURL, tenantRT)
+		if err != nil {
+			s.logger.Warn("Failed to create client with Mimir tenant, falling back to client without tenant", "err", err)
+		} else {
+			client, rt = tenantClient, tenantRT
+		}
+	}
+
+	return client, rt
+}
+
+// mimirTenantHeader is the header Mimir uses to select the tenant of a
+// request.
+const mimirTenantHeader = "X-Scope-OrgID"
+
+// tenant returns the Mimir tenant for requests with the given context. A
+// tenant from the MCP client's request takes precedence over the one
+// configured with --mimir.tenant. Other backends never have a tenant.
+func (s *ServerContainer) tenant(ctx context.Context) string {
+	if !strings.EqualFold(s.prometheusBackend, "mimir") {
+		return ""
+	}
+	if tenant := getTenantFromContext(ctx); tenant != "" {
+		return tenant
+	}
+	return s.mimirTenant
+}
+
+// tenantRoundTripper sets the Mimir tenant header on every request before
+// passing it to the next round tripper.
+type tenantRoundTripper struct {
+	tenant string
+	next   http.RoundTripper
+}
+
+func (rt *tenantRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	next := rt.next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	req = req.Clone(req.Context())
+	req.Header.Set(mimirTenantHeader, rt.tenant)
+	return next.RoundTrip(req)
+}
+
+// headerNameRegex matches valid HTTP header names, see RFC 9110 section 5.1.
+var headerNameRegex = regexp.MustCompile("^[!#$%&'*+.^_`|~0-9A-Za-z-]+$")
+
+// normalizeForwardHeaders validates the header names given with
+// --prometheus.forward-headers and returns them in canonical form without
+// duplicates. The Authorization header is forwarded on its own and can't be
+// listed.
+func normalizeForwardHeaders(names []string) ([]string, error) {
+	var headers []string
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if !headerNameRegex.MatchString(name) {
+			return nil, fmt.Errorf("invalid header name %q to forward", name)
+		}
+		name = http.CanonicalHeaderKey(name)
+		if name == "Authorization" {
+			return nil, errors.New("the Authorization header is always forwarded and can't be listed in --prometheus.forward-headers")
+		}
+		if !slices.Contains(headers, name) {
+			headers = append(headers, name)
+		}
+	}
+	return headers, nil
+}
+
+// forwardedHeaders returns the allow-listed headers of the MCP client's
+// request with the given context. All other headers are dropped.
+func (s *ServerContainer) forwardedHeaders(ctx context.Context) http.Header {
+	requestHeaders := getRequestHeadersFromContext(ctx)
+	if len(s.forwardHeaders) == 0 || len(requestHeaders) == 0 {
+		return nil
+	}
+
+	headers := make(http.Header)
+	for _, name := range s.forwardHeaders {
+		if values := requestHeaders.Values(name); len(values) > 0 {
+			headers[name] = slices.Clone(values)
+		}
+	}
+	return headers
+}
+
+// forwardHeadersRoundTripper sets the forwarded headers of the MCP client's
+// request on every request before passing it to the next round tripper.
+type forwardHeadersRoundTripper struct {
+	headers http.Header
+	next    http.RoundTripper
+}
+
+func (rt *forwardHeadersRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	next := rt.next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	req = req.Clone(req.Context())
+	for name, values := range rt.headers {
+		req.Header[name] = slices.Clone(values)
+	}
+	return next.RoundTrip(req)
+}
+
+// userAgentRoundTripper sets the User-Agent header on every request before
+// passing it to the next round tripper. For requests made by a tool, the tool
+// name is appended as an `mcp-tool/<name>` product token, so access logs of
+// the backend show which tool generated the traffic. It takes precedence
+// over the default User-Agent set by mcpProm.NewAPIClient.
+type userAgentRoundTripper struct {
+	userAgent string
+	next      http.RoundTripper
+}
+
+func (rt *userAgentRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	next := rt.next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	userAgent := rt.userAgent
+	if toolName := getToolNameFromContext(req.Context()); toolName != "" {
+		userAgent += " mcp-tool/" + toolName
+	}
+	req = req.Clone(req.Context())
+	req.Header.Set("User-Agent", userAgent)
+	return next.RoundTrip(req)
+}
+
+// roundTripperForHTTPConfig returns a round tripper built from the HTTP client
+// config. Round trippers are cached by a hash of the config, so requests with
+// the same config share connections instead of rebuilding a transport on
+// every call.
+func (s *ServerContainer) roundTripperForHTTPConfig(cfg *config.HTTPClientConfig) (http.RoundTripper, error) {
+	key := hashHTTPClientConfig(cfg)
+
+	s.httpConfigRTsMu.Lock()
+	defer s.httpConfigRTsMu.Unlock()
+	if rt, ok := s.httpConfigRTs[key]; ok {
+		return rt, nil
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid HTTP client config: %w", err)
+	}
+	baseRT, err := config.NewRoundTripperFromConfig(*cfg, "prometheus-mcp")
+	if err != nil {
+		return nil, err
+	}
+	rt := &userAgentRoundTripper{
+		userAgent: s.userAgent,
+		next:      &concurrencyLimitRoundTripper{limiter: s.apiCallLimiter, next: baseRT},
+	}
+
+	if s.httpConfigRTs == nil {
+		s.httpConfigRTs = make(map[string]http.RoundTripper)
+	}
+	s.httpConfigRTs[key] = rt
+	return rt, nil
+}
+
+// hashHTTPClientConfig returns a hash of every value in the HTTP client
+// config. The config's own marshalers redact secrets, which would make
+// configs that only differ by credentials collide, so the config is walked
+// with reflection instead.
+func hashHTTPClientConfig(cfg *config.HTTPClientConfig) string {
+	h := sha256.New()
+	hashValue(h, reflect.ValueOf(cfg))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func hashValue(w io.Writer, v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			fmt.Fprint(w, "nil;")
+			return
+		}
+		hashValue(w, v.Elem())
+	case reflect.Struct:
+		for i := range v.NumField() {
+			fmt.Fprintf(w, "%s:", v.Type().Field(i).Name)
+			hashValue(w, v.Field(i))
+		}
+	case reflect.Slice, reflect.Array:
+		fmt.Fprintf(w, "[%d]", v.Len())
+		for i := range v.Len() {
+			hashValue(w, v.Index(i))
+		}
+	case reflect.Map:
+		keys := v.MapKeys()
+		slices.SortFunc(keys, func(a, b reflect.Value) int {
+			return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+		})
+		fmt.Fprintf(w, "{%d}", len(keys))
+		for _, k := range keys {
+			hashValue(w, k)
+			hashValue(w, v.MapIndex(k))
+		}
+	case reflect.String:
+		fmt.Fprintf(w, "%q;", v.String())
+	case reflect.Bool:
+		fmt.Fprintf(w, "%t;", v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		fmt.Fprintf(w, "%d;", v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		fmt.Fprintf(w, "%d;", v.Uint())
+	case reflect.Float32, reflect.Float64:
+		fmt.Fprintf(w, "%g;", v.Float())
+	default:
+		// Funcs, channels, and the like can't be compared by value.
+		fmt.Fprintf(w, "%s;", v.Kind())
+	}
+}
+
+// getPrometheusURL returns the URL of the Prometheus backend selected by the
+// target in the context, or the default URL if there is none.
+func (s *ServerContainer) getPrometheusURL(ctx context.Context) string {
+	if target, ok := s.prometheusTargets[getTargetFromContext(ctx)]; ok {
+		return target.url
+	}
+	return s.prometheusURL
+}
+
+// withTarget validates the requested Prometheus target and adds it to the
+// context for GetAPIClient. An empty target selects the default backend.
+func (s *ServerContainer) withTarget(ctx context.Context, target string) (context.Context, error) {
+	if target == "" {
+		return ctx, nil
+	}
+	if _, ok := s.prometheusTargets[target]; !ok {
+		if len(s.prometheusTargets) == 0 {
+			return ctx, fmt.Errorf("unknown target %q, no named Prometheus targets are configured", target)
 		}
-		s.logger.Warn("Failed to create client with provided auth, falling back to default client")
+		return ctx, fmt.Errorf("unknown target %q, must be one of: %s", target, strings.Join(slices.Sorted(maps.Keys(s.prometheusTargets)), ", "))
 	}
+	return addTargetToContext(ctx, target), nil
+}
 
-	return s.defaultAPIClient, s.defaultRT
+// maxAPITimeout returns the upper bound of per-call API timeout overrides.
+// It is never lower than the configured API timeout.
+func (s *ServerContainer) maxAPITimeout() time.Duration {
+	return max(s.apiMaxTimeout, s.apiTimeout)
+}
+
+// withAPITimeout clamps a per-call API timeout override to the maximum and
+// adds it to the context for getAPITimeout. It returns the clamped timeout. A
+// zero timeout keeps the configured API timeout and is returned as is.
+func (s *ServerContainer) withAPITimeout(ctx context.Context, timeout time.Duration) (context.Context, time.Duration) {
+	if timeout <= 0 {
+		return ctx, timeout
+	}
+	timeout = min(timeout, s.maxAPITimeout())
+	return addAPITimeoutToContext(ctx, timeout), timeout
 }
 
-// createClientWithAuth creates a new API client with the given Authorization header.
-func (s *ServerContainer) createClientWithAuth(authorization string) (promv1.API, http.RoundTripper) {
+// getAPITimeout returns the timeout for API calls made with ctx: the per-call
+// override added by withAPITimeout, or the configured API timeout.
+func (s *ServerContainer) getAPITimeout(ctx context.Context) time.Duration {
+	if timeout := getAPITimeoutFromContext(ctx); timeout > 0 {
+		return timeout
+	}
+	return s.apiTimeout
+}
+
+// createClientWithAuth creates a new API client for the Prometheus at
+// prometheusURL with the given Authorization header, wrapping the base round
+// tripper.
+func (s *ServerContainer) createClientWithAuth(prometheusURL, authorization string, base http.RoundTripper) (promv1.API, http.RoundTripper) {
 	var authType, secret string
 	if strings.Contains(authorization, " ") {
 		parts := strings.SplitN(authorization, " ", 2)
@@ -323,8 +1292,8 @@ func (s *ServerContainer) createClientWithAuth(authorization string) (promv1.API
 		secret = authorization
 	}
 
-	rt := config.NewAuthorizationCredentialsRoundTripper(authType, config.NewInlineSecret(secret), s.defaultRT)
-	client, err := mcpProm.NewAPIClient(s.prometheusURL, rt)
+	rt := config.NewAuthorizationCredentialsRoundTripper(authType, config.NewInlineSecret(secret), base)
+	client, err := mcpProm.NewAPIClient(prometheusURL, rt)
 	if err != nil {
 		s.logger.Error("Failed to create API client with credentials", "err", err)
 		return nil, nil
@@ -332,15 +1301,18 @@ func (s *ServerContainer) createClientWithAuth(authorization string) (promv1.API
 	return client, rt
 }
 
-// FormatOutput encodes data as JSON or TOON based on configuration.
+// FormatOutput encodes data as JSON, TOON, or YAML based on configuration.
 func (s *ServerContainer) FormatOutput(data any) (string, error) {
-	if s.toonOutputEnabled {
+	switch s.outputFormat {
+	case OutputFormatTOON:
 		toonEncoded, err := gotoon.Encode(data)
 		if err != nil {
 			return "", fmt.Errorf("failed to TOON encode data: %w", err)
 		}
 
 		return toonEncoded, nil
+	case OutputFormatYAML:
+		return encodeYAML(data)
 	}
 
 	jsonEncoded, err := json.Marshal(data)
@@ -350,7 +1322,62 @@ func (s *ServerContainer) FormatOutput(data any) (string, error) {
 	return string(jsonEncoded), nil
 }
 
+// encodeYAML encodes data as YAML. The data is JSON marshaled first so that
+// the output respects the `json` struct tags and custom JSON marshalers used
+// by the Prometheus API types, then re-encoded as block-style YAML with key
+// order preserved.
+func encodeYAML(data any) (string, error) {
+	jsonEncoded, err := json.Marshal(data)
+	if err != nil {
+		return "", fmt.Errorf("failed to JSON marshal data: %w", err)
+	}
+
+	// JSON is valid YAML, so it can be decoded into a node tree directly.
+	var node yaml.Node
+	if err := yaml.Unmarshal(jsonEncoded, &node); err != nil {
+		return "", fmt.Errorf("failed to YAML decode data: %w", err)
+	}
+	clearYAMLStyle(&node)
+
+	yamlEncoded, err := yaml.Marshal(&node)
+	if err != nil {
+		return "", fmt.Errorf("failed to YAML encode data: %w", err)
+	}
+	return string(yamlEncoded), nil
+}
+
+// clearYAMLStyle resets the flow and quoting styles carried over from the
+// JSON input, so the encoder picks block style and only quotes when needed.
+func clearYAMLStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		clearYAMLStyle(child)
+	}
+}
+
+// getLookbackDelta returns the negative offset from the end time used as the
+// default start time of tools taking a time range, falling back to
+// DefaultLookbackDelta if none is configured.
+func (s *ServerContainer) getLookbackDelta() time.Duration {
+	if s.lookbackDelta == 0 {
+		return DefaultLookbackDelta
+	}
+	return s.lookbackDelta
+}
+
+// normalizeLookbackDelta turns a configured lookback delta into a negative
+// offset, so that both 15m and -15m mean 15 minutes ago. Zero is kept, which
+// selects DefaultLookbackDelta.
+func normalizeLookbackDelta(d time.Duration) time.Duration {
+	if d > 0 {
+		return -d
+	}
+	return d
+}
+
 // GetEffectiveTruncationLimit returns the per-call limit if set, otherwise the global limit.
+// For query results, the limit is in lines, bytes, or estimated tokens depending
+// on the truncation mode.
 func (s *ServerContainer) GetEffectiveTruncationLimit(perCallLimit int) int {
 	// Negative means the tool wants to override and disable truncation.
 	if perCallLimit < 0 {
@@ -366,11 +1393,58 @@ func (s *ServerContainer) GetEffectiveTruncationLimit(perCallLimit int) int {
 	return s.truncationLimit
 }
 
+// truncateResult truncates a query result to the given limit, counted in lines,
+// bytes, or estimated tokens depending on the configured truncation mode.
+func (s *ServerContainer) truncateResult(result string, limit int) (string, bool) {
+	switch s.truncationMode {
+	case TruncationModeBytes:
+		return truncateStringByBytes(result, limit)
+	case TruncationModeTokens:
+		return truncateStringByTokens(result, limit)
+	default:
+		return truncateStringByLines(result, limit)
+	}
+}
+
+// resultTruncationWarning returns the warning for a query result truncated by
+// truncateResult. In tokens mode it also reports the token estimate of the
+// full result.
+func (s *ServerContainer) resultTruncationWarning(result string, limit int) string {
+	warning := displayTruncationWarning(limit)
+	if s.truncationMode == TruncationModeTokens {
+		warning += fmt.Sprintf("\nThe full result was an estimated %d tokens, truncated to fit within %d tokens (estimated as %d characters per token).",
+			estimateTokens(result), limit, charsPerToken)
+	}
+	return warning
+}
+
+// GetEffectiveStripHelp returns the per-call strip help setting if set,
+// otherwise the global setting.
+func (s *ServerContainer) GetEffectiveStripHelp(perCall *bool) bool {
+	if perCall != nil {
+		return *perCall
+	}
+	return s.stripHelpText
+}
+
 // Docs search methods
 
 // errDocsNotProvided is returned when docs filesystem is not configured.
 var errDocsNotProvided = errors.New("docs filesystem not provided")
 
+// errDocsIndexTimeout is returned when building the docs search index exceeds
+// the configured index timeout.
+var errDocsIndexTimeout = errors.New("index build timed out")
+
+// docsIndexContext returns a context bounded by the configured docs index
+// timeout. A timeout of 0 means the index build is not bounded.
+func (s *ServerContainer) docsIndexContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if s.docsIndexTimeout > 0 {
+		return context.WithTimeout(ctx, s.docsIndexTimeout)
+	}
+	return context.WithCancel(ctx)
+}
+
 // swapDocsState replaces the current docs state with a new one.  It acquires
 // the write lock, and only after successfully swapping the new docs in does it
 // attempt to close the old docs search index.
@@ -388,8 +1462,13 @@ func (s *ServerContainer) swapDocsState(state *docsState) {
 }
 
 // buildDocsState creates a new docsState from the given filesystem.
-// It chunks the markdown files and builds a search index.
-func buildDocsState(logger *slog.Logger, docsFS fs.FS) (*docsState, error) {
+// It chunks the markdown files and builds a search index. Transient errors
+// reading a file, while indexing and later on, are retried readRetries times.
+//
+// If the context's deadline is exceeded while indexing, the partial index is
+// discarded and a docsState without a search index is returned alongside
+// errDocsIndexTimeout, so that the docs can still be listed and read.
+func buildDocsState(ctx context.Context, logger *slog.Logger, docsFS fs.FS, readRetries int) (*docsState, error) {
 	if docsFS == nil {
 		return nil, errDocsNotProvided
 	}
@@ -415,14 +1494,23 @@ func buildDocsState(logger *slog.Logger, docsFS fs.FS) (*docsState, error) {
 	bleveSetLogOnce.Do(func() {
 		bleve.SetLog(slog.NewLogLogger(logger.Handler(), slog.LevelDebug))
 	})
+	// Rank matches with BM25, which unlike the default TF-IDF scoring
+	// saturates repeated terms and normalizes for chunk length, so that
+	// multi-word queries favor chunks matching most of the query terms. BM25
+	// needs the scorch index type, which is kept in memory with an empty path.
 	mapping := bleve.NewIndexMapping()
-	searchIndex, err := bleve.NewMemOnly(mapping)
+	mapping.ScoringModel = index.BM25Scoring
+	searchIndex, err := bleve.NewUsing("", mapping, scorch.Name, scorch.Name, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create in-memory search index: %w", err)
 	}
 
 	for _, fn := range docFiles {
-		content, err := getDocFileContent(docsFS, fn)
+		if err := ctx.Err(); err != nil {
+			return abortDocsIndexBuild(logger, docsFS, readRetries, searchIndex, err)
+		}
+
+		content, err := getDocFileContentWithRetries(ctx, docsFS, fn, readRetries)
 		if err != nil {
 			logger.Error("Failed reading doc file", "file", fn, "err", err)
 			continue
@@ -450,15 +1538,78 @@ func buildDocsState(logger *slog.Logger, docsFS fs.FS) (*docsState, error) {
 	return &docsState{
 		fs:          docsFS,
 		searchIndex: searchIndex,
+		readRetries: readRetries,
 	}, nil
 }
 
-// SearchDocs searches the docs index and returns matching chunk IDs.
-func (s *ServerContainer) SearchDocs(q string, limit int) ([]string, error) {
+// abortDocsIndexBuild closes a partially built search index and returns a
+// docsState that only serves the docs filesystem.
+func abortDocsIndexBuild(logger *slog.Logger, docsFS fs.FS, readRetries int, searchIndex bleve.Index, cause error) (*docsState, error) {
+	if err := searchIndex.Close(); err != nil {
+		logger.Error("Failed to close partial search index", "err", err)
+	}
+
+	indexErr := fmt.Errorf("failed to build docs search index: %w", cause)
+	if errors.Is(cause, context.DeadlineExceeded) {
+		indexErr = errDocsIndexTimeout
+	}
+
+	return &docsState{
+		fs:          docsFS,
+		indexErr:    indexErr,
+		readRetries: readRetries,
+	}, indexErr
+}
+
+// docsSearchHit is a single chunk matched by a docs index search.
+type docsSearchHit struct {
+	ID      string
+	File    string
+	Content string
+	Score   float64
+}
+
+// DocsSearchResult is a docs chunk matched by SearchDocs, with its relevance
+// score and a short excerpt around the matched terms.
+type DocsSearchResult struct {
+	ChunkID string  `json:"chunk_id"`
+	File    string  `json:"file"`
+	Score   float64 `json:"score"`
+	Snippet string  `json:"snippet"`
+}
+
+// SearchDocs searches the docs index and returns the matching chunks, ordered
+// by descending relevance score.
+func (s *ServerContainer) SearchDocs(q string, limit int) ([]DocsSearchResult, error) {
+	hits, err := s.searchDocsHits(q, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]DocsSearchResult, 0, len(hits))
+	for _, hit := range hits {
+		result = append(result, DocsSearchResult{
+			ChunkID: hit.ID,
+			File:    hit.File,
+			// Scores are only meaningful relative to each other, three
+			// decimals are plenty to rank matches.
+			Score:   math.Round(hit.Score*1000) / 1000,
+			Snippet: docSnippet(hit.Content, q, docSnippetLength),
+		})
+	}
+	return result, nil
+}
+
+// searchDocsHits searches the docs index and returns the matching chunks,
+// including their stored content.
+func (s *ServerContainer) searchDocsHits(q string, limit int) ([]docsSearchHit, error) {
 	s.docsMu.RLock()
 	defer s.docsMu.RUnlock()
 
 	ds := s.docs
+	if ds != nil && ds.indexErr != nil {
+		return nil, ds.indexErr
+	}
 	if ds == nil || ds.searchIndex == nil {
 		return nil, errors.New("docs search index not initialized")
 	}
@@ -480,9 +1631,16 @@ func (s *ServerContainer) SearchDocs(q string, limit int) ([]string, error) {
 		return nil, fmt.Errorf("failed to search docs index: %w", err)
 	}
 
-	var result []string
+	result := make([]docsSearchHit, 0, len(searchRes.Hits))
 	for _, hit := range searchRes.Hits {
-		result = append(result, hit.ID)
+		name, _ := hit.Fields["Name"].(string)
+		content, _ := hit.Fields["Content"].(string)
+		result = append(result, docsSearchHit{
+			ID:      hit.ID,
+			File:    name,
+			Content: content,
+			Score:   hit.Score,
+		})
 	}
 	return result, nil
 }
@@ -499,16 +1657,19 @@ func (s *ServerContainer) GetDocFileNames() ([]string, error) {
 	return getDocFileNames(ds.fs)
 }
 
-// GetDocFileContent returns the content of a doc file.
-func (s *ServerContainer) GetDocFileContent(path string) (string, error) {
+// GetDocFileContent returns the content of a doc file, retrying transient
+// read errors if the docs are served from --docs.dir.
+func (s *ServerContainer) GetDocFileContent(ctx context.Context, path string) (string, error) {
+	// Don't hold the lock while backing off between retries; a docsState is
+	// never modified after it's swapped in.
 	s.docsMu.RLock()
-	defer s.docsMu.RUnlock()
-
 	ds := s.docs
+	s.docsMu.RUnlock()
+
 	if ds == nil || ds.fs == nil {
 		return "", errDocsNotProvided
 	}
-	return getDocFileContent(ds.fs, path)
+	return getDocFileContentWithRetries(ctx, ds.fs, path, ds.readRetries)
 }
 
 // Logging helper methods
@@ -542,6 +1703,57 @@ func (s *ServerContainer) GetToolLogger(req *mcp.CallToolRequest, input any) *sl
 	return logger
 }
 
+// startProgressHeartbeat sends a progress notification every progress
+// interval while a long-running tool call is in progress, so the client knows
+// the call is still alive rather than hung. Notifications are only sent if the
+// client asked for them by including a progress token in the request. The
+// returned function stops the notifications and must be called once the call
+// returns.
+func (s *ServerContainer) startProgressHeartbeat(ctx context.Context, req *mcp.CallToolRequest, operation string) func() {
+	if s.progressInterval <= 0 || req == nil || req.Session == nil || req.Params == nil {
+		return func() {}
+	}
+	token := req.Params.GetProgressToken()
+	if token == nil {
+		return func() {}
+	}
+
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+
+		start := time.Now()
+		ticker := time.NewTicker(s.progressInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				elapsed := time.Since(start)
+				err := req.Session.NotifyProgress(ctx, &mcp.ProgressNotificationParams{
+					ProgressToken: token,
+					// There is no known total, the elapsed time increases
+					// with every notification as required.
+					Progress: elapsed.Seconds(),
+					Message:  fmt.Sprintf("%s still running after %s", operation, elapsed.Round(time.Second)),
+				})
+				if err != nil {
+					s.logger.Debug("Failed to send progress notification", "operation", operation, "err", err)
+				}
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
+
 // MCP result helper methods
 
 // newToolTextResult creates a new CallToolResult with text content.
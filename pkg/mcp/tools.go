@@ -15,7 +15,9 @@ package mcp
 
 import (
 	"encoding/json"
+	"fmt"
 
+	"github.com/google/jsonschema-go/jsonschema"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
@@ -27,6 +29,33 @@ import (
 // See: https://github.com/prometheus/prometheus-mcp/issues/119
 var emptyInputSchema = json.RawMessage(`{"type":"object","properties":{},"additionalProperties":false}`)
 
+// Example argument values advertised in tool input schemas. LLMs tend to copy
+// the format of examples, which makes malformed arguments less likely.
+var (
+	timestampExamples = []any{"2026-01-02T15:04:05Z", "1767312000", "now-1h", "5m"}
+	stepExamples      = []any{"15s", "1m", "5m"}
+)
+
+// inputSchemaWithExamples infers the input schema of In the same way
+// mcp.AddTool does and adds examples to the given properties. Required
+// properties are still inferred from the json tags, so the SDK keeps
+// validating them. It panics if a property does not exist, since that is a
+// programming error.
+func inputSchemaWithExamples[In any](examples map[string][]any) *jsonschema.Schema {
+	schema, err := jsonschema.For[In](nil)
+	if err != nil {
+		panic(fmt.Sprintf("failed to infer input schema for %T: %v", *new(In), err))
+	}
+	for name, values := range examples {
+		prop, ok := schema.Properties[name]
+		if !ok {
+			panic(fmt.Sprintf("input schema for %T has no property %q", *new(In), name))
+		}
+		prop.Examples = values
+	}
+	return schema
+}
+
 // ptr returns a pointer to the given value.
 //
 // Needed mostly since `true` is a constant and we can't take the address of it
@@ -40,6 +69,9 @@ var (
 	queryToolDef = &mcp.Tool{
 		Name:        "query",
 		Description: "Execute an instant query against the Prometheus datasource",
+		InputSchema: inputSchemaWithExamples[QueryInput](map[string][]any{
+			"timestamp": timestampExamples,
+		}),
 		Annotations: &mcp.ToolAnnotations{
 			Title:        "Instant Query",
 			ReadOnlyHint: true,
@@ -49,12 +81,29 @@ var (
 	rangeQueryToolDef = &mcp.Tool{
 		Name:        "range_query",
 		Description: "Execute a range query against the Prometheus datasource",
+		InputSchema: inputSchemaWithExamples[RangeQueryInput](map[string][]any{
+			"start_time": timestampExamples,
+			"end_time":   timestampExamples,
+			"step":       stepExamples,
+		}),
 		Annotations: &mcp.ToolAnnotations{
 			Title:        "Range Query",
 			ReadOnlyHint: true,
 		},
 	}
 
+	queryStatsSummaryToolDef = &mcp.Tool{
+		Name:        "query_stats_summary",
+		Description: "Execute an instant query and return summary statistics (count, sum, min, max, mean, median) of the sample values across all returned series instead of the series themselves. Far more token-efficient than query when only the distribution of values matters. The query must return an instant vector",
+		InputSchema: inputSchemaWithExamples[QueryStatsSummaryInput](map[string][]any{
+			"timestamp": timestampExamples,
+		}),
+		Annotations: &mcp.ToolAnnotations{
+			Title:        "Query Stats Summary",
+			ReadOnlyHint: true,
+		},
+	}
+
 	exemplarQueryToolDef = &mcp.Tool{
 		Name:        "exemplar_query",
 		Description: "Execute an exemplar query against the Prometheus datasource to find trace exemplars associated with metric samples",
@@ -64,6 +113,33 @@ var (
 		},
 	}
 
+	exemplarCoverageToolDef = &mcp.Tool{
+		Name:        "exemplar_coverage",
+		Description: "Summarizes exemplar availability for a series selector over a time window: how many series have exemplars and a sample of their trace IDs. Use this to check whether trace correlation is possible for a metric before building a trace-linking workflow",
+		Annotations: &mcp.ToolAnnotations{
+			Title:        "Exemplar Coverage",
+			ReadOnlyHint: true,
+		},
+	}
+
+	queryExplainToolDef = &mcp.Tool{
+		Name:        "query_explain",
+		Description: "Parse a PromQL query without executing it and return its pretty-printed form, parse tree, result type, and the vector/matrix selectors and subqueries it contains, including any @ and offset modifiers. Syntax errors are reported with their exact position in the query. Use this to validate and debug a query before running an expensive range query",
+		Annotations: &mcp.ToolAnnotations{
+			Title:        "Explain Query",
+			ReadOnlyHint: true,
+		},
+	}
+
+	validateQueryToolDef = &mcp.Tool{
+		Name:        "validate_query",
+		Description: "Check whether a PromQL query is syntactically valid without executing it. Returns valid true, or valid false with the parse error and the byte offset of the problem in the query. Use this as a cheap preflight check before query or range_query",
+		Annotations: &mcp.ToolAnnotations{
+			Title:        "Validate Query",
+			ReadOnlyHint: true,
+		},
+	}
+
 	seriesToolDef = &mcp.Tool{
 		Name:        "series",
 		Description: "Finds series by label matches",
@@ -73,6 +149,51 @@ var (
 		},
 	}
 
+	seriesCountToolDef = &mcp.Tool{
+		Name:        "series_count",
+		Description: "Counts the series matching the label matchers, without returning their label sets. Use this instead of series to answer how many series a selector matches, it is much cheaper on context and never truncated",
+		Annotations: &mcp.ToolAnnotations{
+			Title:        "Count Series",
+			ReadOnlyHint: true,
+		},
+	}
+
+	labelsCardinalityToolDef = &mcp.Tool{
+		Name:        "labels_cardinality",
+		Description: "Summarizes the cardinality of the series matching a metric name or selector: the number of distinct values of each label, highest first. Use this to find which labels cause a cardinality explosion without listing all series",
+		Annotations: &mcp.ToolAnnotations{
+			Title:        "Labels Cardinality",
+			ReadOnlyHint: true,
+		},
+	}
+
+	seriesByLabelRegexToolDef = &mcp.Tool{
+		Name:        "series_by_label_regex",
+		Description: "Finds series of a metric whose label value matches a regular expression, without having to write a PromQL selector. The selector is built and escaped from the metric, label, and regex, and returned alongside the series",
+		Annotations: &mcp.ToolAnnotations{
+			Title:        "Series By Label Regex",
+			ReadOnlyHint: true,
+		},
+	}
+
+	findMetricsToolDef = &mcp.Tool{
+		Name:        "find_metrics",
+		Description: "Finds metric names matching a regular expression, in sorted order. The metric names are filtered on the MCP server, which is much cheaper on context than listing the values of the __name__ label",
+		Annotations: &mcp.ToolAnnotations{
+			Title:        "Find Metrics",
+			ReadOnlyHint: true,
+		},
+	}
+
+	otlpMetricsNamesToolDef = &mcp.Tool{
+		Name:        "otlp_metrics_names",
+		Description: "Lists metric names that appear to have been ingested through the OTLP receiver, in sorted order. By default only names that the OTLP to Prometheus translation alone produces are matched: the target_info and otel_scope_info metrics, and untranslated dotted names. A custom pattern can be given instead, e.g. to also match OpenTelemetry semantic convention namespaces like http_server_ or unit suffixes like _milliseconds, which native Prometheus instrumentation may share. Useful to verify that OTLP ingestion is landing",
+		Annotations: &mcp.ToolAnnotations{
+			Title:        "OTLP Metrics Names",
+			ReadOnlyHint: true,
+		},
+	}
+
 	labelNamesToolDef = &mcp.Tool{
 		Name:        "label_names",
 		Description: "Returns the unique label names present in the block in sorted order by given time range and matches",
@@ -91,6 +212,15 @@ var (
 		},
 	}
 
+	labelExplosionToolDef = &mcp.Tool{
+		Name:        "label_explosion",
+		Description: "Checks whether a label has an excessive number of distinct values (e.g. user IDs used as label values), a common cause of cardinality problems. Returns the value count, whether it exceeds the threshold, and a sample of values.",
+		Annotations: &mcp.ToolAnnotations{
+			Title:        "Label Explosion Check",
+			ReadOnlyHint: true,
+		},
+	}
+
 	metricMetadataToolDef = &mcp.Tool{
 		Name:        "metric_metadata",
 		Description: "Returns metadata about metrics currently scraped by the metric name.",
@@ -100,6 +230,15 @@ var (
 		},
 	}
 
+	metricsWithHelpToolDef = &mcp.Tool{
+		Name:        "metrics_with_help",
+		Description: "Lists metric names together with their help text and type as compact `name: help (type)` lines, sorted by name and optionally filtered by a name prefix. More concise than metric_metadata for discovering which metrics exist and what they measure.",
+		Annotations: &mcp.ToolAnnotations{
+			Title:        "Metrics With Help",
+			ReadOnlyHint: true,
+		},
+	}
+
 	targetsMetadataToolDef = &mcp.Tool{
 		Name:        "targets_metadata",
 		Description: "Returns metadata about metrics currently scraped by the target ",
@@ -119,6 +258,43 @@ var (
 		},
 	}
 
+	listSilencesToolDef = &mcp.Tool{
+		Name:        "list_silences",
+		Description: "Lists silences from the Alertmanager configured with --alertmanager.url, optionally filtered by label matchers",
+		Annotations: &mcp.ToolAnnotations{
+			Title:        "List Silences",
+			ReadOnlyHint: true,
+		},
+	}
+
+	alertmanagerAlertsToolDef = &mcp.Tool{
+		Name:        "alertmanager_alerts",
+		Description: "Lists alerts from the Alertmanager configured with --alertmanager.url, grouped by their routing groups, optionally filtered by label matchers. Unlike list_alerts, this shows the alerts Alertmanager received from all sources along with their silenced/inhibited state",
+		Annotations: &mcp.ToolAnnotations{
+			Title:        "Alertmanager Alerts",
+			ReadOnlyHint: true,
+		},
+	}
+
+	createSilenceToolDef = &mcp.Tool{
+		Name:        "create_silence",
+		Description: "Creates a silence in the Alertmanager configured with --alertmanager.url for alerts matching the given label matchers, starting now and lasting for the given duration. Returns the ID of the new silence.",
+		Annotations: &mcp.ToolAnnotations{
+			Title:           "Create Silence",
+			DestructiveHint: ptr(true),
+		},
+	}
+
+	alertmanagerStatusToolDef = &mcp.Tool{
+		Name:        "alertmanager_status",
+		Description: "Get the status of the Alertmanager configured with --alertmanager.url: its loaded configuration, a summary of the configured receivers and routing tree, cluster peers, and uptime. Useful to confirm which receivers and routes are active when diagnosing missing notifications",
+		InputSchema: emptyInputSchema,
+		Annotations: &mcp.ToolAnnotations{
+			Title:        "Alertmanager Status",
+			ReadOnlyHint: true,
+		},
+	}
+
 	flagsToolDef = &mcp.Tool{
 		Name:        "flags",
 		Description: "Get runtime flags",
@@ -131,14 +307,76 @@ var (
 
 	listAlertsToolDef = &mcp.Tool{
 		Name:        "list_alerts",
-		Description: "List all active alerts",
-		InputSchema: emptyInputSchema,
+		Description: "List active alerts, optionally filtered by state and label matchers, e.g. only firing alerts matching severity=\"critical\"",
 		Annotations: &mcp.ToolAnnotations{
 			Title:        "List Alerts",
 			ReadOnlyHint: true,
 		},
 	}
 
+	activeAlertsDetailToolDef = &mcp.Tool{
+		Name:        "active_alerts_detail",
+		Description: "List firing and pending alerts with their full label set, annotations, when they became active, and how long they have been active, sorted by longest-active first",
+		Annotations: &mcp.ToolAnnotations{
+			Title:        "Active Alerts Detail",
+			ReadOnlyHint: true,
+		},
+	}
+
+	targetChurnToolDef = &mcp.Tool{
+		Name:        "target_churn",
+		Description: "Report which scrape targets appeared or disappeared since the previous call of this tool, useful for spotting flapping service discovery. The first call records a baseline and reports no changes",
+		Annotations: &mcp.ToolAnnotations{
+			Title:        "Target Churn",
+			ReadOnlyHint: true,
+		},
+	}
+
+	sampleLimitsToolDef = &mcp.Tool{
+		Name:        "sample_limits",
+		Description: "Compare the number of samples each target currently exposes against the sample_limit configured for its scrape job, flagging targets that are close to or over their limit and at risk of failing scrapes. Targets are matched to scrape configs by their job label",
+		Annotations: &mcp.ToolAnnotations{
+			Title:        "Sample Limits",
+			ReadOnlyHint: true,
+		},
+	}
+
+	fleetHealthToolDef = &mcp.Tool{
+		Name:        "fleet_health",
+		Description: "Get the percentage of scrape targets that are up, grouped by a label such as namespace or team, flagging groups below a threshold. Use this for an organization-wide health rollup instead of listing all targets",
+		Annotations: &mcp.ToolAnnotations{
+			Title:        "Fleet Health",
+			ReadOnlyHint: true,
+		},
+	}
+
+	whyNoDataToolDef = &mcp.Tool{
+		Name:        "why_no_data",
+		Description: "Diagnose why a metric or selector returns no data. Checks whether matching series exist, whether they currently have samples or went stale, and the scrape health of the targets of the jobs involved, and returns a structured diagnosis such as 'no matching series', 'series exist but are stale', or 'target X is down'",
+		Annotations: &mcp.ToolAnnotations{
+			Title:        "Why No Data",
+			ReadOnlyHint: true,
+		},
+	}
+
+	detectGapsToolDef = &mcp.Tool{
+		Name:        "detect_gaps",
+		Description: "Find gaps in the series matching a selector over a time range, reporting the intervals where samples are missing for longer than the expected resolution. Use this to find scrape outages or flapping exporters that the `up` metric alone may not show",
+		Annotations: &mcp.ToolAnnotations{
+			Title:        "Detect Gaps",
+			ReadOnlyHint: true,
+		},
+	}
+
+	metricsMissingMetadataToolDef = &mcp.Tool{
+		Name:        "metrics_missing_metadata",
+		Description: "Lists metric names that have samples but no metadata (HELP/TYPE), which often indicates improperly exposed metrics. Recording rule outputs and series generated by Prometheus itself are excluded",
+		Annotations: &mcp.ToolAnnotations{
+			Title:        "Metrics Missing Metadata",
+			ReadOnlyHint: true,
+		},
+	}
+
 	tsdbStatsToolDef = &mcp.Tool{
 		Name:        "tsdb_stats",
 		Description: "Get usage and cardinality statistics from the TSDB",
@@ -169,6 +407,44 @@ var (
 		},
 	}
 
+	jobConfigToolDef = &mcp.Tool{
+		Name:        "job_config",
+		Description: "Get the scrape config of a single job from the loaded Prometheus configuration as YAML, including its relabel rules, service discovery, and scrape intervals. Credentials are redacted. Use this instead of config to see how a specific job is scraped",
+		Annotations: &mcp.ToolAnnotations{
+			Title:        "Job Config",
+			ReadOnlyHint: true,
+		},
+	}
+
+	scrapeConfigsToolDef = &mcp.Tool{
+		Name:        "scrape_configs",
+		Description: "Get only the scrape_configs section of the loaded Prometheus configuration, optionally filtered by job_name, as structured output instead of the full config YAML. Credentials are redacted",
+		Annotations: &mcp.ToolAnnotations{
+			Title:        "Scrape Configs",
+			ReadOnlyHint: true,
+		},
+	}
+
+	configPendingChangesToolDef = &mcp.Tool{
+		Name:        "config_pending_changes",
+		Description: "Compare the Prometheus configuration file on disk against the currently loaded configuration to determine whether a reload is needed and what would change. Requires the MCP server to be started with `--prometheus.config-path`.",
+		InputSchema: emptyInputSchema,
+		Annotations: &mcp.ToolAnnotations{
+			Title:        "Config Pending Changes",
+			ReadOnlyHint: true,
+		},
+	}
+
+	configDiffToolDef = &mcp.Tool{
+		Name:        "config_diff",
+		Description: "Show a unified diff between a Prometheus configuration file on disk and the currently loaded configuration, with credentials redacted. Lines prefixed with `-` are only in the file, lines prefixed with `+` only in the loaded config. The loaded config has all defaults filled in, so defaults omitted from the file also show up as differences; use config_pending_changes to only see changes that a reload would apply. Requires the MCP server to be started with `--prometheus.config-path`.",
+		InputSchema: emptyInputSchema,
+		Annotations: &mcp.ToolAnnotations{
+			Title:        "Config Diff",
+			ReadOnlyHint: true,
+		},
+	}
+
 	runtimeInfoToolDef = &mcp.Tool{
 		Name:        "runtime_info",
 		Description: "Get Prometheus runtime information",
@@ -181,8 +457,7 @@ var (
 
 	listRulesToolDef = &mcp.Tool{
 		Name:        "list_rules",
-		Description: "List all alerting and recording rules that are loaded",
-		InputSchema: emptyInputSchema,
+		Description: "List the alerting and recording rules that are loaded. Rules can be filtered by type, rule group, and file, and exclude_alerts omits the active alerts of alerting rules, to keep large rule sets manageable",
 		Annotations: &mcp.ToolAnnotations{
 			Title:        "List Rules",
 			ReadOnlyHint: true,
@@ -191,14 +466,40 @@ var (
 
 	listTargetsToolDef = &mcp.Tool{
 		Name:        "list_targets",
-		Description: "Get overview of Prometheus target discovery",
-		InputSchema: emptyInputSchema,
+		Description: "Get overview of Prometheus target discovery. Targets can be filtered by scrape pool, state, and health to keep large target lists manageable",
 		Annotations: &mcp.ToolAnnotations{
 			Title:        "List Targets",
 			ReadOnlyHint: true,
 		},
 	}
 
+	remoteReadToolDef = &mcp.Tool{
+		Name:        "remote_read",
+		Description: "Read the raw samples of the series matching a selector over a time range from the remote read endpoint configured with --prometheus.remote-read-url, such as a long-term storage that only supports remote read. The result has the same format as range_query, with each series' raw samples instead of evaluated steps",
+		Annotations: &mcp.ToolAnnotations{
+			Title:        "Remote Read",
+			ReadOnlyHint: true,
+		},
+	}
+
+	scrapeTargetToolDef = &mcp.Tool{
+		Name:        "scrape_target",
+		Description: "Fetch the raw metrics exposition from a scrape target, as Prometheus would see it when scraping. The URL must be the scrape URL of an active or dropped target known to Prometheus. Use this to debug what a target exposes; the request is made directly from the MCP server without Prometheus' HTTP client credentials",
+		Annotations: &mcp.ToolAnnotations{
+			Title:        "Scrape Target",
+			ReadOnlyHint: true,
+		},
+	}
+
+	waitReadyToolDef = &mcp.Tool{
+		Name:        "wait_ready",
+		Description: "Waits until Prometheus is ready to serve traffic by polling its readiness endpoint, e.g. after a restart while the WAL is replayed. Returns whether it became ready before the timeout, the last readiness response, the elapsed time, and optionally the WAL replay progress",
+		Annotations: &mcp.ToolAnnotations{
+			Title:        "Wait Until Ready",
+			ReadOnlyHint: true,
+		},
+	}
+
 	walReplayToolDef = &mcp.Tool{
 		Name:        "wal_replay_status",
 		Description: "Get current WAL replay status",
@@ -213,7 +514,6 @@ var (
 	cleanTombstonesToolDef = &mcp.Tool{
 		Name:        "clean_tombstones",
 		Description: "Removes the deleted data from disk and cleans up the existing tombstones",
-		InputSchema: emptyInputSchema,
 		Annotations: &mcp.ToolAnnotations{
 			Title:           "Clean Tombstones",
 			DestructiveHint: ptr(true),
@@ -231,7 +531,7 @@ var (
 
 	snapshotToolDef = &mcp.Tool{
 		Name:        "snapshot",
-		Description: "creates a snapshot of all current data into snapshots/<datetime>-<rand> under the TSDB's data directory and returns the directory as response.",
+		Description: "creates a snapshot of all current data into snapshots/<datetime>-<rand> under the TSDB's data directory. Returns the snapshot name and, if the TSDB path can be read from the Prometheus flags, the full snapshot directory.",
 		Annotations: &mcp.ToolAnnotations{
 			Title:           "Create Snapshot",
 			DestructiveHint: ptr(true),
@@ -249,6 +549,16 @@ var (
 		},
 	}
 
+	statusOverviewToolDef = &mcp.Tool{
+		Name:        "status_overview",
+		Description: "Get a one-shot overview of the Prometheus server: health, readiness, build info, runtime info, and TSDB stats. Each section reports its own status, so a failing check doesn't hide the others",
+		InputSchema: emptyInputSchema,
+		Annotations: &mcp.ToolAnnotations{
+			Title:        "Status Overview",
+			ReadOnlyHint: true,
+		},
+	}
+
 	readyToolDef = &mcp.Tool{
 		Name:        "ready",
 		Description: "Management API endpoint that can be used to check Prometheus is ready to serve traffic (i.e. respond to queries.)",
@@ -279,6 +589,35 @@ var (
 		},
 	}
 
+	testRelabelToolDef = &mcp.Tool{
+		Name:        "test_relabel",
+		Description: "Simulate relabeling by applying relabel config rules (as in relabel_configs or metric_relabel_configs) to a sample label set, returning the resulting labels after each rule and whether the target/series would be kept or dropped. Nothing is changed on the Prometheus server.",
+		Annotations: &mcp.ToolAnnotations{
+			Title:        "Test Relabel",
+			ReadOnlyHint: true,
+		},
+	}
+
+	mcpConfigToolDef = &mcp.Tool{
+		Name:        "mcp_config",
+		Description: "Get the effective configuration of this MCP server itself (not Prometheus), including the backend URL, timeouts, truncation limit, output format, enabled and disabled tools, transport, and docs status. Secrets are redacted",
+		InputSchema: emptyInputSchema,
+		Annotations: &mcp.ToolAnnotations{
+			Title:        "MCP Server Config",
+			ReadOnlyHint: true,
+		},
+	}
+
+	effectiveLimitsToolDef = &mcp.Tool{
+		Name:        "effective_limits",
+		Description: "Get the limits that currently apply to tool calls from this session, such as the API timeout, truncation limit, and maximum points per series for range queries, so queries can be tailored to stay within bounds. Does not contact Prometheus",
+		InputSchema: emptyInputSchema,
+		Annotations: &mcp.ToolAnnotations{
+			Title:        "Effective Limits",
+			ReadOnlyHint: true,
+		},
+	}
+
 	// Documentation tools.
 	docsListToolDef = &mcp.Tool{
 		Name:        "docs_list",
@@ -292,7 +631,7 @@ var (
 
 	docsReadToolDef = &mcp.Tool{
 		Name:        "docs_read",
-		Description: "Read the named markdown file containing official Prometheus documentation from the prometheus/docs repo",
+		Description: "Read the named markdown file containing official Prometheus documentation from the prometheus/docs repo. Large files can be read in parts with start_line and end_line, the response then notes the total number of lines in the file",
 		Annotations: &mcp.ToolAnnotations{
 			Title:        "Read Documentation",
 			ReadOnlyHint: true,
@@ -301,13 +640,31 @@ var (
 
 	docsSearchToolDef = &mcp.Tool{
 		Name:        "docs_search",
-		Description: "Search the markdown files containing official Prometheus documentation from the prometheus/docs repo",
+		Description: "Search the markdown files containing official Prometheus documentation from the prometheus/docs repo. Matches are ranked by relevance score with a snippet of each, followed by the content of the matching files",
 		Annotations: &mcp.ToolAnnotations{
 			Title:        "Search Documentation",
 			ReadOnlyHint: true,
 		},
 	}
 
+	promqlRecipeToolDef = &mcp.Tool{
+		Name:        "promql_recipe",
+		Description: "Suggest a PromQL query skeleton for a natural-language goal, along with related snippets from the official Prometheus documentation. This is advisory only: the query is not executed, and its <placeholders> must be replaced with real metric names and label matchers before use.",
+		Annotations: &mcp.ToolAnnotations{
+			Title:        "PromQL Recipe",
+			ReadOnlyHint: true,
+		},
+	}
+
+	examplesToolDef = &mcp.Tool{
+		Name:        "examples",
+		Description: "List known-good example PromQL queries extracted from fenced code blocks in the official Prometheus documentation, along with the documentation file each came from. Optionally filter to documentation matching a search query.",
+		Annotations: &mcp.ToolAnnotations{
+			Title:        "PromQL Examples",
+			ReadOnlyHint: true,
+		},
+	}
+
 	// Thanos-specific tools.
 	thanosStoresToolDef = &mcp.Tool{
 		Name:        "list_stores",
@@ -318,4 +675,24 @@ var (
 			ReadOnlyHint: true,
 		},
 	}
+
+	// Mimir-specific tools.
+	listTenantsToolDef = &mcp.Tool{
+		Name:        "list_tenants",
+		Description: "List the IDs of the tenants known to a multi-tenant Mimir, Cortex, or Grafana Enterprise Metrics cluster, read from the admin endpoint configured with --mimir.tenants-path. Use it to find the tenant to query before sending per-tenant requests with the X-Scope-OrgID header",
+		Annotations: &mcp.ToolAnnotations{
+			Title:        "List Tenants",
+			ReadOnlyHint: true,
+		},
+	}
+
+	// VictoriaMetrics-specific tools.
+	vmCardinalityToolDef = &mcp.Tool{
+		Name:        "vm_cardinality",
+		Description: "Get VictoriaMetrics cardinality stats for a day: total series and label-value pairs, and the metric names, labels, and label-value pairs with the most series, with how often each metric name is queried when VictoriaMetrics tracks it",
+		Annotations: &mcp.ToolAnnotations{
+			Title:        "VictoriaMetrics Cardinality",
+			ReadOnlyHint: true,
+		},
+	}
 )
@@ -26,8 +26,8 @@ type EmptyInput struct{}
 
 // TimeRangeInput provides optional start/end time parameters for time-bounded queries.
 type TimeRangeInput struct {
-	StartTime string `json:"start_time,omitempty" jsonschema:"start timestamp for the query. Accepts: Unix epoch seconds, RFC3339, or a duration string relative to now (e.g. 5m, 1h30m, etc). Defaults to 5m ago."`
-	EndTime   string `json:"end_time,omitempty" jsonschema:"end timestamp for the query. Accepts: Unix epoch seconds, RFC3339, or a duration string relative to now (e.g. 5m, 1h30m, etc). Defaults to current time."`
+	StartTime string `json:"start_time,omitempty" jsonschema:"start timestamp for the query. Accepts: Unix epoch seconds, RFC3339, a duration string relative to now (e.g. 5m, 1h30m, etc), or a relative time expression (e.g. now-1h, now-30m+5m, today). Defaults to 5m ago."`
+	EndTime   string `json:"end_time,omitempty" jsonschema:"end timestamp for the query. Accepts: Unix epoch seconds, RFC3339, a duration string relative to now (e.g. 5m, 1h30m, etc), or a relative time expression (e.g. now-1h, now-30m+5m, today). Defaults to current time."`
 }
 
 // TruncatableInput provides optional truncation limit for query responses.
@@ -35,13 +35,56 @@ type TruncatableInput struct {
 	TruncationLimit int `json:"truncation_limit,omitempty" jsonschema:"truncation limit for query response in number of lines/entries, set to -1 to disable truncation"`
 }
 
+// PaginationInput provides optional pagination for tools returning long lists.
+type PaginationInput struct {
+	Page     int `json:"page,omitempty" jsonschema:"1-based page of results to return. Setting page or page_size enables pagination, which replaces truncation. Use the next_page value of the previous response to iterate."`
+	PageSize int `json:"page_size,omitempty" jsonschema:"number of entries per page when paginating. Defaults to 500, at most 10000."`
+}
+
+// SeriesLimitInput provides an optional server-side series limit for query
+// responses.
+type SeriesLimitInput struct {
+	SeriesLimit int `json:"series_limit,omitempty" jsonschema:"maximum number of series for Prometheus to return, enforced server-side via the API's 'limit' parameter. Applied before truncation_limit. Unlimited if unset."`
+}
+
+// SortInput provides optional sorting of query results.
+type SortInput struct {
+	SortBy    string `json:"sort_by,omitempty" jsonschema:"optional sort for the result series, so the most relevant ones come first and survive truncation: 'value' to sort by sample value (the latest sample for range queries), or 'label:<name>' to sort by the value of a label. Defaults to the backend's order."`
+	SortOrder string `json:"sort_order,omitempty" jsonschema:"order to sort in when sort_by is set, one of 'asc' or 'desc'. Defaults to 'desc' for values and 'asc' for labels."`
+}
+
+// ListSortInput provides optional sorting of list results.
+type ListSortInput struct {
+	Sort string `json:"sort,omitempty" jsonschema:"order of the results before truncation or pagination: 'asc' or 'desc' to sort alphabetically, or 'none' to keep the backend's order. Defaults to 'asc', so truncated results are stable across calls."`
+}
+
+// StripHelpInput provides an optional per-call override for stripping metric
+// help text from metadata responses.
+type StripHelpInput struct {
+	StripHelp *bool `json:"strip_help,omitempty" jsonschema:"omit metric help text from the response to save tokens when only types/units are needed. Defaults to the server's --mcp.strip-help-text setting."`
+}
+
+// TargetInput provides an optional selection of a named Prometheus backend.
+type TargetInput struct {
+	Target string `json:"target,omitempty" jsonschema:"name of the Prometheus backend to query, as configured with --prometheus.url name=url. Defaults to the default backend."`
+}
+
+// TimeoutInput provides an optional per-call override of the API timeout.
+type TimeoutInput struct {
+	Timeout string `json:"timeout,omitempty" jsonschema:"timeout for this call as a duration (e.g. '10s', '2m'), to allow more time for a heavy query or less for a quick probe. Query tools also send it to Prometheus as the evaluation timeout. Defaults to the server's --prometheus.timeout and is capped at --prometheus.max-timeout, see the effective_limits tool."`
+}
+
 // Tool definition structs
 
 // QueryInput is the input for the instant query tool.
 type QueryInput struct {
 	Query     string `json:"query" jsonschema:"the PromQL query to execute"`
-	Timestamp string `json:"timestamp,omitempty" jsonschema:"evaluation timestamp for the instant query. Accepts: Unix epoch seconds, RFC3339, or a duration string relative to now e.g. 5m, 1h30m, etc. Defaults to current time."`
+	Timestamp string `json:"timestamp,omitempty" jsonschema:"evaluation timestamp for the instant query. Accepts: Unix epoch seconds, RFC3339, a duration string relative to now e.g. 5m, 1h30m, etc, or a relative time expression e.g. now-1h, today. Defaults to current time."`
+	TimeoutInput
+	SeriesLimitInput
+	SortInput
 	TruncatableInput
+	TargetInput
 }
 
 // LogValue implements slog.LogValuer.
@@ -49,15 +92,25 @@ func (qi QueryInput) LogValue() slog.Value {
 	return slog.GroupValue(
 		slog.String("query", qi.Query),
 		slog.String("timestamp", qi.Timestamp),
+		slog.String("timeout", qi.Timeout),
+		slog.Int("series_limit", qi.SeriesLimit),
+		slog.String("sort_by", qi.SortBy),
+		slog.String("sort_order", qi.SortOrder),
+		slog.String("target", qi.Target),
 	)
 }
 
 // RangeQueryInput is the input for the range query tool.
 type RangeQueryInput struct {
-	Query string `json:"query" jsonschema:"the PromQL query to execute"`
-	Step  string `json:"step,omitempty" jsonschema:"query resolution step width in Go duration format (e.g. '30s', '5m', '1h'), auto-set if unspecified"`
+	Query  string `json:"query" jsonschema:"the PromQL query to execute"`
+	Step   string `json:"step,omitempty" jsonschema:"query resolution step width in Go duration format (e.g. '15s', '1m', '5m'), auto-set if unspecified"`
+	Format string `json:"format,omitempty" jsonschema:"text format of the result: 'default' for the Prometheus string format, or 'csv' for a CSV table with a column per label followed by timestamp and value columns and a row per sample. Defaults to 'default'."`
 	TimeRangeInput
+	TimeoutInput
+	SeriesLimitInput
+	SortInput
 	TruncatableInput
+	TargetInput
 }
 
 // LogValue implements slog.LogValuer.
@@ -65,32 +118,106 @@ func (rqi RangeQueryInput) LogValue() slog.Value {
 	return slog.GroupValue(
 		slog.String("query", rqi.Query),
 		slog.String("step", rqi.Step),
+		slog.String("format", rqi.Format),
 		slog.String("start_time", rqi.StartTime),
 		slog.String("end_time", rqi.EndTime),
+		slog.String("timeout", rqi.Timeout),
+		slog.Int("series_limit", rqi.SeriesLimit),
+		slog.String("sort_by", rqi.SortBy),
+		slog.String("sort_order", rqi.SortOrder),
+		slog.String("target", rqi.Target),
+	)
+}
+
+// QueryStatsSummaryInput is the input for the query stats summary tool.
+type QueryStatsSummaryInput struct {
+	Query     string `json:"query" jsonschema:"the PromQL query to execute. It must return an instant vector.,required"`
+	Timestamp string `json:"timestamp,omitempty" jsonschema:"evaluation timestamp for the instant query. Accepts: Unix epoch seconds, RFC3339, a duration string relative to now e.g. 5m, 1h30m, etc, or a relative time expression e.g. now-1h, today. Defaults to current time."`
+	TargetInput
+}
+
+// LogValue implements slog.LogValuer.
+func (qssi QueryStatsSummaryInput) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("query", qssi.Query),
+		slog.String("timestamp", qssi.Timestamp),
+		slog.String("target", qssi.Target),
 	)
 }
 
 // ExemplarQueryInput is the input for the exemplar query tool.
 type ExemplarQueryInput struct {
-	Query string `json:"query" jsonschema:"the PromQL query to execute"`
+	Query  string `json:"query" jsonschema:"the PromQL query to execute"`
+	Format string `json:"format,omitempty" jsonschema:"format of the result: 'default' for the exemplars as JSON Lines wrapped in a result object with warnings, or 'jsonl' for only the JSON Lines, one object per series, which is easier to stream-parse. A truncated 'jsonl' result ends with a line holding the truncation warning. Defaults to 'default'."`
 	TimeRangeInput
 	TruncatableInput
+	TargetInput
 }
 
 // LogValue implements slog.LogValuer.
 func (eqi ExemplarQueryInput) LogValue() slog.Value {
 	return slog.GroupValue(
 		slog.String("query", eqi.Query),
+		slog.String("format", eqi.Format),
 		slog.String("start_time", eqi.StartTime),
 		slog.String("end_time", eqi.EndTime),
+		slog.String("target", eqi.Target),
+	)
+}
+
+// ExemplarCoverageInput is the input for the exemplar coverage tool.
+type ExemplarCoverageInput struct {
+	Selector       string `json:"selector" jsonschema:"series selector bounding which series to check for exemplars, e.g. http_request_duration_seconds_bucket{job=\"api\"},required"`
+	TraceIDSamples int    `json:"trace_id_samples,omitempty" jsonschema:"maximum number of distinct trace IDs to sample. Defaults to 5."`
+	TimeRangeInput
+	TruncatableInput
+	TargetInput
+}
+
+// LogValue implements slog.LogValuer.
+func (eci ExemplarCoverageInput) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("selector", eci.Selector),
+		slog.Int("trace_id_samples", eci.TraceIDSamples),
+		slog.String("start_time", eci.StartTime),
+		slog.String("end_time", eci.EndTime),
+		slog.String("target", eci.Target),
+	)
+}
+
+// QueryExplainInput is the input for the query explain tool.
+type QueryExplainInput struct {
+	Query string `json:"query" jsonschema:"the PromQL query to parse and explain"`
+}
+
+// LogValue implements slog.LogValuer.
+func (qei QueryExplainInput) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("query", qei.Query),
+	)
+}
+
+// ValidateQueryInput is the input for the validate query tool.
+type ValidateQueryInput struct {
+	Query string `json:"query" jsonschema:"the PromQL query to validate"`
+}
+
+// LogValue implements slog.LogValuer.
+func (vqi ValidateQueryInput) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("query", vqi.Query),
 	)
 }
 
 // SeriesInput is the input for the series query tool.
 type SeriesInput struct {
-	Matches []string `json:"matches" jsonschema:"series selector arguments that select the series to return,required"`
+	Matches []string `json:"matches,omitempty" jsonschema:"series selector arguments that select the series to return. Required unless the server allows empty matchers."`
 	TimeRangeInput
+	TimeoutInput
+	ListSortInput
 	TruncatableInput
+	PaginationInput
+	TargetInput
 }
 
 // LogValue implements slog.LogValuer.
@@ -99,6 +226,101 @@ func (si SeriesInput) LogValue() slog.Value {
 		slog.Any("matches", si.Matches),
 		slog.String("start_time", si.StartTime),
 		slog.String("end_time", si.EndTime),
+		slog.String("timeout", si.Timeout),
+		slog.String("sort", si.Sort),
+		slog.Int("page", si.Page),
+		slog.Int("page_size", si.PageSize),
+		slog.String("target", si.Target),
+	)
+}
+
+// LabelsCardinalityInput is the input for the labels cardinality tool.
+type LabelsCardinalityInput struct {
+	Selector string `json:"selector" jsonschema:"metric name or series selector of the series to summarize, e.g. http_requests_total or {job=\"api\"},required"`
+	TopN     int    `json:"top_n,omitempty" jsonschema:"optional number of labels to return, highest cardinality first. Defaults to 10."`
+	TimeRangeInput
+	TargetInput
+}
+
+// LogValue implements slog.LogValuer.
+func (lci LabelsCardinalityInput) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("selector", lci.Selector),
+		slog.Int("top_n", lci.TopN),
+		slog.String("start_time", lci.StartTime),
+		slog.String("end_time", lci.EndTime),
+		slog.String("target", lci.Target),
+	)
+}
+
+// SeriesCountInput is the input for the series count tool.
+type SeriesCountInput struct {
+	Matches []string `json:"matches,omitempty" jsonschema:"series selector arguments that select the series to count. Required unless the server allows empty matchers."`
+	TimeRangeInput
+	TargetInput
+}
+
+// LogValue implements slog.LogValuer.
+func (sci SeriesCountInput) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.Any("matches", sci.Matches),
+		slog.String("start_time", sci.StartTime),
+		slog.String("end_time", sci.EndTime),
+		slog.String("target", sci.Target),
+	)
+}
+
+// SeriesByLabelRegexInput is the input for the series by label regex tool.
+type SeriesByLabelRegexInput struct {
+	Metric string `json:"metric" jsonschema:"the metric name to select series for,required"`
+	Label  string `json:"label" jsonschema:"the label name to match against,required"`
+	Regex  string `json:"regex" jsonschema:"RE2 regular expression the label value must fully match. It is escaped into the matcher automatically.,required"`
+	TimeRangeInput
+	TruncatableInput
+	TargetInput
+}
+
+// LogValue implements slog.LogValuer.
+func (sblri SeriesByLabelRegexInput) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("metric", sblri.Metric),
+		slog.String("label", sblri.Label),
+		slog.String("regex", sblri.Regex),
+		slog.String("start_time", sblri.StartTime),
+		slog.String("end_time", sblri.EndTime),
+		slog.String("target", sblri.Target),
+	)
+}
+
+// FindMetricsInput is the input for the find metrics tool.
+type FindMetricsInput struct {
+	Pattern string `json:"pattern" jsonschema:"RE2 regular expression to search metric names for, e.g. '^node_cpu' for metrics starting with node_cpu. It matches anywhere in the name unless anchored with ^ or $.,required"`
+	Limit   int    `json:"limit,omitempty" jsonschema:"maximum number of matching metric names to return. Defaults to 100."`
+	TargetInput
+}
+
+// LogValue implements slog.LogValuer.
+func (fmi FindMetricsInput) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("pattern", fmi.Pattern),
+		slog.Int("limit", fmi.Limit),
+		slog.String("target", fmi.Target),
+	)
+}
+
+// OTLPMetricsNamesInput is the input for the OTLP metrics names tool.
+type OTLPMetricsNamesInput struct {
+	Pattern string `json:"pattern,omitempty" jsonschema:"RE2 regular expression that overrides the default heuristic for metric names ingested through OTLP, e.g. '^(http_server|rpc_client)_|_milliseconds$' for OpenTelemetry semantic conventions or '^myapp_' for the naming conventions of a specific instrumentation. It matches anywhere in the name unless anchored with ^ or $."`
+	Limit   int    `json:"limit,omitempty" jsonschema:"maximum number of matching metric names to return. Defaults to 100."`
+	TargetInput
+}
+
+// LogValue implements slog.LogValuer.
+func (omni OTLPMetricsNamesInput) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("pattern", omni.Pattern),
+		slog.Int("limit", omni.Limit),
+		slog.String("target", omni.Target),
 	)
 }
 
@@ -107,6 +329,7 @@ type LabelNamesInput struct {
 	Matches []string `json:"matches,omitempty" jsonschema:"series selector arguments to filter label names"`
 	TimeRangeInput
 	TruncatableInput
+	TargetInput
 }
 
 // LogValue implements slog.LogValuer.
@@ -115,6 +338,7 @@ func (lni LabelNamesInput) LogValue() slog.Value {
 		slog.Any("matches", lni.Matches),
 		slog.String("start_time", lni.StartTime),
 		slog.String("end_time", lni.EndTime),
+		slog.String("target", lni.Target),
 	)
 }
 
@@ -123,7 +347,10 @@ type LabelValuesInput struct {
 	Label   string   `json:"label" jsonschema:"the label to query values for,required"`
 	Matches []string `json:"matches,omitempty" jsonschema:"series selector arguments to filter label values"`
 	TimeRangeInput
+	ListSortInput
 	TruncatableInput
+	PaginationInput
+	TargetInput
 }
 
 // LogValue implements slog.LogValuer.
@@ -133,6 +360,31 @@ func (lvi LabelValuesInput) LogValue() slog.Value {
 		slog.Any("matches", lvi.Matches),
 		slog.String("start_time", lvi.StartTime),
 		slog.String("end_time", lvi.EndTime),
+		slog.String("sort", lvi.Sort),
+		slog.Int("page", lvi.Page),
+		slog.Int("page_size", lvi.PageSize),
+		slog.String("target", lvi.Target),
+	)
+}
+
+// LabelExplosionInput is the input for the label explosion tool.
+type LabelExplosionInput struct {
+	Label     string   `json:"label" jsonschema:"the label to check for an excessive number of distinct values,required"`
+	Threshold int      `json:"threshold,omitempty" jsonschema:"number of distinct values above which the label is flagged as exploding. Defaults to 1000."`
+	Matches   []string `json:"matches,omitempty" jsonschema:"series selector arguments to filter label values"`
+	TimeRangeInput
+	TargetInput
+}
+
+// LogValue implements slog.LogValuer.
+func (lei LabelExplosionInput) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("label", lei.Label),
+		slog.Int("threshold", lei.Threshold),
+		slog.Any("matches", lei.Matches),
+		slog.String("start_time", lei.StartTime),
+		slog.String("end_time", lei.EndTime),
+		slog.String("target", lei.Target),
 	)
 }
 
@@ -140,6 +392,8 @@ func (lvi LabelValuesInput) LogValue() slog.Value {
 type MetricMetadataInput struct {
 	Metric string `json:"metric,omitempty" jsonschema:"metric name to retrieve metadata for, all metrics if empty"`
 	Limit  string `json:"limit,omitempty" jsonschema:"maximum number of metrics to return"`
+	StripHelpInput
+	TargetInput
 }
 
 // LogValue implements slog.LogValuer.
@@ -147,6 +401,24 @@ func (mmi MetricMetadataInput) LogValue() slog.Value {
 	return slog.GroupValue(
 		slog.String("metric", mmi.Metric),
 		slog.String("limit", mmi.Limit),
+		slog.Any("strip_help", mmi.StripHelp),
+		slog.String("target", mmi.Target),
+	)
+}
+
+// MetricsWithHelpInput is the input for the metrics with help tool.
+type MetricsWithHelpInput struct {
+	Prefix          string `json:"prefix,omitempty" jsonschema:"only list metrics whose name starts with this prefix, e.g. 'node_' or 'http_', all metrics if empty"`
+	TruncationLimit int    `json:"truncation_limit,omitempty" jsonschema:"truncation limit for the listing in number of lines, set to -1 to disable truncation"`
+	TargetInput
+}
+
+// LogValue implements slog.LogValuer.
+func (mwhi MetricsWithHelpInput) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("prefix", mwhi.Prefix),
+		slog.Int("truncation_limit", mwhi.TruncationLimit),
+		slog.String("target", mwhi.Target),
 	)
 }
 
@@ -155,6 +427,7 @@ type TargetsMetadataInput struct {
 	MatchTarget string `json:"match_target,omitempty" jsonschema:"label selectors to match targets, all targets if empty"`
 	Metric      string `json:"metric,omitempty" jsonschema:"metric name to retrieve metadata for, all metrics if empty"`
 	Limit       string `json:"limit,omitempty" jsonschema:"maximum number of targets to match"`
+	StripHelpInput
 }
 
 // LogValue implements slog.LogValuer.
@@ -163,13 +436,21 @@ func (tmi TargetsMetadataInput) LogValue() slog.Value {
 		slog.String("match_target", tmi.MatchTarget),
 		slog.String("metric", tmi.Metric),
 		slog.String("limit", tmi.Limit),
+		slog.Any("strip_help", tmi.StripHelp),
 	)
 }
 
+// ConfirmationInput holds the confirmation token required by destructive
+// tools when the server is started with --dangerous.require-confirmation.
+type ConfirmationInput struct {
+	Confirm string `json:"confirm,omitempty" jsonschema:"confirmation token, only required if the server requires confirmation of destructive operations. Calling the tool without it returns an error describing how to confirm."`
+}
+
 // DeleteSeriesInput is the input for the delete series admin tool.
 type DeleteSeriesInput struct {
 	Matches []string `json:"matches" jsonschema:"series selector arguments for series to delete,required"`
 	TimeRangeInput
+	ConfirmationInput
 }
 
 // LogValue implements slog.LogValuer.
@@ -178,12 +459,25 @@ func (dsi DeleteSeriesInput) LogValue() slog.Value {
 		slog.Any("matches", dsi.Matches),
 		slog.String("start_time", dsi.StartTime),
 		slog.String("end_time", dsi.EndTime),
+		slog.Bool("confirm_set", dsi.Confirm != ""),
+	)
+}
+
+// CleanTombstonesInput is the input for the clean tombstones admin tool.
+type CleanTombstonesInput struct {
+	ConfirmationInput
+}
+
+// LogValue implements slog.LogValuer.
+func (cti CleanTombstonesInput) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.Bool("confirm_set", cti.Confirm != ""),
 	)
 }
 
 // SnapshotInput is the input for the snapshot admin tool.
 type SnapshotInput struct {
-	SkipHead bool `json:"skip_head,omitempty" jsonschema:"skip data present in the head block"`
+	SkipHead bool `json:"skip_head,omitempty" jsonschema:"skip data present in the head block, i.e. the most recent samples that are still in memory and not yet compacted into a block on disk, typically the last 2-3 hours. Skipping it makes the snapshot faster and cheaper, but the snapshot then misses the newest data. Defaults to false."`
 }
 
 // LogValue implements slog.LogValuer.
@@ -193,15 +487,50 @@ func (si SnapshotInput) LogValue() slog.Value {
 	)
 }
 
+// AlertmanagerFilterInput is the input for Alertmanager tools that list
+// silences or alerts.
+type AlertmanagerFilterInput struct {
+	Filter []string `json:"filter,omitempty" jsonschema:"label matchers to filter by, e.g. alertname=\"HighLatency\" or job=~\"api.*\""`
+}
+
+// LogValue implements slog.LogValuer.
+func (afi AlertmanagerFilterInput) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.Any("filter", afi.Filter),
+	)
+}
+
+// CreateSilenceInput is the input for the create silence tool.
+type CreateSilenceInput struct {
+	Matchers  []string `json:"matchers" jsonschema:"label matchers selecting the alerts to silence, e.g. alertname=\"HighLatency\" or job=~\"api.*\",required"`
+	Duration  string   `json:"duration" jsonschema:"how long the silence lasts from now, as a duration string (e.g. 30m, 2h, 1d),required"`
+	Comment   string   `json:"comment" jsonschema:"why the alerts are being silenced,required"`
+	CreatedBy string   `json:"created_by,omitempty" jsonschema:"author of the silence. Defaults to prometheus-mcp."`
+}
+
+// LogValue implements slog.LogValuer.
+func (csi CreateSilenceInput) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.Any("matchers", csi.Matchers),
+		slog.String("duration", csi.Duration),
+		slog.String("comment", csi.Comment),
+		slog.String("created_by", csi.CreatedBy),
+	)
+}
+
 // DocsReadInput is the input for the docs read tool.
 type DocsReadInput struct {
-	File string `json:"file" jsonschema:"the name of the documentation file to read"`
+	File      string `json:"file" jsonschema:"the name of the documentation file to read"`
+	StartLine int    `json:"start_line,omitempty" jsonschema:"1-based first line of the file to return, to read large files in parts. Defaults to the first line."`
+	EndLine   int    `json:"end_line,omitempty" jsonschema:"1-based last line of the file to return, inclusive. Defaults to the last line."`
 }
 
 // LogValue implements slog.LogValuer.
 func (dri DocsReadInput) LogValue() slog.Value {
 	return slog.GroupValue(
 		slog.String("file", dri.File),
+		slog.Int("start_line", dri.StartLine),
+		slog.Int("end_line", dri.EndLine),
 	)
 }
 
@@ -218,3 +547,349 @@ func (dsi DocsSearchInput) LogValue() slog.Value {
 		slog.Int("limit", dsi.Limit),
 	)
 }
+
+// PromQLRecipeInput is the input for the PromQL recipe tool.
+type PromQLRecipeInput struct {
+	Goal  string `json:"goal" jsonschema:"a natural-language description of what the query should answer, e.g. 'p99 latency for service X'"`
+	Limit int    `json:"limit,omitempty" jsonschema:"maximum number of related documentation snippets to return. Defaults to 5."`
+}
+
+// LogValue implements slog.LogValuer.
+func (pri PromQLRecipeInput) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("goal", pri.Goal),
+		slog.Int("limit", pri.Limit),
+	)
+}
+
+// ListAlertsInput is the input for the list alerts tool.
+type ListAlertsInput struct {
+	State    string   `json:"state,omitempty" jsonschema:"optional alert state to filter on, one of 'firing', 'pending', or 'inactive'. Defaults to all states."`
+	Matchers []string `json:"matchers,omitempty" jsonschema:"optional label matchers that alerts must all match, e.g. severity=\"critical\" or job=~\"api.*\""`
+}
+
+// LogValue implements slog.LogValuer.
+func (lai ListAlertsInput) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("state", lai.State),
+		slog.Any("matchers", lai.Matchers),
+	)
+}
+
+// ActiveAlertsDetailInput is the input for the active alerts detail tool.
+type ActiveAlertsDetailInput struct {
+	State string `json:"state,omitempty" jsonschema:"optional alert state to filter on, one of 'firing' or 'pending'. Defaults to both."`
+	TruncatableInput
+}
+
+// LogValue implements slog.LogValuer.
+func (aadi ActiveAlertsDetailInput) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("state", aadi.State),
+		slog.Int("truncation_limit", aadi.TruncationLimit),
+	)
+}
+
+// ListRulesInput is the input for the list rules tool.
+type ListRulesInput struct {
+	Type      string `json:"type,omitempty" jsonschema:"optional rule type to filter on, one of 'alerting', 'recording', or 'any'. Defaults to 'any'."`
+	RuleGroup string `json:"rule_group,omitempty" jsonschema:"optional rule group name to filter on"`
+	File      string `json:"file,omitempty" jsonschema:"optional rule file to filter on, either the full path as loaded by Prometheus or just the file name"`
+	// ExcludeAlerts asks Prometheus to omit the active alerts of alerting
+	// rules, which keeps the response small on servers with many firing
+	// alerts.
+	ExcludeAlerts bool `json:"exclude_alerts,omitempty" jsonschema:"optional, omit the active alerts of alerting rules from the response. Defaults to false."`
+}
+
+// LogValue implements slog.LogValuer.
+func (lri ListRulesInput) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("type", lri.Type),
+		slog.String("rule_group", lri.RuleGroup),
+		slog.String("file", lri.File),
+		slog.Bool("exclude_alerts", lri.ExcludeAlerts),
+	)
+}
+
+// RemoteReadInput is the input for the remote read tool.
+type RemoteReadInput struct {
+	Selector string `json:"selector" jsonschema:"series selector of the series to read, e.g. http_requests_total{job=\"api\"},required"`
+	TimeRangeInput
+	TruncatableInput
+}
+
+// LogValue implements slog.LogValuer.
+func (rri RemoteReadInput) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("selector", rri.Selector),
+		slog.String("start_time", rri.StartTime),
+		slog.String("end_time", rri.EndTime),
+		slog.Int("truncation_limit", rri.TruncationLimit),
+	)
+}
+
+// ScrapeTargetInput is the input for the scrape target tool.
+type ScrapeTargetInput struct {
+	URL             string `json:"url" jsonschema:"the scrape URL of the target, as reported in the scrape_url field of list_targets. Only URLs of targets known to Prometheus are allowed."`
+	TruncationLimit int    `json:"truncation_limit,omitempty" jsonschema:"truncation limit for the metrics response in number of lines, set to -1 to disable truncation"`
+}
+
+// LogValue implements slog.LogValuer.
+func (sti ScrapeTargetInput) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("url", sti.URL),
+		slog.Int("truncation_limit", sti.TruncationLimit),
+	)
+}
+
+// ListTargetsInput is the input for the list targets tool.
+type ListTargetsInput struct {
+	ScrapePool string `json:"scrape_pool,omitempty" jsonschema:"optional scrape pool (job name) to filter targets on"`
+	State      string `json:"state,omitempty" jsonschema:"optional target state to filter on, one of 'active', 'dropped', or 'any'. Defaults to 'any'."`
+	Health     string `json:"health,omitempty" jsonschema:"optional target health to filter on, one of 'up', 'down', or 'unknown'. Dropped targets have no health and are excluded when set."`
+}
+
+// LogValue implements slog.LogValuer.
+func (lti ListTargetsInput) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("scrape_pool", lti.ScrapePool),
+		slog.String("state", lti.State),
+		slog.String("health", lti.Health),
+	)
+}
+
+// TargetChurnInput is the input for the target churn tool.
+type TargetChurnInput struct {
+	TruncatableInput
+	TargetInput
+}
+
+// LogValue implements slog.LogValuer.
+func (tci TargetChurnInput) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.Int("truncation_limit", tci.TruncationLimit),
+		slog.String("target", tci.Target),
+	)
+}
+
+// MetricsMissingMetadataInput is the input for the metrics missing metadata tool.
+type MetricsMissingMetadataInput struct {
+	TimeRangeInput
+	TruncatableInput
+}
+
+// LogValue implements slog.LogValuer.
+func (mmmi MetricsMissingMetadataInput) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("start_time", mmmi.StartTime),
+		slog.String("end_time", mmmi.EndTime),
+		slog.Int("truncation_limit", mmmi.TruncationLimit),
+	)
+}
+
+// JobConfigInput is the input for the job config tool.
+type JobConfigInput struct {
+	JobName string `json:"job_name" jsonschema:"the job_name of the scrape config to return,required"`
+}
+
+// LogValue implements slog.LogValuer.
+func (jci JobConfigInput) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("job_name", jci.JobName),
+	)
+}
+
+// ScrapeConfigsInput is the input for the scrape configs tool.
+type ScrapeConfigsInput struct {
+	JobName string `json:"job_name,omitempty" jsonschema:"optional job_name to return only the scrape config of that job. Defaults to all scrape configs."`
+}
+
+// LogValue implements slog.LogValuer.
+func (sci ScrapeConfigsInput) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("job_name", sci.JobName),
+	)
+}
+
+// SampleLimitsInput is the input for the sample limits tool.
+type SampleLimitsInput struct {
+	Threshold float64 `json:"threshold,omitempty" jsonschema:"optional fraction of the sample limit, between 0 and 1, at which a target is flagged as at risk. Defaults to 0.8."`
+	TruncatableInput
+}
+
+// LogValue implements slog.LogValuer.
+func (sli SampleLimitsInput) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.Float64("threshold", sli.Threshold),
+		slog.Int("truncation_limit", sli.TruncationLimit),
+	)
+}
+
+// FleetHealthInput is the input for the fleet health tool.
+type FleetHealthInput struct {
+	GroupBy   string  `json:"group_by" jsonschema:"the target label to group targets by, e.g. namespace or team,required"`
+	Threshold float64 `json:"threshold,omitempty" jsonschema:"optional percentage of targets up, between 0 and 100, below which a group is flagged. Defaults to 90."`
+	TruncatableInput
+	TargetInput
+}
+
+// LogValue implements slog.LogValuer.
+func (fhi FleetHealthInput) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("group_by", fhi.GroupBy),
+		slog.Float64("threshold", fhi.Threshold),
+		slog.Int("truncation_limit", fhi.TruncationLimit),
+		slog.String("target", fhi.Target),
+	)
+}
+
+// DetectGapsInput is the input for the detect gaps tool.
+type DetectGapsInput struct {
+	Selector   string `json:"selector" jsonschema:"the series selector to check for gaps, e.g. up{job=\"node\"},required"`
+	Resolution string `json:"resolution" jsonschema:"the expected interval between samples in Go duration format (e.g. '15s', '1m'), usually the scrape interval. Consecutive samples more than one and a half intervals apart are reported as gaps.,required"`
+	TimeRangeInput
+	TruncatableInput
+	TargetInput
+}
+
+// LogValue implements slog.LogValuer.
+func (dgi DetectGapsInput) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("selector", dgi.Selector),
+		slog.String("resolution", dgi.Resolution),
+		slog.String("start_time", dgi.StartTime),
+		slog.String("end_time", dgi.EndTime),
+		slog.Int("truncation_limit", dgi.TruncationLimit),
+		slog.String("target", dgi.Target),
+	)
+}
+
+// WhyNoDataInput is the input for the why no data tool.
+type WhyNoDataInput struct {
+	Selector string `json:"selector" jsonschema:"the metric name or series selector that returns no data, e.g. up{job=\"node\"},required"`
+	Lookback string `json:"lookback,omitempty" jsonschema:"how far back to look for series that existed but went stale, in Go duration format (e.g. '1h', '1d'). Defaults to 1h."`
+	TargetInput
+}
+
+// LogValue implements slog.LogValuer.
+func (wndi WhyNoDataInput) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("selector", wndi.Selector),
+		slog.String("lookback", wndi.Lookback),
+		slog.String("target", wndi.Target),
+	)
+}
+
+// VMCardinalityInput is the input for the VictoriaMetrics cardinality tool.
+type VMCardinalityInput struct {
+	TopN       int    `json:"top_n,omitempty" jsonschema:"optional number of entries to return in each top list. Defaults to 10."`
+	Date       string `json:"date,omitempty" jsonschema:"optional day to get stats for in YYYY-MM-DD format. Defaults to today. Use 1970-01-01 to get stats across all days."`
+	Match      string `json:"match,omitempty" jsonschema:"optional series selector to only count matching series, e.g. {job=\"node\"}"`
+	FocusLabel string `json:"focus_label,omitempty" jsonschema:"optional label name to also list the values of with the most series"`
+	TargetInput
+}
+
+// LogValue implements slog.LogValuer.
+func (vci VMCardinalityInput) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.Int("top_n", vci.TopN),
+		slog.String("date", vci.Date),
+		slog.String("match", vci.Match),
+		slog.String("focus_label", vci.FocusLabel),
+		slog.String("target", vci.Target),
+	)
+}
+
+// ListTenantsInput is the input for the Mimir tenant listing tool.
+type ListTenantsInput struct {
+	TargetInput
+}
+
+// LogValue implements slog.LogValuer.
+func (lti ListTenantsInput) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("target", lti.Target),
+	)
+}
+
+// TestRelabelInput is the input for the test relabel tool.
+type TestRelabelInput struct {
+	Labels map[string]string `json:"labels" jsonschema:"the label set of the sample target to relabel, including any __meta_* or other internal labels,required"`
+	Config string            `json:"config" jsonschema:"relabel config as YAML, either a list of relabel_configs/metric_relabel_configs entries or a single entry,required"`
+}
+
+// LogValue implements slog.LogValuer.
+func (tri TestRelabelInput) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.Any("labels", tri.Labels),
+		slog.String("config", tri.Config),
+	)
+}
+
+// ExamplesInput is the input for the PromQL examples tool.
+type ExamplesInput struct {
+	Query string `json:"query,omitempty" jsonschema:"optional search query used to find documentation containing relevant examples. If unset, examples from all documentation files are returned."`
+	Limit int    `json:"limit,omitempty" jsonschema:"maximum number of examples to return. Defaults to 50."`
+}
+
+// LogValue implements slog.LogValuer.
+func (ei ExamplesInput) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("query", ei.Query),
+		slog.Int("limit", ei.Limit),
+	)
+}
+
+// WaitReadyInput is the input for the wait ready tool.
+type WaitReadyInput struct {
+	Timeout          string `json:"timeout,omitempty" jsonschema:"maximum time to wait for Prometheus to become ready, as a duration (e.g. '30s', '5m'). Defaults to 1m, at most 10m."`
+	PollInterval     string `json:"poll_interval,omitempty" jsonschema:"time between readiness checks, as a duration (e.g. '5s'). Defaults to 2s, at least 100ms."`
+	IncludeWALReplay bool   `json:"include_wal_replay,omitempty" jsonschema:"whether to also fetch the WAL replay progress while Prometheus is not ready. Defaults to false."`
+}
+
+// LogValue implements slog.LogValuer.
+func (wri WaitReadyInput) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("timeout", wri.Timeout),
+		slog.String("poll_interval", wri.PollInterval),
+		slog.Bool("include_wal_replay", wri.IncludeWALReplay),
+	)
+}
+
+// QueryResultOutput is the structured output of the query and range query
+// tools, returned alongside the text output for clients that support
+// structured tool output.
+type QueryResultOutput struct {
+	ResultType string        `json:"result_type" jsonschema:"type of the query result: vector, matrix, scalar, or string"`
+	Series     []QuerySeries `json:"series,omitempty" jsonschema:"series of vector and matrix results, in the same order as in the text output"`
+	Sample     *QuerySample  `json:"sample,omitempty" jsonschema:"value of scalar and string results"`
+	Warnings   []string      `json:"warnings,omitempty" jsonschema:"warnings returned by Prometheus for the query"`
+	Notes      []string      `json:"notes,omitempty" jsonschema:"advisory notes on how offset and @ modifiers in the query interact with the requested time or range"`
+	Range      *QueryRange   `json:"range,omitempty" jsonschema:"evaluation steps of a range query, which determine the resolution of the result"`
+	Truncated  bool          `json:"truncated,omitempty" jsonschema:"whether the result was truncated, in which case only the series shown in full in the text output are included"`
+}
+
+// QueryRange describes the evaluation steps of a range query. Prometheus
+// evaluates the query at start and every step after it, up to and including
+// end.
+type QueryRange struct {
+	Start       float64 `json:"start" jsonschema:"timestamp of the first evaluation step in Unix epoch seconds, at millisecond precision"`
+	End         float64 `json:"end" jsonschema:"timestamp of the last evaluation step in Unix epoch seconds. This is the requested end time aligned down to the last step that fits into the range."`
+	Step        string  `json:"step" jsonschema:"resolution of the result, either the requested step or the step calculated from the time range"`
+	StepSeconds float64 `json:"step_seconds" jsonschema:"resolution of the result in seconds"`
+	Steps       int64   `json:"steps" jsonschema:"number of evaluation steps, which is the maximum number of samples per series"`
+}
+
+// QuerySeries is a series of a query result.
+type QuerySeries struct {
+	Metric map[string]string `json:"metric" jsonschema:"labels of the series"`
+	Value  *QuerySample      `json:"value,omitempty" jsonschema:"sample of an instant vector series"`
+	Values []QuerySample     `json:"values,omitempty" jsonschema:"samples of a range vector series, in timestamp order"`
+}
+
+// QuerySample is a sample of a query result.
+type QuerySample struct {
+	Timestamp float64 `json:"timestamp" jsonschema:"timestamp of the sample in Unix epoch seconds"`
+	Value     string  `json:"value,omitempty" jsonschema:"float value of the sample, as a string like in the Prometheus HTTP API so NaN and infinite values can be represented. Also the value of string results."`
+	Histogram string  `json:"histogram,omitempty" jsonschema:"native histogram value of the sample, in the same format as in the text output"`
+}
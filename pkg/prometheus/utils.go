@@ -17,6 +17,7 @@ import (
 	"fmt"
 	"math"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/prometheus/common/model"
@@ -84,11 +85,18 @@ func ParseTimestamp(s string) (time.Time, error) {
 // formats and possibly make shell calls to `date`/`python` for time handling,
 // etc. Accepting duration strings from the get go avoids a lot of LLM
 // confusion and failed/extra tool calls.
+//
+// For the same reason, Grafana-style relative time expressions are accepted
+// as well, see parseRelativeTime.
 func ParseTimestampOrDuration(s string) (time.Time, error) {
 	if t, err := ParseTimestamp(s); err == nil {
 		return t, nil
 	}
 
+	if t, ok, err := parseRelativeTime(s, time.Now()); ok {
+		return t, err
+	}
+
 	if dur, err := model.ParseDuration(s); err == nil {
 		// Durations always represent a time in the past relative to now.
 		// Both "5m" and "-5m" mean "5 minutes ago" -- we normalize
@@ -103,3 +111,46 @@ func ParseTimestampOrDuration(s string) (time.Time, error) {
 
 	return time.Time{}, fmt.Errorf("cannot parse %q to a valid timestamp or duration", s)
 }
+
+// parseRelativeTime parses Grafana-style relative time expressions: `now`,
+// optionally followed by durations to add or subtract, such as `now-1h` or
+// `now-30m+5m`, and `today` for the start of the current day in UTC. The
+// returned bool reports whether s is a relative time expression at all, so
+// that malformed expressions like `now-1x` get a specific error.
+func parseRelativeTime(s string, now time.Time) (time.Time, bool, error) {
+	now = now.UTC()
+	if s == "today" {
+		return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC), true, nil
+	}
+
+	rest, ok := strings.CutPrefix(s, "now")
+	if !ok {
+		return time.Time{}, false, nil
+	}
+
+	t := now
+	for rest != "" {
+		sign := rest[0]
+		if sign != '+' && sign != '-' {
+			return time.Time{}, true, fmt.Errorf("cannot parse %q to a valid relative time: expected '+' or '-' after %q", s, strings.TrimSuffix(s, rest))
+		}
+		rest = rest[1:]
+
+		end := strings.IndexAny(rest, "+-")
+		if end < 0 {
+			end = len(rest)
+		}
+		dur, err := model.ParseDuration(rest[:end])
+		if err != nil {
+			return time.Time{}, true, fmt.Errorf("cannot parse %q to a valid relative time: %w", s, err)
+		}
+		if sign == '-' {
+			t = t.Add(-time.Duration(dur))
+		} else {
+			t = t.Add(time.Duration(dur))
+		}
+		rest = rest[end:]
+	}
+
+	return t, true, nil
+}
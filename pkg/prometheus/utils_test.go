@@ -141,10 +141,145 @@ func TestParseTimestampOrDuration(t *testing.T) {
 		})
 	}
 
+	// Test relative time expressions (offset from now).
+	relativeCases := []struct {
+		name   string
+		input  string
+		offset time.Duration
+	}{
+		{
+			name:   "now",
+			input:  "now",
+			offset: 0,
+		},
+		{
+			name:   "now minus 1 hour",
+			input:  "now-1h",
+			offset: -1 * time.Hour,
+		},
+		{
+			name:   "now minus 30 minutes plus 5 minutes",
+			input:  "now-30m+5m",
+			offset: -25 * time.Minute,
+		},
+	}
+
+	for _, tc := range relativeCases {
+		t.Run(tc.name, func(t *testing.T) {
+			before := time.Now()
+			got, err := ParseTimestampOrDuration(tc.input)
+			after := time.Now()
+
+			require.NoError(t, err)
+			require.False(t, got.Before(before.Add(tc.offset)),
+				"result %v should not be before %v (expected earliest)", got, before.Add(tc.offset))
+			require.False(t, got.After(after.Add(tc.offset)),
+				"result %v should not be after %v (expected latest)", got, after.Add(tc.offset))
+		})
+	}
+
 	// Test invalid input.
-	t.Run("Invalid input", func(t *testing.T) {
-		_, err := ParseTimestampOrDuration("not-a-timestamp-or-duration")
-		require.Error(t, err)
-		require.Contains(t, err.Error(), "cannot parse")
-	})
+	invalidCases := []string{
+		"not-a-timestamp-or-duration",
+		"yesterday",
+		"now-",
+		"now-abc",
+		"now*1h",
+		"now-1h+",
+	}
+
+	for _, input := range invalidCases {
+		t.Run("Invalid input "+input, func(t *testing.T) {
+			_, err := ParseTimestampOrDuration(input)
+			require.Error(t, err)
+			require.Contains(t, err.Error(), "cannot parse")
+		})
+	}
+}
+
+func TestParseRelativeTime(t *testing.T) {
+	now := time.Date(2024, 3, 15, 13, 45, 30, 0, time.UTC)
+
+	testCases := []struct {
+		name         string
+		input        string
+		expectedOK   bool
+		expectedTime time.Time
+		expectError  bool
+	}{
+		{
+			name:         "now",
+			input:        "now",
+			expectedOK:   true,
+			expectedTime: now,
+		},
+		{
+			name:         "now minus duration",
+			input:        "now-1h",
+			expectedOK:   true,
+			expectedTime: time.Date(2024, 3, 15, 12, 45, 30, 0, time.UTC),
+		},
+		{
+			name:         "now plus duration",
+			input:        "now+1d",
+			expectedOK:   true,
+			expectedTime: time.Date(2024, 3, 16, 13, 45, 30, 0, time.UTC),
+		},
+		{
+			name:         "chained offsets",
+			input:        "now-30m+5m",
+			expectedOK:   true,
+			expectedTime: time.Date(2024, 3, 15, 13, 20, 30, 0, time.UTC),
+		},
+		{
+			name:         "compound duration",
+			input:        "now-1h30m",
+			expectedOK:   true,
+			expectedTime: time.Date(2024, 3, 15, 12, 15, 30, 0, time.UTC),
+		},
+		{
+			name:         "today",
+			input:        "today",
+			expectedOK:   true,
+			expectedTime: time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "not a relative time",
+			input:      "1h",
+			expectedOK: false,
+		},
+		{
+			name:        "missing duration",
+			input:       "now-",
+			expectedOK:  true,
+			expectError: true,
+		},
+		{
+			name:        "invalid duration",
+			input:       "now-1x",
+			expectedOK:  true,
+			expectError: true,
+		},
+		{
+			name:        "invalid operator",
+			input:       "now*2h",
+			expectedOK:  true,
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok, err := parseRelativeTime(tc.input, now)
+			require.Equal(t, tc.expectedOK, ok)
+			if tc.expectError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			if ok {
+				require.True(t, tc.expectedTime.Equal(got), "expected times to be equal", "expected", tc.expectedTime, "got", got)
+			}
+		})
+	}
 }